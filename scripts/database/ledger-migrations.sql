@@ -139,3 +139,52 @@ BEGIN
     RETURN v_entry_id;
 END;
 $$ LANGUAGE plpgsql;
+
+-- Ledger event streams (append-only, one stream per account)
+CREATE TABLE IF NOT EXISTS ledger_events (
+    id VARCHAR(64) PRIMARY KEY,
+    account_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL CHECK (version > 0),
+    type VARCHAR(32) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT ledger_events_account_version_key UNIQUE (account_id, version)
+);
+
+-- Signed chain checkpoints; events after a checkpoint link to its hash
+CREATE TABLE IF NOT EXISTS ledger_checkpoints (
+    account_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL CHECK (version > 0),
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (account_id, version)
+);
+
+-- Event IDs produced by idempotent batches, keyed by the client's batch key
+CREATE TABLE IF NOT EXISTS ledger_batch_manifests (
+    batch_key VARCHAR(255) PRIMARY KEY,
+    event_ids JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Lookups of events referencing another event
+CREATE INDEX IF NOT EXISTS idx_ledger_events_reference_id ON ledger_events ((payload->>'referenceId'));
+CREATE INDEX IF NOT EXISTS idx_ledger_events_references ON ledger_events USING GIN ((payload->'references') jsonb_path_ops);
+
+-- Projection states and the stream version each has consumed up to, committed together
+CREATE TABLE IF NOT EXISTS ledger_projection_states (
+    projection VARCHAR(128) NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL CHECK (version >= 0),
+    state JSONB NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (projection, account_id)
+);
+
+CREATE TABLE IF NOT EXISTS ledger_projection_cursors (
+    projection VARCHAR(128) NOT NULL,
+    account_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL CHECK (version >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (projection, account_id)
+);
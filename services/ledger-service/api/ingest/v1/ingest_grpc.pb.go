@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: ingest/v1/ingest.proto
+
+package ingestv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	IngestService_Ingest_FullMethodName = "/ledger.ingest.v1.IngestService/Ingest"
+)
+
+// IngestServiceClient is the client API for IngestService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type IngestServiceClient interface {
+	// Ingest receives events and answers each one, in order, with an ack or nack.
+	// The server stops reading while earlier events are still being appended, so
+	// a slow store pushes back on the client through gRPC flow control.
+	Ingest(ctx context.Context, opts ...grpc.CallOption) (IngestService_IngestClient, error)
+}
+
+type ingestServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIngestServiceClient(cc grpc.ClientConnInterface) IngestServiceClient {
+	return &ingestServiceClient{cc}
+}
+
+func (c *ingestServiceClient) Ingest(ctx context.Context, opts ...grpc.CallOption) (IngestService_IngestClient, error) {
+	stream, err := c.cc.NewStream(ctx, &IngestService_ServiceDesc.Streams[0], IngestService_Ingest_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ingestServiceIngestClient{stream}
+	return x, nil
+}
+
+type IngestService_IngestClient interface {
+	Send(*IngestRequest) error
+	Recv() (*IngestResponse, error)
+	grpc.ClientStream
+}
+
+type ingestServiceIngestClient struct {
+	grpc.ClientStream
+}
+
+func (x *ingestServiceIngestClient) Send(m *IngestRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ingestServiceIngestClient) Recv() (*IngestResponse, error) {
+	m := new(IngestResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IngestServiceServer is the server API for IngestService service.
+// All implementations must embed UnimplementedIngestServiceServer
+// for forward compatibility
+type IngestServiceServer interface {
+	// Ingest receives events and answers each one, in order, with an ack or nack.
+	// The server stops reading while earlier events are still being appended, so
+	// a slow store pushes back on the client through gRPC flow control.
+	Ingest(IngestService_IngestServer) error
+	mustEmbedUnimplementedIngestServiceServer()
+}
+
+// UnimplementedIngestServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedIngestServiceServer struct {
+}
+
+func (UnimplementedIngestServiceServer) Ingest(IngestService_IngestServer) error {
+	return status.Errorf(codes.Unimplemented, "method Ingest not implemented")
+}
+func (UnimplementedIngestServiceServer) mustEmbedUnimplementedIngestServiceServer() {}
+
+// UnsafeIngestServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IngestServiceServer will
+// result in compilation errors.
+type UnsafeIngestServiceServer interface {
+	mustEmbedUnimplementedIngestServiceServer()
+}
+
+func RegisterIngestServiceServer(s grpc.ServiceRegistrar, srv IngestServiceServer) {
+	s.RegisterService(&IngestService_ServiceDesc, srv)
+}
+
+func _IngestService_Ingest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngestServiceServer).Ingest(&ingestServiceIngestServer{stream})
+}
+
+type IngestService_IngestServer interface {
+	Send(*IngestResponse) error
+	Recv() (*IngestRequest, error)
+	grpc.ServerStream
+}
+
+type ingestServiceIngestServer struct {
+	grpc.ServerStream
+}
+
+func (x *ingestServiceIngestServer) Send(m *IngestResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ingestServiceIngestServer) Recv() (*IngestRequest, error) {
+	m := new(IngestRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// IngestService_ServiceDesc is the grpc.ServiceDesc for IngestService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IngestService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ledger.ingest.v1.IngestService",
+	HandlerType: (*IngestServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Ingest",
+			Handler:       _IngestService_Ingest_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ingest/v1/ingest.proto",
+}
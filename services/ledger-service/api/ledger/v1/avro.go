@@ -0,0 +1,9 @@
+package ledgerv1
+
+import _ "embed"
+
+// LedgerEventAvroSchema is the Avro schema of a ledger event, as registered with the schema
+// registry for the event topics
+//
+//go:embed ledger_event.avsc
+var LedgerEventAvroSchema string
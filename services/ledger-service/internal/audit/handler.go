@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+// DefaultPageSize is the number of events served per page
+const DefaultPageSize = 100
+
+var (
+	// ErrUnauthorized is returned by an Authenticator that rejects a request
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidCursor is returned for a cursor the handler did not issue, or issued for another account
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Authenticator decides whether a request may read the account's history
+type Authenticator func(r *http.Request, accountID string) error
+
+// BearerTokens authenticates requests carrying one of tokens as an Authorization bearer token
+func BearerTokens(tokens ...string) Authenticator {
+	return func(r *http.Request, _ string) error {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			return ErrUnauthorized
+		}
+		for _, token := range tokens {
+			if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return ErrUnauthorized
+	}
+}
+
+// Handler serves GET /accounts/{id}/events?cursor=... with signed, independently verifiable
+// pages of the account's history
+type Handler struct {
+	store        store.EventStore
+	signer       models.Signer
+	authenticate Authenticator
+	pageSize     int
+	cursorKey    []byte
+	logger       logrus.FieldLogger
+}
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithPageSize sets the number of events served per page
+func WithPageSize(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.pageSize = n
+		}
+	}
+}
+
+// WithCursorKey sets the secret cursors are authenticated with. Replicas serving the same
+// auditors must share it; without it each handler uses a random key and its cursors are only
+// valid against that handler.
+func WithCursorKey(secret []byte) Option {
+	return func(h *Handler) {
+		if len(secret) > 0 {
+			h.cursorKey = secret
+		}
+	}
+}
+
+// WithLogger sets the logger used for failed requests
+func WithLogger(logger logrus.FieldLogger) Option {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// NewHandler creates a handler serving the store's account streams, signing each page with
+// signer, to requests authenticate accepts
+func NewHandler(eventStore store.EventStore, signer models.Signer, authenticate Authenticator, opts ...Option) (*Handler, error) {
+	h := &Handler{
+		store:        eventStore,
+		signer:       signer,
+		authenticate: authenticate,
+		pageSize:     DefaultPageSize,
+		logger:       logrus.StandardLogger(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.cursorKey == nil {
+		h.cursorKey = make([]byte, 32)
+		if _, err := rand.Read(h.cursorKey); err != nil {
+			return nil, fmt.Errorf("failed to generate cursor key: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP answers with the requested page as JSON
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := accountPath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+	if err := h.authenticate(r, accountID); err != nil {
+		writeError(w, http.StatusUnauthorized, ErrUnauthorized)
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	fromVersion := int64(0)
+	if cursor != "" {
+		var err error
+		if fromVersion, err = h.decodeCursor(accountID, cursor); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	page, err := h.page(r, accountID, cursor, fromVersion)
+	if err != nil {
+		h.logger.WithError(err).WithField("accountId", accountID).Error("failed to serve audit page")
+		writeError(w, http.StatusInternalServerError, errors.New("failed to load events"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		h.logger.WithError(err).WithField("accountId", accountID).Warn("failed to write audit page")
+	}
+}
+
+// page reads the account's events from fromVersion and signs the first pageSize of them
+func (h *Handler) page(r *http.Request, accountID, cursor string, fromVersion int64) (models.AuditPage, error) {
+	events, err := h.store.Read(r.Context(), accountID, fromVersion)
+	if err != nil {
+		return models.AuditPage{}, err
+	}
+
+	page := models.AuditPage{AccountID: accountID, Cursor: cursor, Events: events}
+	if len(events) > h.pageSize {
+		page.Events = events[:h.pageSize]
+		page.NextCursor = h.encodeCursor(accountID, events[h.pageSize].Version)
+	}
+	if err := models.SignAuditPage(&page, h.signer); err != nil {
+		return models.AuditPage{}, err
+	}
+	return page, nil
+}
+
+// encodeCursor returns an opaque cursor resuming the account's history at fromVersion. It
+// carries a MAC so a client cannot forge one, or reuse one against another account.
+func (h *Handler) encodeCursor(accountID string, fromVersion int64) string {
+	version := strconv.FormatInt(fromVersion, 10)
+	return version + "." + base64.RawURLEncoding.EncodeToString(h.cursorMAC(accountID, version))
+}
+
+// decodeCursor validates a cursor issued for the account and returns its version
+func (h *Handler) decodeCursor(accountID, cursor string) (int64, error) {
+	version, mac, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(decoded, h.cursorMAC(accountID, version)) {
+		return 0, ErrInvalidCursor
+	}
+	fromVersion, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || fromVersion < 1 {
+		return 0, ErrInvalidCursor
+	}
+	return fromVersion, nil
+}
+
+// cursorMAC authenticates a cursor's version for the account
+func (h *Handler) cursorMAC(accountID, version string) []byte {
+	mac := hmac.New(sha256.New, h.cursorKey)
+	mac.Write([]byte(accountID))
+	mac.Write([]byte{0})
+	mac.Write([]byte(version))
+	return mac.Sum(nil)
+}
+
+// accountPath extracts the account ID from /accounts/{id}/events
+func accountPath(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/accounts/")
+	if !ok {
+		return "", false
+	}
+	accountID, ok := strings.CutSuffix(rest, "/events")
+	if !ok || accountID == "" || strings.Contains(accountID, "/") {
+		return "", false
+	}
+	return accountID, true
+}
+
+// writeError answers with status and a JSON error body
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
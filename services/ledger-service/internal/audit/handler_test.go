@@ -0,0 +1,116 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+var pageKey = models.NewHMACKey("audit-2026", []byte("secret"))
+
+func startHandler(t *testing.T, events int) *httptest.Server {
+	t.Helper()
+
+	eventStore := store.NewMemoryStore()
+	for version := int64(1); version <= int64(events); version++ {
+		event := models.NewLedgerEvent(models.Credit, models.NewMoney(version*100, "USD", 2), "acc_1", "corr_1").
+			WithVersion(version)
+		require.NoError(t, eventStore.Append(context.Background(), event))
+	}
+
+	handler, err := NewHandler(eventStore, pageKey, BearerTokens("auditor-token"), WithPageSize(2))
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, server *httptest.Server, path, cursor string) *http.Response {
+	t.Helper()
+	if cursor != "" {
+		path += "?cursor=" + url.QueryEscape(cursor)
+	}
+	req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer auditor-token")
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func getPage(t *testing.T, server *httptest.Server, cursor string) models.AuditPage {
+	t.Helper()
+	resp := get(t, server, "/accounts/acc_1/events", cursor)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var page models.AuditPage
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&page))
+	return page
+}
+
+func TestHandlerServesVerifiablePages(t *testing.T) {
+	server := startHandler(t, 5)
+
+	var versions []int64
+	cursor := ""
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+		page := getPage(t, server, cursor)
+		require.NoError(t, models.VerifyAuditPage(page, pageKey))
+		for _, event := range page.Events {
+			versions = append(versions, event.Version)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, versions)
+}
+
+func TestHandlerPageTamperingFailsVerification(t *testing.T) {
+	server := startHandler(t, 3)
+
+	page := getPage(t, server, "")
+	page.Events[1].Amount.MinorUnits = 100000
+	assert.ErrorIs(t, models.VerifyAuditPage(page, pageKey), models.ErrPageTampered)
+
+	page = getPage(t, server, "")
+	page.Events = page.Events[:1]
+	assert.ErrorIs(t, models.VerifyAuditPage(page, pageKey), models.ErrPageTampered)
+
+	page = getPage(t, server, "")
+	page.NextCursor = ""
+	assert.ErrorIs(t, models.VerifyAuditPage(page, pageKey), models.ErrInvalidSignature)
+}
+
+func TestHandlerRejectsTamperedCursor(t *testing.T) {
+	server := startHandler(t, 5)
+	next := getPage(t, server, "").NextCursor
+	require.NotEmpty(t, next)
+
+	for _, cursor := range []string{"4" + next[1:], next + "x", "garbage"} {
+		assert.Equal(t, http.StatusBadRequest, get(t, server, "/accounts/acc_1/events", cursor).StatusCode, cursor)
+	}
+	assert.Equal(t, http.StatusBadRequest, get(t, server, "/accounts/acc_2/events", next).StatusCode)
+}
+
+func TestHandlerRequiresAuthentication(t *testing.T) {
+	server := startHandler(t, 1)
+
+	resp, err := server.Client().Get(server.URL + "/accounts/acc_1/events")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	assert.Equal(t, http.StatusNotFound, get(t, server, "/accounts/acc_1", "").StatusCode)
+}
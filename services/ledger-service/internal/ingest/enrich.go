@@ -0,0 +1,56 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+
+	"fintech-platform/ledger-service/internal/currency"
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// ErrPrecisionMismatch is returned when an amount's precision contradicts its currency
+var ErrPrecisionMismatch = errors.New("precision contradicts currency")
+
+// Enricher fills in or normalizes fields of a decoded event before it is validated.
+// Returning an error rejects the event.
+type Enricher func(event *models.LedgerEvent) error
+
+// FillPrecision raises a zero Money.Precision on the event amount and fee components to the
+// currency table's, keeping the value, so producers that omit it do not record 2-decimal
+// currencies at precision 0.
+// A non-zero precision that differs from the currency's is rejected. Signed events are never
+// modified, since the precision is covered by the signature; a missing precision on a signed
+// event is rejected instead. Unknown currencies are left alone.
+func FillPrecision(event *models.LedgerEvent) error {
+	if err := fillPrecision(&event.Amount, event.Signature != ""); err != nil {
+		return err
+	}
+	if event.Fees != nil {
+		for i := range event.Fees.Components {
+			if err := fillPrecision(&event.Fees.Components[i].Amount, event.Signature != ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fillPrecision checks or fills a single amount's precision
+func fillPrecision(amount *models.Money, signed bool) error {
+	precision, ok := currency.Precision(amount.Currency)
+	if !ok || amount.Precision == precision {
+		return nil
+	}
+	if amount.Precision != 0 {
+		return fmt.Errorf("%w: %s has precision %d, got %d", ErrPrecisionMismatch, amount.Currency, precision, amount.Precision)
+	}
+	if signed {
+		return fmt.Errorf("%w: signed %s amount is missing its precision", ErrPrecisionMismatch, amount.Currency)
+	}
+	rescaled, err := amount.Rescale(precision)
+	if err != nil {
+		return err
+	}
+	*amount = rescaled
+	return nil
+}
@@ -0,0 +1,92 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ingestv1 "fintech-platform/ledger-service/api/ingest/v1"
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+func TestFillPrecision(t *testing.T) {
+	event := models.NewLedgerEvent(models.Credit, models.NewMoney(10, "USD", 0), "acc_1", "corr_1")
+	require.NoError(t, FillPrecision(event))
+	assert.Equal(t, models.NewMoney(1000, "USD", 2), event.Amount)
+
+	contradictory := models.NewLedgerEvent(models.Credit, models.NewMoney(10000, "USD", 3), "acc_1", "corr_1")
+	assert.ErrorIs(t, FillPrecision(contradictory), ErrPrecisionMismatch)
+
+	signed := models.NewLedgerEvent(models.Credit, models.NewMoney(10, "USD", 0), "acc_1", "corr_1")
+	require.NoError(t, signed.Sign("secret"))
+	assert.ErrorIs(t, FillPrecision(signed), ErrPrecisionMismatch)
+
+	yen := models.NewLedgerEvent(models.Credit, models.NewMoney(500, "JPY", 0), "acc_1", "corr_1")
+	assert.NoError(t, FillPrecision(yen))
+}
+
+func TestIngestFillsPrecision(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore, WithEnrichers(FillPrecision))
+
+	stream, err := client.Ingest(context.Background())
+	require.NoError(t, err)
+
+	missing := models.NewLedgerEvent(models.Credit, models.NewMoney(10, "USD", 0), "acc_1", "corr_1")
+	require.NoError(t, stream.Send(eventRequest(t, "req_1", missing)))
+	contradictory := models.NewLedgerEvent(models.Credit, models.NewMoney(100000, "USD", 4), "acc_1", "corr_1").WithVersion(2)
+	require.NoError(t, stream.Send(eventRequest(t, "req_2", contradictory)))
+	require.NoError(t, stream.CloseSend())
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_NACK, resp.Status)
+	assert.Contains(t, resp.Error, ErrPrecisionMismatch.Error())
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, models.NewMoney(1000, "USD", 2), stored[0].Amount)
+}
+
+func TestIngestKeepsFractionalAmounts(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore, WithEnrichers(FillPrecision))
+
+	stream, err := client.Ingest(context.Background())
+	require.NoError(t, err)
+
+	withAmount := func(requestID string, event *models.LedgerEvent, amount string) *ingestv1.IngestRequest {
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(eventRequest(t, requestID, event).Event, &fields))
+		fields["amount"] = json.RawMessage(amount)
+		payload, err := json.Marshal(fields)
+		require.NoError(t, err)
+		return &ingestv1.IngestRequest{RequestId: requestID, Event: payload}
+	}
+	fractional := models.NewLedgerEvent(models.Credit, models.NewMoney(1025, "USD", 2), "acc_1", "corr_1")
+	require.NoError(t, stream.Send(withAmount("req_1", fractional, `{"amount":10.25,"currency":"USD"}`)))
+	truncated := models.NewLedgerEvent(models.Credit, models.NewMoney(1025, "USD", 2), "acc_1", "corr_2").WithVersion(2)
+	require.NoError(t, stream.Send(withAmount("req_2", truncated, `{"amount":10.25,"currency":"USD","precision":0}`)))
+	require.NoError(t, stream.CloseSend())
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_NACK, resp.Status, "10.25 must not be stored as 10.00")
+	assert.Contains(t, resp.Error, models.ErrPrecisionLoss.Error())
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, models.NewMoney(1025, "USD", 2), stored[0].Amount)
+}
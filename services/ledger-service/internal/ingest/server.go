@@ -0,0 +1,207 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	ingestv1 "fintech-platform/ledger-service/api/ingest/v1"
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+// ErrRetryMismatch is returned when a resubmitted event ID is already stored with other content
+var ErrRetryMismatch = errors.New("event differs from the one stored under its ID")
+
+// DefaultMaxInFlight is the number of received events buffered ahead of the store
+const DefaultMaxInFlight = 64
+
+// Server implements the IngestService gRPC stream on top of an EventStore
+type Server struct {
+	ingestv1.UnimplementedIngestServiceServer
+
+	store       store.EventStore
+	maxInFlight int
+	enrichers   []Enricher
+	logger      logrus.FieldLogger
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithMaxInFlight bounds how many events are read off a stream before they are appended.
+// Once the buffer is full the server stops reading, and gRPC flow control blocks the client.
+func WithMaxInFlight(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxInFlight = n
+		}
+	}
+}
+
+// WithEnrichers runs the enrichers, in order, on every decoded event before it is validated
+func WithEnrichers(enrichers ...Enricher) Option {
+	return func(s *Server) {
+		s.enrichers = append(s.enrichers, enrichers...)
+	}
+}
+
+// WithLogger sets the logger used for stream lifecycle messages
+func WithLogger(logger logrus.FieldLogger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// NewServer creates an ingest server appending to the given store
+func NewServer(eventStore store.EventStore, opts ...Option) *Server {
+	s := &Server{
+		store:       eventStore,
+		maxInFlight: DefaultMaxInFlight,
+		logger:      logrus.StandardLogger(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Ingest validates and appends each received event, answering with an ack or nack in receive order
+func (s *Server) Ingest(stream ingestv1.IngestService_IngestServer) error {
+	ctx := stream.Context()
+	pending := make(chan *ingestv1.IngestRequest, s.maxInFlight)
+	recvErr := make(chan error, 1)
+
+	go func() {
+		defer close(pending)
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					recvErr <- err
+				}
+				return
+			}
+			select {
+			case pending <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for req := range pending {
+		resp := s.handle(ctx, req)
+		if err := stream.Send(resp); err != nil {
+			// Appended events stay durable; a client resubmitting them after
+			// reconnecting is acked again through the duplicate path.
+			s.logger.WithError(err).WithField("requestId", req.GetRequestId()).
+				Warn("ingest stream closed before ack was delivered")
+			return err
+		}
+	}
+
+	select {
+	case err := <-recvErr:
+		s.logger.WithError(err).Info("ingest stream closed by client")
+		return err
+	default:
+		return nil
+	}
+}
+
+// handle enriches, validates and appends a single event, returning its ack or nack
+func (s *Server) handle(ctx context.Context, req *ingestv1.IngestRequest) *ingestv1.IngestResponse {
+	resp := &ingestv1.IngestResponse{RequestId: req.GetRequestId()}
+
+	event, err := models.LedgerEventFromJSON(req.GetEvent())
+	if err != nil {
+		return nack(resp, err)
+	}
+	resp.EventId = event.ID
+
+	for _, enrich := range s.enrichers {
+		if err := enrich(event); err != nil {
+			return nack(resp, err)
+		}
+	}
+	if err := event.Validate(); err != nil {
+		return nack(resp, err)
+	}
+
+	err = s.store.Append(ctx, event)
+	switch {
+	case errors.Is(err, store.ErrDuplicateEvent):
+		stored, findErr := s.find(ctx, event.AccountID, func(e *models.LedgerEvent) bool { return e.ID == event.ID })
+		if findErr != nil {
+			return nack(resp, findErr)
+		}
+		if stored == nil || !sameEvent(stored, event) {
+			return nack(resp, fmt.Errorf("%w: %s", ErrRetryMismatch, event.ID))
+		}
+		event = stored
+	case errors.Is(err, store.ErrDuplicateContent):
+		// The idempotency key was already spent on the same intent, so the stored event is acked
+		// in its place
+		contentHash := event.ContentHash()
+		stored, findErr := s.find(ctx, event.AccountID, func(e *models.LedgerEvent) bool { return e.ContentHash() == contentHash })
+		if findErr != nil {
+			return nack(resp, findErr)
+		}
+		if stored == nil {
+			return nack(resp, err)
+		}
+		event = stored
+	case err != nil:
+		return nack(resp, err)
+	}
+
+	resp.Status = ingestv1.IngestStatus_INGEST_STATUS_ACK
+	resp.EventId = event.ID
+	resp.Sequence = event.Version
+	return resp
+}
+
+// find returns the first event in the account's stream that matches, or nil if none does
+func (s *Server) find(ctx context.Context, accountID string, match func(*models.LedgerEvent) bool) (*models.LedgerEvent, error) {
+	events, err := s.store.Read(ctx, accountID, 1)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if match(event) {
+			return event, nil
+		}
+	}
+	return nil, nil
+}
+
+// sameEvent reports whether event resubmits stored, ignoring the chain fields and the timestamp
+// adjustment the store applies on append
+func sameEvent(stored, event *models.LedgerEvent) bool {
+	original := stored.Clone()
+	if stamped, ok := original.Metadata[store.MetaOriginalTimestamp].(string); ok {
+		timestamp, err := time.Parse(time.RFC3339Nano, stamped)
+		if err != nil {
+			return false
+		}
+		original.Timestamp = timestamp
+		delete(original.Metadata, store.MetaOriginalTimestamp)
+	}
+	retried := event.Clone()
+	for _, e := range []*models.LedgerEvent{original, retried} {
+		e.PreviousHash, e.HashLength = "", 0
+	}
+	return original.ComputeHash() == retried.ComputeHash()
+}
+
+// nack marks the response as rejected with the given reason
+func nack(resp *ingestv1.IngestResponse, err error) *ingestv1.IngestResponse {
+	resp.Status = ingestv1.IngestStatus_INGEST_STATUS_NACK
+	resp.Error = err.Error()
+	return resp
+}
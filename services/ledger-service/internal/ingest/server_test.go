@@ -0,0 +1,196 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	ingestv1 "fintech-platform/ledger-service/api/ingest/v1"
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+func startServer(t *testing.T, eventStore store.EventStore, opts ...Option) ingestv1.IngestServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	ingestv1.RegisterIngestServiceServer(grpcServer, NewServer(eventStore, append([]Option{WithMaxInFlight(2)}, opts...)...))
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return ingestv1.NewIngestServiceClient(conn)
+}
+
+func eventRequest(t *testing.T, requestID string, event *models.LedgerEvent) *ingestv1.IngestRequest {
+	t.Helper()
+	payload, err := event.ToJSON()
+	require.NoError(t, err)
+	return &ingestv1.IngestRequest{RequestId: requestID, Event: payload}
+}
+
+func TestIngestAcksBatchInOrder(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore)
+
+	stream, err := client.Ingest(context.Background())
+	require.NoError(t, err)
+
+	var requests []*ingestv1.IngestRequest
+	for version := int64(1); version <= 5; version++ {
+		event := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1").
+			WithVersion(version)
+		requests = append(requests, eventRequest(t, fmt.Sprintf("req_%d", version), event))
+	}
+	invalid := models.NewLedgerEvent(models.Debit, models.NewMoney(0, "USD", 2), "acc_1", "corr_1").
+		WithVersion(6)
+	requests = append(requests, eventRequest(t, "req_invalid", invalid))
+
+	go func() {
+		for _, req := range requests {
+			if err := stream.Send(req); err != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	for i, req := range requests {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, req.RequestId, resp.RequestId)
+		if i < 5 {
+			assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+			assert.Equal(t, int64(i+1), resp.Sequence)
+		} else {
+			assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_NACK, resp.Status)
+			assert.NotEmpty(t, resp.Error)
+		}
+	}
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, stored, 5)
+}
+
+func TestIngestReacksResubmittedEvent(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore)
+
+	event := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1")
+	req := eventRequest(t, "req_1", event)
+
+	// The first stream is dropped after its ack, as a disconnecting client would.
+	ctx, cancel := context.WithCancel(context.Background())
+	first, err := client.Ingest(ctx)
+	require.NoError(t, err)
+	require.NoError(t, first.Send(req))
+	resp, err := first.Recv()
+	require.NoError(t, err)
+	require.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	cancel()
+
+	second, err := client.Ingest(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, second.Send(req))
+	resp, err = second.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	assert.Equal(t, int64(1), resp.Sequence)
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
+
+// ingestOne sends a single request on a fresh stream and returns its response
+func ingestOne(t *testing.T, client ingestv1.IngestServiceClient, req *ingestv1.IngestRequest) *ingestv1.IngestResponse {
+	t.Helper()
+	stream, err := client.Ingest(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(req))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.NoError(t, stream.CloseSend())
+	return resp
+}
+
+func TestIngestNacksDifferentEventUnderStoredID(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore)
+
+	event := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1")
+	resp := ingestOne(t, client, eventRequest(t, "req_1", event))
+	require.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+
+	changed := event.Clone()
+	changed.Amount = models.NewMoney(5000, "USD", 2)
+	resp = ingestOne(t, client, eventRequest(t, "req_2", changed))
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_NACK, resp.Status)
+	assert.Contains(t, resp.Error, ErrRetryMismatch.Error())
+
+	elsewhere := event.Clone()
+	elsewhere.AccountID = "acc_2"
+	resp = ingestOne(t, client, eventRequest(t, "req_3", elsewhere))
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_NACK, resp.Status, "the ID is stored on another account")
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, models.NewMoney(1000, "USD", 2), stored[0].Amount)
+}
+
+func TestIngestReacksAdjustedEventWithStoredVersion(t *testing.T) {
+	eventStore := store.NewMemoryStore(store.MonotonicTimestamps(nil))
+	client := startServer(t, eventStore)
+
+	head := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1")
+	late := models.NewLedgerEvent(models.Credit, models.NewMoney(500, "USD", 2), "acc_1", "corr_2").WithVersion(2)
+	late.Timestamp = head.Timestamp.Add(-time.Minute)
+	for i, event := range []*models.LedgerEvent{head, late} {
+		resp := ingestOne(t, client, eventRequest(t, fmt.Sprintf("req_%d", i), event))
+		require.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	}
+
+	resp := ingestOne(t, client, eventRequest(t, "req_retry", late))
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status, "the store's timestamp adjustment is not a change")
+	assert.Equal(t, int64(2), resp.Sequence)
+}
+
+func TestIngestAcksRepeatedIdempotencyKeyWithStoredEvent(t *testing.T) {
+	eventStore := store.NewMemoryStore()
+	client := startServer(t, eventStore)
+
+	first := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1").
+		WithIdempotencyKey("idem_1")
+	resp := ingestOne(t, client, eventRequest(t, "req_1", first))
+	require.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+
+	again := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1").
+		WithIdempotencyKey("idem_1").WithVersion(2)
+	resp = ingestOne(t, client, eventRequest(t, "req_2", again))
+	assert.Equal(t, ingestv1.IngestStatus_INGEST_STATUS_ACK, resp.Status)
+	assert.Equal(t, first.ID, resp.EventId)
+	assert.Equal(t, int64(1), resp.Sequence)
+
+	stored, err := eventStore.Read(context.Background(), "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
@@ -0,0 +1,229 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// VersionGap is a range of versions missing from a stream, From to To inclusive
+type VersionGap struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// ConservationIssue is an event through which a stream creates or destroys money
+type ConservationIssue struct {
+	EventID string `json:"eventId"`
+	Reason  string `json:"reason"`
+}
+
+// AccountBalance is the final balance of a stream in one currency, as BalanceState folds it:
+// credits and adjustments less debits posted, net of reversals, and the holds live at the last
+// event held
+type AccountBalance struct {
+	Posted Money `json:"posted"`
+	Held   Money `json:"held"`
+}
+
+// AccountHealth is the combined result of every consistency check AccountReport runs over a stream
+type AccountHealth struct {
+	Events int `json:"events"`
+	// ChainBreak is where the hash chain stops linking up, nil if it is intact
+	ChainBreak *ChainBreakError `json:"chainBreak,omitempty"`
+	// Signatures is the verification report, nil unless the report was given keys
+	Signatures *Report `json:"signatures,omitempty"`
+	// VersionGaps lists the versions missing between the first and last event
+	VersionGaps []VersionGap `json:"versionGaps"`
+	// DuplicateVersions lists versions held by more than one event
+	DuplicateVersions []int64 `json:"duplicateVersions"`
+	// Conservation lists reversals that do not undo their original exactly, or undo an
+	// event already reversed, and events the balance cannot take, such as releases of more
+	// than is held
+	Conservation []ConservationIssue `json:"conservation"`
+	// OrphanReferences lists references to events absent from the stream
+	OrphanReferences []OrphanRef `json:"orphanReferences"`
+	// Balances maps each currency in the stream to its final balance
+	Balances map[string]AccountBalance `json:"balances"`
+}
+
+// Healthy reports whether every check passed
+func (h AccountHealth) Healthy() bool {
+	return h.ChainBreak == nil &&
+		(h.Signatures == nil || h.Signatures.Verified()) &&
+		len(h.VersionGaps) == 0 &&
+		len(h.DuplicateVersions) == 0 &&
+		len(h.Conservation) == 0 &&
+		len(h.OrphanReferences) == 0
+}
+
+// AccountReportOption configures AccountReport
+type AccountReportOption func(*accountReportConfig)
+
+type accountReportConfig struct {
+	keys KeyProvider
+}
+
+// ReportKeys verifies the stream's signatures with keys; without it signatures are not checked
+func ReportKeys(keys KeyProvider) AccountReportOption {
+	return func(c *accountReportConfig) {
+		c.keys = keys
+	}
+}
+
+// AccountReport checks an account stream, given in version order, without changing anything:
+// its hash chain from the first event's PreviousHash, its signatures, version gaps and
+// duplicates, conservation through reversals and releases, and orphan references, and folds
+// its final balances. Every check runs to completion, so one report flags every issue found.
+// The checks share a single pass over the stream after indexing it by event ID.
+func AccountReport(events []*LedgerEvent, opts ...AccountReportOption) AccountHealth {
+	var config accountReportConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	health := AccountHealth{
+		Events:            len(events),
+		VersionGaps:       []VersionGap{},
+		DuplicateVersions: []int64{},
+		Conservation:      []ConservationIssue{},
+		OrphanReferences:  FindOrphanReferences(events),
+		Balances:          make(map[string]AccountBalance),
+	}
+	if health.OrphanReferences == nil {
+		health.OrphanReferences = []OrphanRef{}
+	}
+	if config.keys != nil {
+		signatures := VerificationReport(nil, config.keys)
+		health.Signatures = &signatures
+	}
+
+	balances := newAccountBalances()
+	byID := make(map[string]*LedgerEvent, len(events))
+	for _, event := range events {
+		byID[event.ID] = event
+	}
+
+	var (
+		expectedHash string
+		lastVersion  int64
+		reversed     = make(map[string]string)
+	)
+	for i, event := range events {
+		if i == 0 {
+			expectedHash = event.PreviousHash
+		}
+		if health.ChainBreak == nil {
+			health.ChainBreak = checkChainLink(i, event, expectedHash)
+			expectedHash = event.ComputeHash()
+		}
+
+		if health.Signatures != nil {
+			health.Signatures.record(event, config.keys)
+		}
+
+		switch {
+		case i == 0:
+		case event.Version == lastVersion:
+			health.DuplicateVersions = append(health.DuplicateVersions, event.Version)
+		case event.Version > lastVersion+1:
+			health.VersionGaps = append(health.VersionGaps, VersionGap{From: lastVersion + 1, To: event.Version - 1})
+		}
+		if i == 0 || event.Version > lastVersion {
+			lastVersion = event.Version
+		}
+
+		// A reversal the conservation check flags is not flagged again for the balance it cannot take
+		issue := checkReversalConservation(event, byID, reversed)
+		if err := balances.apply(event); err != nil && issue == "" {
+			issue = err.Error()
+		}
+		if issue != "" {
+			health.Conservation = append(health.Conservation, ConservationIssue{EventID: event.ID, Reason: issue})
+		}
+	}
+	for currency := range balances.states {
+		health.Balances[currency] = balances.balance(currency)
+	}
+	return health
+}
+
+// checkChainLink returns the chain break at event, or nil if it links to expected
+func checkChainLink(index int, event *LedgerEvent, expected string) *ChainBreakError {
+	if expected != "" && len(expected) != 2*event.hashLength() {
+		return &ChainBreakError{Index: index, EventID: event.ID, Reason: fmt.Sprintf(
+			"chained at %d-byte hashes after a %d-byte hash", event.hashLength(), len(expected)/2)}
+	}
+	if event.PreviousHash != expected {
+		return &ChainBreakError{Index: index, EventID: event.ID, Reason: "previous hash mismatch"}
+	}
+	if event.ComputeHash() == "" {
+		return &ChainBreakError{Index: index, EventID: event.ID, Reason: "event cannot be hashed"}
+	}
+	return nil
+}
+
+// checkReversalConservation describes how a reversal fails to undo its original, recording the
+// originals reversed so far in reversed. Reversals whose original is not in the stream are left
+// to the orphan reference check.
+func checkReversalConservation(event *LedgerEvent, byID map[string]*LedgerEvent, reversed map[string]string) string {
+	if !event.IsReversal() {
+		return ""
+	}
+	original, ok := byID[event.ReversedEventID()]
+	if !ok {
+		return ""
+	}
+	if err := ValidateReversal(original, event, nil); err != nil {
+		return err.Error()
+	}
+	if previous, ok := reversed[original.ID]; ok {
+		return fmt.Sprintf("%v: %s by %s", ErrAlreadyReversed, original.ID, previous)
+	}
+	reversed[original.ID] = event.ID
+	return ""
+}
+
+// accountBalances folds a recorded stream into a BalanceState per currency. Each event is
+// evaluated at its own timestamp with overdrafts allowed, so the stream is folded as it was
+// recorded rather than judged against the current time.
+type accountBalances struct {
+	states map[string]*BalanceState
+	now    time.Time
+}
+
+func newAccountBalances() *accountBalances {
+	return &accountBalances{states: make(map[string]*BalanceState)}
+}
+
+// apply folds event into its currency's balance, which is unchanged if it returns an error
+func (b *accountBalances) apply(event *LedgerEvent) error {
+	if event.Timestamp.After(b.now) {
+		b.now = event.Timestamp
+	}
+	if !event.PostsBalance() && !event.AffectsHolds() {
+		return nil
+	}
+	return b.state(event).Apply(event, BalanceRules{Now: event.Timestamp, AllowOverdraft: true})
+}
+
+// state returns the balance in event's currency, starting it at the currency's standard
+// precision, or the event's for currencies outside the currency table
+func (b *accountBalances) state(event *LedgerEvent) *BalanceState {
+	currency := event.Amount.Currency
+	if state, ok := b.states[currency]; ok {
+		return state
+	}
+	zero, err := ZeroMoney(currency)
+	if err != nil {
+		zero = Money{Currency: currency, Precision: event.Amount.Precision}
+	}
+	state := NewBalanceState(event.AccountID, zero)
+	b.states[currency] = &state
+	return &state
+}
+
+// balance returns the balance in currency as of the latest event folded
+func (b *accountBalances) balance(currency string) AccountBalance {
+	state := b.states[currency]
+	return AccountBalance{Posted: state.Posted, Held: state.Held(b.now)}
+}
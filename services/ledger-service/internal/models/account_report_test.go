@@ -0,0 +1,94 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountReportHealthyStream(t *testing.T) {
+	health := AccountReport(chainedEvents(t, 3))
+
+	assert.True(t, health.Healthy())
+	assert.Nil(t, health.Signatures)
+	assert.Equal(t, map[string]AccountBalance{
+		"USD": {Posted: NewMoney(600, "USD", 2), Held: NewMoney(0, "USD", 2)},
+	}, health.Balances)
+}
+
+func TestAccountReportFlagsEachIssue(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	credit := NewLedgerEvent(Credit, NewMoney(10000, "USD", 2), "acc_1", "corr_1").WithVersion(1)
+	hold := NewLedgerEvent(Hold, NewMoney(3000, "USD", 2), "acc_1", "corr_2").WithVersion(2)
+	release := NewLedgerEvent(Release, NewMoney(5000, "USD", 2), "acc_1", "corr_2").WithVersion(3)
+	debit := NewLedgerEvent(Debit, NewMoney(2000, "USD", 2), "acc_1", "corr_3").WithVersion(5)
+	reversal := NewReversal(credit, "corr_4").WithVersion(5)
+	reversal.Amount = NewMoney(9000, "USD", 2)
+	refund := NewLedgerEvent(Credit, NewMoney(1000, "USD", 2), "acc_1", "corr_5").
+		WithReferenceID("evt_missing").
+		WithVersion(6)
+
+	events := []*LedgerEvent{credit, hold, release, debit, reversal, refund}
+	for i, event := range events {
+		require.NoError(t, event.SignWith(key))
+		if i > 0 {
+			event.ChainAfter(events[i-1])
+		}
+	}
+	debit.PreviousHash = credit.ComputeHash()
+	refund.Amount = NewMoney(100000, "USD", 2)
+
+	health := AccountReport(events, ReportKeys(NewStaticKeys(key)))
+
+	assert.False(t, health.Healthy())
+	assert.Equal(t, 6, health.Events)
+
+	require.NotNil(t, health.ChainBreak)
+	assert.Equal(t, 3, health.ChainBreak.Index)
+	assert.Equal(t, debit.ID, health.ChainBreak.EventID)
+
+	require.NotNil(t, health.Signatures)
+	assert.Equal(t, 5, health.Signatures.Counts[OutcomeVerified])
+	require.Len(t, health.Signatures.Failures, 1)
+	assert.Equal(t, refund.ID, health.Signatures.Failures[0].EventID)
+
+	assert.Equal(t, []VersionGap{{From: 4, To: 4}}, health.VersionGaps)
+	assert.Equal(t, []int64{5}, health.DuplicateVersions)
+
+	require.Len(t, health.Conservation, 2)
+	assert.Equal(t, release.ID, health.Conservation[0].EventID)
+	assert.Contains(t, health.Conservation[0].Reason, "exceeds")
+	assert.Equal(t, reversal.ID, health.Conservation[1].EventID)
+	assert.Contains(t, health.Conservation[1].Reason, ErrReversalMismatch.Error())
+
+	assert.Equal(t, []OrphanRef{{EventID: refund.ID, TargetID: "evt_missing"}}, health.OrphanReferences)
+
+	assert.Equal(t, map[string]AccountBalance{
+		"USD": {Posted: NewMoney(99000, "USD", 2), Held: NewMoney(3000, "USD", 2)},
+	}, health.Balances)
+}
+
+func TestAccountReportFlagsRepeatedReversal(t *testing.T) {
+	credit := NewLedgerEvent(Credit, NewMoney(500, "USD", 2), "acc_1", "corr_1").WithVersion(1)
+	first := NewReversal(credit, "corr_2").WithVersion(2)
+	second := NewReversal(credit, "corr_3").WithVersion(3)
+
+	health := AccountReport([]*LedgerEvent{credit, first, second})
+
+	require.Len(t, health.Conservation, 1)
+	assert.Equal(t, second.ID, health.Conservation[0].EventID)
+	assert.Contains(t, health.Conservation[0].Reason, ErrAlreadyReversed.Error())
+	assert.Equal(t, NewMoney(0, "USD", 2), health.Balances["USD"].Posted)
+}
+
+func TestAccountReportBalanceAfterReversedDebit(t *testing.T) {
+	credit := NewLedgerEvent(Credit, NewMoney(1000, "USD", 2), "acc_1", "corr_1").WithVersion(1)
+	debit := NewLedgerEvent(Debit, NewMoney(400, "USD", 2), "acc_1", "corr_2").WithVersion(2)
+	reversal := NewReversal(debit, "corr_3").WithVersion(3)
+
+	health := AccountReport([]*LedgerEvent{credit, debit, reversal})
+
+	assert.Empty(t, health.Conservation)
+	assert.Equal(t, NewMoney(1000, "USD", 2), health.Balances["USD"].Posted)
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrAgainstNormalBalance is returned in strict mode when an event would push an account
+// against its normal balance by more than the tolerance
+var ErrAgainstNormalBalance = errors.New("posting against normal balance")
+
+// AccountType classifies an account in double-entry terms
+type AccountType string
+
+const (
+	AccountAsset     AccountType = "ASSET"
+	AccountLiability AccountType = "LIABILITY"
+	AccountEquity    AccountType = "EQUITY"
+	AccountRevenue   AccountType = "REVENUE"
+	AccountExpense   AccountType = "EXPENSE"
+)
+
+// IsValid returns true for the known account types
+func (t AccountType) IsValid() bool {
+	switch t {
+	case AccountAsset, AccountLiability, AccountEquity, AccountRevenue, AccountExpense:
+		return true
+	}
+	return false
+}
+
+// DebitNormal returns true if debits increase the account type's balance: assets and expenses.
+// Liabilities, equity and revenue are credit-normal.
+func (t AccountType) DebitNormal() bool {
+	return t == AccountAsset || t == AccountExpense
+}
+
+// NormalBalanceValidator checks that events keep accounts on the side of their normal balance.
+// Balances are posted balances, credits minus debits, so a credit-normal account must not go
+// below -tolerance and a debit-normal one must not go above tolerance. In strict mode a
+// violation is an error; in advisory mode it is only logged.
+type NormalBalanceValidator struct {
+	types     map[string]AccountType
+	tolerance Money
+	strict    bool
+	logger    logrus.FieldLogger
+}
+
+// NewNormalBalanceValidator creates a validator for accounts with the given types, allowing
+// them tolerance past their normal balance. Accounts without a type are not checked.
+func NewNormalBalanceValidator(types map[string]AccountType, tolerance Money, strict bool) *NormalBalanceValidator {
+	return &NormalBalanceValidator{
+		types:     types,
+		tolerance: tolerance,
+		strict:    strict,
+		logger:    logrus.StandardLogger(),
+	}
+}
+
+// WithLogger sets the logger used for advisory-mode warnings
+func (v *NormalBalanceValidator) WithLogger(logger logrus.FieldLogger) *NormalBalanceValidator {
+	v.logger = logger
+	return v
+}
+
+// Check reports whether posting e to an account whose posted balance is balance would push the
+// account against its normal balance. Only events that move the balance away from the normal
+// side are flagged, so an account already outside the tolerance can still be corrected.
+func (v *NormalBalanceValidator) Check(balance Money, e *LedgerEvent) error {
+	accountType, ok := v.types[e.AccountID]
+	if !ok || !e.AffectsBalance() {
+		return nil
+	}
+	against := e.WithdrawsFunds()
+	if accountType.DebitNormal() {
+		against = !against
+	}
+	if !against {
+		return nil
+	}
+
+	amount, err := e.Amount.Rescale(balance.Precision)
+	if err != nil {
+		return err
+	}
+	next, err := balance.Add(amount)
+	if e.IsDebit() {
+		next, err = balance.Sub(amount)
+	}
+	if err != nil {
+		return err
+	}
+	tolerance, err := v.tolerance.Rescale(next.Precision)
+	if err != nil {
+		return err
+	}
+
+	excess := -next.MinorUnits
+	if accountType.DebitNormal() {
+		excess = next.MinorUnits
+	}
+	if excess <= tolerance.MinorUnits {
+		return nil
+	}
+	return v.violation(e, fmt.Errorf("%w: %s on %s account %s leaves balance %s %s",
+		ErrAgainstNormalBalance, e.Type, accountType, e.AccountID, next.decimal(), next.Currency))
+}
+
+// violation returns err in strict mode and logs it otherwise
+func (v *NormalBalanceValidator) violation(e *LedgerEvent, err error) error {
+	if v.strict {
+		return err
+	}
+	v.logger.WithField("eventId", e.ID).Warn(err.Error())
+	return nil
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var accountTypes = map[string]AccountType{
+	"deposits": AccountLiability,
+	"cash":     AccountAsset,
+}
+
+func TestNormalBalanceValidatorStrictFlagsDebitToLiability(t *testing.T) {
+	validator := NewNormalBalanceValidator(accountTypes, NewMoney(0, "USD", 2), true)
+	debit := NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "deposits", "corr_1")
+
+	err := validator.Check(NewMoney(2000, "USD", 2), debit)
+	require.ErrorIs(t, err, ErrAgainstNormalBalance)
+	assert.Contains(t, err.Error(), "-30")
+
+	assert.NoError(t, validator.Check(NewMoney(5000, "USD", 2), debit), "a debit within the credit balance")
+	assert.NoError(t, validator.Check(NewMoney(-9000, "USD", 2),
+		NewLedgerEvent(Credit, NewMoney(5000, "USD", 2), "deposits", "corr_2")), "credits correct a liability")
+	assert.NoError(t, validator.Check(NewMoney(0, "USD", 2),
+		NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "untyped", "corr_3")))
+}
+
+func TestNormalBalanceValidatorAssetAndTolerance(t *testing.T) {
+	validator := NewNormalBalanceValidator(accountTypes, NewMoney(10, "USD", 1), true)
+
+	credit := NewLedgerEvent(Credit, NewMoney(300, "USD", 2), "cash", "corr_1")
+	assert.ErrorIs(t, validator.Check(NewMoney(-150, "USD", 2), credit), ErrAgainstNormalBalance)
+	assert.NoError(t, validator.Check(NewMoney(-250, "USD", 2), credit), "within the 1.0 tolerance")
+	assert.NoError(t, validator.Check(NewMoney(0, "USD", 2),
+		NewLedgerEvent(Debit, NewMoney(300, "USD", 2), "cash", "corr_2")))
+}
+
+func TestNormalBalanceValidatorTreatsNegativeAdjustmentsAsDebits(t *testing.T) {
+	validator := NewNormalBalanceValidator(accountTypes, NewMoney(0, "USD", 2), true)
+
+	writeDown := NewLedgerEvent(Adjustment, NewMoney(-5000, "USD", 2), "deposits", "corr_1")
+	err := validator.Check(NewMoney(2000, "USD", 2), writeDown)
+	require.ErrorIs(t, err, ErrAgainstNormalBalance)
+	assert.Contains(t, err.Error(), "-30")
+	assert.NoError(t, validator.Check(NewMoney(-9000, "USD", 2),
+		NewLedgerEvent(Adjustment, NewMoney(5000, "USD", 2), "deposits", "corr_2")), "positive adjustments credit")
+
+	assert.ErrorIs(t, validator.Check(NewMoney(0, "USD", 2),
+		NewLedgerEvent(Adjustment, NewMoney(300, "USD", 2), "cash", "corr_3")), ErrAgainstNormalBalance)
+	assert.NoError(t, validator.Check(NewMoney(0, "USD", 2),
+		NewLedgerEvent(Adjustment, NewMoney(-300, "USD", 2), "cash", "corr_4")))
+}
+
+func TestNormalBalanceValidatorAdvisoryLogs(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	validator := NewNormalBalanceValidator(accountTypes, NewMoney(0, "USD", 2), false).WithLogger(logger)
+
+	debit := NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "deposits", "corr_1")
+	require.NoError(t, validator.Check(NewMoney(0, "USD", 2), debit))
+	require.Len(t, hook.Entries, 1)
+	assert.Equal(t, debit.ID, hook.LastEntry().Data["eventId"])
+}
+
+func TestAccountTypeNormalBalance(t *testing.T) {
+	for accountType, debitNormal := range map[AccountType]bool{
+		AccountAsset: true, AccountExpense: true,
+		AccountLiability: false, AccountEquity: false, AccountRevenue: false,
+	} {
+		assert.True(t, accountType.IsValid())
+		assert.Equal(t, debitNormal, accountType.DebitNormal(), string(accountType))
+	}
+	assert.False(t, AccountType("CONTRA").IsValid())
+}
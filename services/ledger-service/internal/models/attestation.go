@@ -0,0 +1,100 @@
+package models
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Attestation is a counter-signature by a third party, such as a bank acknowledging a
+// settlement. It covers the event's canonical bytes, the attester and the time of attesting, and
+// is not itself part of the canonical form, so adding one never invalidates the event's
+// signature or hashes.
+type Attestation struct {
+	AttesterID string    `json:"attesterId"`
+	KeyID      string    `json:"keyId"`
+	Algorithm  string    `json:"algorithm,omitempty"`
+	Signature  string    `json:"signature"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// AttestationResult is the outcome of verifying one attestation
+type AttestationResult struct {
+	AttesterID string              `json:"attesterId"`
+	KeyID      string              `json:"keyId"`
+	Outcome    VerificationOutcome `json:"outcome"`
+	Reason     string              `json:"reason,omitempty"`
+}
+
+// AddAttestation counter-signs the event on behalf of attester with signer. The attestation
+// covers the same canonical bytes as the event's hash, which do not depend on the event's own
+// signature algorithm, so it survives the event being re-signed.
+func (e *LedgerEvent) AddAttestation(attester string, signer Signer) error {
+	if attester == "" {
+		return fmt.Errorf("attester is required")
+	}
+	attestation := Attestation{
+		AttesterID: attester,
+		KeyID:      signer.KeyID(),
+		Algorithm:  signer.Algorithm(),
+		Timestamp:  time.Now().UTC().Truncate(time.Second),
+	}
+	payload, err := e.attestationPayload(attestation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for attestation: %w", err)
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return fmt.Errorf("failed to attest event: %w", err)
+	}
+
+	attestation.Signature = hex.EncodeToString(signature)
+	e.Attestations = append(e.Attestations, attestation)
+	return nil
+}
+
+// VerifyAttestations verifies each attestation with the verifier its KeyID resolves to within
+// the event's tenant, returning the results in attestation order
+func (e *LedgerEvent) VerifyAttestations(keys KeyProvider) []AttestationResult {
+	results := make([]AttestationResult, 0, len(e.Attestations))
+	for _, attestation := range e.Attestations {
+		result := AttestationResult{AttesterID: attestation.AttesterID, KeyID: attestation.KeyID, Outcome: OutcomeVerified}
+		if err := e.verifyAttestation(attestation, keys); err != nil {
+			result.Outcome, result.Reason = OutcomeInvalid, err.Error()
+			if errors.Is(err, ErrUnknownKey) {
+				result.Outcome = OutcomeUnknownKey
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// verifyAttestation checks a single attestation's signature
+func (e *LedgerEvent) verifyAttestation(attestation Attestation, keys KeyProvider) error {
+	verifier, err := keys.Verifier(e.TenantID, attestation.KeyID)
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(attestation.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed attestation signature", ErrInvalidSignature)
+	}
+	payload, err := e.attestationPayload(attestation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for verification: %w", err)
+	}
+	return verifier.Verify(payload, signature)
+}
+
+// attestationPayload returns "<unix seconds>.<attester>.<canonical bytes>", the bytes an
+// attestation signs
+func (e *LedgerEvent) attestationPayload(attestation Attestation) ([]byte, error) {
+	canonical, err := e.signingBytesAs("")
+	if err != nil {
+		return nil, err
+	}
+	attested := append([]byte(attestation.AttesterID+"."), canonical...)
+	return timestampedPayload(attested, attestation.Timestamp), nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttestationKeepsOwnSignatureValid(t *testing.T) {
+	ours := NewHMACKey("ledger-1", []byte("ours"))
+	bank := NewHMACKey("bank-1", []byte("bank"))
+
+	event := NewLedgerEvent(Credit, usdAmount(250), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, event.SignWith(ours))
+	hash := event.ComputeHash()
+	require.NoError(t, event.AddAttestation("acme-bank", bank))
+
+	require.NoError(t, event.VerifyWith(ours))
+	assert.Equal(t, hash, event.ComputeHash())
+	assert.Equal(t, []AttestationResult{
+		{AttesterID: "acme-bank", KeyID: "bank-1", Outcome: OutcomeVerified},
+	}, event.VerifyAttestations(NewStaticKeys(bank)))
+
+	data, err := event.ToJSON()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(data)
+	require.NoError(t, err)
+	require.NoError(t, decoded.VerifyWith(ours))
+	assert.Equal(t, OutcomeVerified, decoded.VerifyAttestations(NewStaticKeys(bank))[0].Outcome)
+}
+
+func TestAttestationOutcomes(t *testing.T) {
+	bank := NewHMACKey("bank-1", []byte("bank"))
+	event := NewLedgerEvent(Credit, usdAmount(250), "acc_1", "corr_1")
+	require.NoError(t, event.AddAttestation("acme-bank", bank))
+
+	assert.Equal(t, OutcomeUnknownKey, event.VerifyAttestations(NewStaticKeys())[0].Outcome)
+
+	relabeled := *event
+	relabeled.Attestations = []Attestation{event.Attestations[0]}
+	relabeled.Attestations[0].AttesterID = "other-bank"
+	assert.Equal(t, OutcomeInvalid, relabeled.VerifyAttestations(NewStaticKeys(bank))[0].Outcome)
+
+	event.Amount = usdAmount(2500)
+	result := event.VerifyAttestations(NewStaticKeys(bank))[0]
+	assert.Equal(t, OutcomeInvalid, result.Outcome)
+	assert.Contains(t, result.Reason, ErrInvalidSignature.Error())
+
+	assert.Error(t, event.AddAttestation("", bank))
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPageTampered is returned when an audit page's events do not match its signed root
+var ErrPageTampered = errors.New("audit page tampered")
+
+// AuditPage is one page of an account's history served to auditors. Like a Bundle it is
+// verifiable on its own: the signature covers the Merkle root of its events together with the
+// account and the cursors locating the page, so events cannot be dropped, altered or moved
+// between pages unnoticed.
+type AuditPage struct {
+	AccountID string `json:"accountId"`
+	// Cursor is the cursor the page was requested with; empty for the first page
+	Cursor string `json:"cursor,omitempty"`
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string         `json:"nextCursor,omitempty"`
+	Events     []*LedgerEvent `json:"events"`
+	// MerkleRoot is empty for a page without events
+	MerkleRoot string `json:"merkleRoot,omitempty"`
+	KeyID      string `json:"keyId"`
+	Algorithm  string `json:"algorithm"`
+	Signature  string `json:"signature"`
+}
+
+// SignAuditPage sets the page's Merkle root over its events and signs it with signer
+func SignAuditPage(page *AuditPage, signer Signer) error {
+	root, err := pageRoot(page.Events)
+	if err != nil {
+		return fmt.Errorf("failed to compute page root: %w", err)
+	}
+	page.MerkleRoot = root
+	page.KeyID = signer.KeyID()
+	page.Algorithm = signer.Algorithm()
+
+	payload, err := page.signedPayload()
+	if err != nil {
+		return err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return fmt.Errorf("failed to sign audit page: %w", err)
+	}
+	page.Signature = hex.EncodeToString(signature)
+	return nil
+}
+
+// VerifyAuditPage checks the page signature and that its events still match the signed root
+func VerifyAuditPage(page AuditPage, verifier Verifier) error {
+	if page.KeyID != verifier.KeyID() {
+		return fmt.Errorf("%w: page signed with key %q, verifier has %q", ErrInvalidSignature, page.KeyID, verifier.KeyID())
+	}
+
+	payload, err := page.signedPayload()
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(page.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	if err := verifier.Verify(payload, signature); err != nil {
+		return err
+	}
+
+	root, err := pageRoot(page.Events)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPageTampered, err)
+	}
+	if root != page.MerkleRoot {
+		return fmt.Errorf("%w: events do not match merkle root", ErrPageTampered)
+	}
+	for _, event := range page.Events {
+		if event.AccountID != page.AccountID {
+			return fmt.Errorf("%w: event %s belongs to account %s", ErrPageTampered, event.ID, event.AccountID)
+		}
+	}
+	return nil
+}
+
+// pageRoot returns the hex Merkle root of events, or the empty string when there are none
+func pageRoot(events []*LedgerEvent) (string, error) {
+	if len(events) == 0 {
+		return "", nil
+	}
+	root, err := MerkleRoot(events)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(root[:]), nil
+}
+
+// signedPayload returns the bytes covered by the page signature
+func (p AuditPage) signedPayload() ([]byte, error) {
+	payload, err := json.Marshal(struct {
+		AccountID  string `json:"accountId"`
+		Cursor     string `json:"cursor"`
+		NextCursor string `json:"nextCursor"`
+		MerkleRoot string `json:"merkleRoot"`
+	}{p.AccountID, p.Cursor, p.NextCursor, p.MerkleRoot})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit page for signing: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,475 @@
+package models
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrMalformedAvro is returned when Avro-encoded bytes do not decode against the event schema
+var ErrMalformedAvro = errors.New("malformed avro event")
+
+// Branch indexes of the metadata value union in ledgerv1.LedgerEventAvroSchema
+const (
+	avroMetadataNull int64 = iota
+	avroMetadataBoolean
+	avroMetadataDouble
+	avroMetadataString
+	avroMetadataJSON
+)
+
+// avroNanosRange bounds the times a timestamp-nanos long can hold
+var avroNanosRange = [2]time.Time{time.Unix(0, math.MinInt64), time.Unix(0, math.MaxInt64)}
+
+// ToAvro encodes the event in the Avro binary encoding of ledgerv1.LedgerEventAvroSchema. The
+// bytes carry no schema or framing; producers add the schema registry framing themselves.
+// Optional fields are unions with null. Metadata is a map from string to the union
+// [null, boolean, double, string, JSONValue], holding each value as the JSON form encodes it:
+// numbers become doubles, and arrays and objects are carried as their JSON text in JSONValue.
+// Timestamps are nanoseconds since the Unix epoch, so times outside 1678-2262 fail to encode.
+func (e *LedgerEvent) ToAvro() ([]byte, error) {
+	metadata, err := jsonMetadata(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &avroWriter{}
+	w.string(e.ID)
+	w.string(e.TenantID)
+	w.string(string(e.Type))
+	w.money(e.Amount)
+	w.string(e.Currency)
+	w.string(e.AccountID)
+	w.optionalString(e.PaymentID)
+	w.optionalString(e.ReferenceID)
+	w.long(int64(len(e.References)))
+	for _, ref := range e.References {
+		w.string(string(ref.Kind))
+		w.string(ref.EventID)
+	}
+	w.endBlocks(len(e.References))
+	w.optionalString(e.ReversesEventID)
+	w.optionalString(e.HoldID)
+	w.time(e.Timestamp)
+	w.optionalTime(e.EffectiveAt)
+	w.optionalTime(e.ExpiresAt)
+	w.optionalTime(e.ValidFrom)
+	w.optionalTime(e.ValidUntil)
+	w.metadata(metadata)
+	if e.Fees == nil {
+		w.long(0)
+	} else {
+		w.long(1)
+		w.long(int64(len(e.Fees.Components)))
+		for _, component := range e.Fees.Components {
+			w.string(string(component.Type))
+			w.money(component.Amount)
+		}
+		w.endBlocks(len(e.Fees.Components))
+	}
+	w.string(e.Signature)
+	w.string(e.SignatureAlgorithm)
+	w.string(e.KeyID)
+	w.long(int64(len(e.PriorSignatures)))
+	for _, prior := range e.PriorSignatures {
+		w.string(prior.KeyID)
+		w.string(prior.Algorithm)
+		w.string(prior.Signature)
+	}
+	w.endBlocks(len(e.PriorSignatures))
+	w.long(int64(len(e.Attestations)))
+	for _, attestation := range e.Attestations {
+		w.string(attestation.AttesterID)
+		w.string(attestation.KeyID)
+		w.string(attestation.Algorithm)
+		w.string(attestation.Signature)
+		w.time(attestation.Timestamp)
+	}
+	w.endBlocks(len(e.Attestations))
+	w.string(e.PreviousHash)
+	w.long(int64(e.HashLength))
+	w.long(e.Version)
+	w.long(int64(e.Priority))
+	w.string(e.CorrelationID)
+	w.string(e.IdempotencyKey)
+	if e.Taxes == nil {
+		w.long(0)
+	} else {
+		w.long(1)
+		w.long(int64(len(e.Taxes.Components)))
+		for _, component := range e.Taxes.Components {
+			w.string(string(component.Type))
+			w.string(component.Jurisdiction)
+			w.string(component.Rate.String())
+			w.money(component.TaxableBase)
+			w.money(component.TaxAmount)
+		}
+		w.endBlocks(len(e.Taxes.Components))
+	}
+
+	if w.err != nil {
+		return nil, fmt.Errorf("failed to encode event %s as avro: %w", e.ID, w.err)
+	}
+	return w.buf, nil
+}
+
+// LedgerEventFromAvro decodes an event from the Avro binary encoding written by ToAvro. As with
+// LedgerEventFromJSON, timestamps come back in UTC and metadata numbers as float64.
+func LedgerEventFromAvro(data []byte) (*LedgerEvent, error) {
+	r := &avroReader{buf: data}
+	e := &LedgerEvent{
+		ID:        r.string(),
+		TenantID:  r.string(),
+		Type:      EventType(r.string()),
+		Amount:    r.money(),
+		Currency:  r.string(),
+		AccountID: r.string(),
+	}
+	e.PaymentID = r.optionalString()
+	e.ReferenceID = r.optionalString()
+	r.blocks(func() {
+		e.References = append(e.References, EventRef{Kind: RefKind(r.string()), EventID: r.string()})
+	})
+	e.ReversesEventID = r.optionalString()
+	e.HoldID = r.optionalString()
+	e.Timestamp = r.time()
+	e.EffectiveAt = r.optionalTime()
+	e.ExpiresAt = r.optionalTime()
+	e.ValidFrom = r.optionalTime()
+	e.ValidUntil = r.optionalTime()
+	e.Metadata = r.metadata()
+	if r.union(2) == 1 {
+		e.Fees = &FeeBreakdown{}
+		r.blocks(func() {
+			e.Fees.Components = append(e.Fees.Components, FeeComponent{Type: FeeType(r.string()), Amount: r.money()})
+		})
+	}
+	e.Signature = r.string()
+	e.SignatureAlgorithm = r.string()
+	e.KeyID = r.string()
+	r.blocks(func() {
+		e.PriorSignatures = append(e.PriorSignatures, PriorSignature{KeyID: r.string(), Algorithm: r.string(), Signature: r.string()})
+	})
+	r.blocks(func() {
+		e.Attestations = append(e.Attestations, Attestation{
+			AttesterID: r.string(),
+			KeyID:      r.string(),
+			Algorithm:  r.string(),
+			Signature:  r.string(),
+			Timestamp:  r.time(),
+		})
+	})
+	e.PreviousHash = r.string()
+	e.HashLength = int(r.int())
+	e.Version = r.long()
+	e.Priority = int(r.long())
+	e.CorrelationID = r.string()
+	e.IdempotencyKey = r.string()
+	if r.union(2) == 1 {
+		e.Taxes = &TaxBreakdown{}
+		r.blocks(func() {
+			component := TaxComponent{Type: TaxType(r.string()), Jurisdiction: r.string()}
+			component.Rate = r.decimal()
+			component.TaxableBase = r.money()
+			component.TaxAmount = r.money()
+			e.Taxes.Components = append(e.Taxes.Components, component)
+		})
+	}
+
+	if r.err == nil && len(r.buf) > 0 {
+		r.fail("%d trailing bytes", len(r.buf))
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return e, nil
+}
+
+// avroWriter appends values in the Avro binary encoding, keeping the first error
+type avroWriter struct {
+	buf []byte
+	err error
+}
+
+// long writes a zig-zag varint; Avro ints and longs share the encoding
+func (w *avroWriter) long(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *avroWriter) string(s string) {
+	w.long(int64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *avroWriter) boolean(b bool) {
+	if b {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *avroWriter) double(f float64) {
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(f))
+}
+
+// endBlocks terminates an array or map written as a single block of count items
+func (w *avroWriter) endBlocks(count int) {
+	if count > 0 {
+		w.long(0)
+	}
+}
+
+func (w *avroWriter) money(m Money) {
+	w.long(m.MinorUnits)
+	w.string(m.Currency)
+	w.long(int64(m.Precision))
+}
+
+func (w *avroWriter) optionalString(s *string) {
+	if s == nil {
+		w.long(0)
+		return
+	}
+	w.long(1)
+	w.string(*s)
+}
+
+func (w *avroWriter) time(t time.Time) {
+	if t.Before(avroNanosRange[0]) || t.After(avroNanosRange[1]) {
+		if w.err == nil {
+			w.err = fmt.Errorf("time %s is outside the timestamp-nanos range", t.Format(time.RFC3339))
+		}
+		return
+	}
+	w.long(t.UnixNano())
+}
+
+func (w *avroWriter) optionalTime(t *time.Time) {
+	if t == nil {
+		w.long(0)
+		return
+	}
+	w.long(1)
+	w.time(*t)
+}
+
+// metadata writes JSON-decoded metadata, with keys sorted so equal metadata encodes equally
+func (w *avroWriter) metadata(metadata map[string]interface{}) {
+	if metadata == nil {
+		w.long(0)
+		return
+	}
+	w.long(1)
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w.long(int64(len(keys)))
+	for _, key := range keys {
+		w.string(key)
+		switch value := metadata[key].(type) {
+		case nil:
+			w.long(avroMetadataNull)
+		case bool:
+			w.long(avroMetadataBoolean)
+			w.boolean(value)
+		case float64:
+			w.long(avroMetadataDouble)
+			w.double(value)
+		case string:
+			w.long(avroMetadataString)
+			w.string(value)
+		default:
+			encoded, err := json.Marshal(value)
+			if err != nil && w.err == nil {
+				w.err = fmt.Errorf("failed to encode metadata %q: %w", key, err)
+			}
+			w.long(avroMetadataJSON)
+			w.string(string(encoded))
+		}
+	}
+	w.endBlocks(len(keys))
+}
+
+// avroReader consumes values in the Avro binary encoding. After the first error every read
+// returns a zero value and the error is kept for the caller to check once.
+type avroReader struct {
+	buf []byte
+	err error
+}
+
+func (r *avroReader) fail(format string, args ...interface{}) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s", ErrMalformedAvro, fmt.Sprintf(format, args...))
+	}
+	r.buf = nil
+}
+
+func (r *avroReader) long() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.fail("invalid long")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *avroReader) int() int32 {
+	v := r.long()
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		r.fail("int %d out of range", v)
+		return 0
+	}
+	return int32(v)
+}
+
+func (r *avroReader) bytes(n int64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > int64(len(r.buf)) {
+		r.fail("length %d exceeds the %d remaining bytes", n, len(r.buf))
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *avroReader) string() string {
+	b := r.bytes(r.long())
+	if !utf8.Valid(b) {
+		r.fail("invalid UTF-8 string")
+		return ""
+	}
+	return string(b)
+}
+
+func (r *avroReader) boolean() bool {
+	b := r.bytes(1)
+	if len(b) == 0 {
+		return false
+	}
+	if b[0] > 1 {
+		r.fail("invalid boolean %d", b[0])
+	}
+	return b[0] == 1
+}
+
+func (r *avroReader) double() float64 {
+	b := r.bytes(8)
+	if len(b) == 0 {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// union reads a union branch index, checking it against the number of branches
+func (r *avroReader) union(branches int64) int64 {
+	branch := r.long()
+	if branch < 0 || branch >= branches {
+		r.fail("union branch %d out of range", branch)
+		return 0
+	}
+	return branch
+}
+
+// blocks reads the blocks of an array or map, calling item once per item
+func (r *avroReader) blocks(item func()) {
+	for r.err == nil {
+		count := r.long()
+		if count == 0 {
+			return
+		}
+		if count < 0 {
+			// A negative count is followed by the block's size in bytes
+			count = -count
+			r.long()
+		}
+		if count > int64(len(r.buf)) {
+			r.fail("block of %d items exceeds the %d remaining bytes", count, len(r.buf))
+			return
+		}
+		for ; count > 0 && r.err == nil; count-- {
+			item()
+		}
+	}
+}
+
+func (r *avroReader) money() Money {
+	minorUnits := r.long()
+	currency := r.string()
+	return NewMoney(minorUnits, currency, int(r.int()))
+}
+
+func (r *avroReader) decimal() Decimal {
+	s := r.string()
+	if r.err != nil {
+		return Decimal{}
+	}
+	d, err := ParseDecimal(s)
+	if err != nil {
+		r.fail("%v", err)
+	}
+	return d
+}
+
+func (r *avroReader) optionalString() *string {
+	if r.union(2) == 0 {
+		return nil
+	}
+	s := r.string()
+	return &s
+}
+
+func (r *avroReader) time() time.Time {
+	return time.Unix(0, r.long()).UTC()
+}
+
+func (r *avroReader) optionalTime() *time.Time {
+	if r.union(2) == 0 {
+		return nil
+	}
+	t := r.time()
+	return &t
+}
+
+func (r *avroReader) metadata() map[string]interface{} {
+	if r.union(2) == 0 {
+		return nil
+	}
+	metadata := make(map[string]interface{})
+	r.blocks(func() {
+		key := r.string()
+		switch r.union(avroMetadataJSON + 1) {
+		case avroMetadataNull:
+			metadata[key] = nil
+		case avroMetadataBoolean:
+			metadata[key] = r.boolean()
+		case avroMetadataDouble:
+			metadata[key] = r.double()
+		case avroMetadataString:
+			metadata[key] = r.string()
+		case avroMetadataJSON:
+			var value interface{}
+			if err := json.Unmarshal([]byte(r.string()), &value); err != nil && r.err == nil {
+				r.fail("metadata %q: %v", key, err)
+			}
+			metadata[key] = value
+		}
+	})
+	return metadata
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ledgerv1 "fintech-platform/ledger-service/api/ledger/v1"
+)
+
+func TestAvroRoundTrip(t *testing.T) {
+	effective := time.Date(2024, 3, 1, 9, 30, 0, 123456789, time.UTC)
+	expires := effective.Add(72 * time.Hour)
+	event := NewLedgerEvent(Hold, usdAmount(42.5), "acc_1", "corr_1").
+		WithVersion(3).
+		WithPaymentID("pay_1").
+		WithHoldID("hold_1").
+		WithPriority(2).
+		WithIdempotencyKey("idem_1").
+		WithMetadata("channel", "card").
+		WithMetadata("attempt", 2.0).
+		WithMetadata("retried", true).
+		WithMetadata("note", nil).
+		WithMetadata("tags", []interface{}{"a", "b"}).
+		WithMetadata("terminal", map[string]interface{}{"id": "t_9", "offline": false}).
+		WithEffectiveAt(effective)
+	event.TenantID = "tenant_1"
+	event.ExpiresAt = &expires
+	event.PreviousHash = "abc123"
+	event.HashLength = MinHashLength
+	event.Fees = &FeeBreakdown{Components: []FeeComponent{{Type: "interchange", Amount: usdAmount(0.3)}}}
+	event.Taxes = &TaxBreakdown{Components: []TaxComponent{
+		{Type: VAT, Jurisdiction: "DE", Rate: NewDecimal(19, 2), TaxableBase: usdAmount(35.71), TaxAmount: usdAmount(6.79)},
+	}}
+	event.AddReference(RefCaptures, "evt_auth")
+	event.Attestations = []Attestation{{AttesterID: "risk", KeyID: "risk-1", Algorithm: "hmac-sha256", Signature: "c2ln", Timestamp: effective}}
+	require.NoError(t, event.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+	require.NoError(t, event.Resign(NewHMACKey("ledger-2", []byte("rotated"))))
+
+	encoded, err := event.ToAvro()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromAvro(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, event, decoded)
+	assert.NoError(t, decoded.VerifyWith(NewHMACKey("ledger-2", []byte("rotated"))))
+
+	again, err := decoded.ToAvro()
+	require.NoError(t, err)
+	assert.Equal(t, encoded, again)
+}
+
+func TestAvroKeepsUnsetOptionalFields(t *testing.T) {
+	event := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	event.Metadata = nil
+
+	encoded, err := event.ToAvro()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromAvro(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, event, decoded)
+	assert.Nil(t, decoded.PaymentID)
+	assert.Nil(t, decoded.EffectiveAt)
+	assert.Nil(t, decoded.Metadata)
+	assert.Nil(t, decoded.Fees)
+}
+
+func TestAvroRejectsMalformedInput(t *testing.T) {
+	encoded, err := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1).ToAvro()
+	require.NoError(t, err)
+
+	_, err = LedgerEventFromAvro(encoded[:len(encoded)-3])
+	assert.ErrorIs(t, err, ErrMalformedAvro)
+	_, err = LedgerEventFromAvro(append(encoded, 0))
+	assert.ErrorIs(t, err, ErrMalformedAvro)
+
+	outOfRange := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1")
+	outOfRange.Timestamp = time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err = outOfRange.ToAvro()
+	assert.Error(t, err)
+}
+
+func TestAvroSchemaListsEncodedFields(t *testing.T) {
+	var schema struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(ledgerv1.LedgerEventAvroSchema), &schema))
+
+	// The schema's field order is the encoding order, and matches the JSON field names
+	encoded, err := json.Marshal(NewLedgerEvent(Debit, usdAmount(1), "acc_1", "corr_1"))
+	require.NoError(t, err)
+	var jsonFields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &jsonFields))
+
+	names := make([]string, 0, len(schema.Fields))
+	for _, field := range schema.Fields {
+		names = append(names, field.Name)
+	}
+	for name := range jsonFields {
+		assert.Contains(t, names, name)
+	}
+	assert.Len(t, names, 30)
+}
@@ -0,0 +1,36 @@
+package models
+
+// BalancePoint is an account's balance in one currency right after an event
+type BalancePoint struct {
+	EventID   string `json:"eventId"`
+	Version   int64  `json:"version"`
+	Currency  string `json:"currency"`
+	Posted    Money  `json:"posted"`
+	Held      Money  `json:"held"`
+	Available Money  `json:"available"`
+	// Issue describes why the event could not be applied, such as a release of more than is
+	// held; the balance is then the one before the event
+	Issue string `json:"issue,omitempty"`
+}
+
+// BalanceHistory folds an account stream, given in version order, and returns the balance in
+// each event's currency after every event, one point per event. Balances fold as in
+// AccountReport, with holds counted while live at each event's timestamp; control events
+// repeat the balance before them.
+func BalanceHistory(events []*LedgerEvent) []BalancePoint {
+	balances := newAccountBalances()
+	history := make([]BalancePoint, 0, len(events))
+	for _, event := range events {
+		point := BalancePoint{EventID: event.ID, Version: event.Version, Currency: event.Amount.Currency}
+		if err := balances.apply(event); err != nil {
+			point.Issue = err.Error()
+		}
+
+		state := balances.state(event)
+		point.Posted = state.Posted
+		point.Held = state.Held(event.Timestamp)
+		point.Available = state.Available(event.Timestamp)
+		history = append(history, point)
+	}
+	return history
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBalanceHistoryRunningBalances(t *testing.T) {
+	hold := NewLedgerEvent(Hold, usdAmount(30), "acc_1", "corr_2").WithVersion(2)
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1),
+		hold,
+		NewLedgerEvent(Debit, usdAmount(20), "acc_1", "corr_3").WithVersion(3),
+		newControlEvent(AccountFreeze, "acc_1", "corr_4").WithVersion(4),
+		NewLedgerEvent(Release, usdAmount(30), "acc_1", "corr_2").WithHoldID(hold.ID).WithVersion(5),
+		NewLedgerEvent(Release, usdAmount(5), "acc_1", "corr_5").WithHoldID(hold.ID).WithVersion(6),
+	}
+
+	history := BalanceHistory(events)
+	require.Len(t, history, len(events))
+
+	expected := []struct{ posted, held, available float64 }{
+		{100, 0, 100},
+		{100, 30, 70},
+		{80, 30, 50},
+		{80, 30, 50},
+		{80, 0, 80},
+		{80, 0, 80},
+	}
+	for i, point := range history {
+		assert.Equal(t, events[i].ID, point.EventID)
+		assert.Equal(t, events[i].Version, point.Version)
+		if events[i].IsControl() {
+			assert.Empty(t, point.Issue)
+			continue
+		}
+		assert.Equal(t, "USD", point.Currency)
+		assert.Equal(t, usdAmount(expected[i].posted), point.Posted, "posted after version %d", point.Version)
+		assert.Equal(t, usdAmount(expected[i].held), point.Held, "held after version %d", point.Version)
+		assert.Equal(t, usdAmount(expected[i].available), point.Available, "available after version %d", point.Version)
+	}
+	assert.Empty(t, history[4].Issue)
+	assert.Contains(t, history[5].Issue, "exceeds")
+}
+
+func TestBalanceHistoryTracksEachCurrency(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1),
+		NewLedgerEvent(Credit, NewMoney(500, "EUR", 2), "acc_1", "corr_2").WithVersion(2),
+		NewLedgerEvent(Debit, usdAmount(4), "acc_1", "corr_3").WithVersion(3),
+	}
+
+	history := BalanceHistory(events)
+	require.Len(t, history, 3)
+	assert.Equal(t, NewMoney(500, "EUR", 2), history[1].Posted)
+	assert.Equal(t, usdAmount(6), history[2].Posted)
+	assert.Empty(t, BalanceHistory(nil))
+}
+
+func TestBalanceHistoryCapturesAndExpiresHolds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(event *LedgerEvent, offset time.Duration) *LedgerEvent {
+		event.Timestamp = start.Add(offset)
+		return event
+	}
+	hold := at(NewLedgerEvent(Hold, usdAmount(30), "acc_1", "corr_2").WithVersion(2), time.Minute)
+	expiring := at(NewLedgerEvent(Hold, usdAmount(50), "acc_1", "corr_4").WithVersion(4), 3*time.Minute).
+		WithExpiry(time.Hour)
+	validFrom := start.Add(3 * time.Hour)
+	windowed := at(NewLedgerEvent(Credit, usdAmount(5), "acc_1", "corr_6").WithVersion(6), 2*time.Hour).
+		WithValidity(&validFrom, nil)
+	events := []*LedgerEvent{
+		at(NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1), 0),
+		hold,
+		at(NewLedgerEvent(Debit, usdAmount(30), "acc_1", "corr_3").WithHoldID(hold.ID).WithVersion(3), 2*time.Minute),
+		expiring,
+		at(NewLedgerEvent(Credit, usdAmount(1), "acc_1", "corr_5").WithVersion(5), 2*time.Hour),
+		windowed,
+	}
+
+	history := BalanceHistory(events)
+	require.Len(t, history, len(events))
+	expected := []struct{ posted, held, available float64 }{
+		{100, 0, 100},
+		{100, 30, 70},
+		{70, 0, 70},
+		{70, 50, 20},
+		{71, 0, 71},
+		{71, 0, 71},
+	}
+	for i, point := range history {
+		assert.Empty(t, point.Issue, "version %d", point.Version)
+		assert.Equal(t, usdAmount(expected[i].posted), point.Posted, "posted after version %d", point.Version)
+		assert.Equal(t, usdAmount(expected[i].held), point.Held, "held after version %d", point.Version)
+		assert.Equal(t, usdAmount(expected[i].available), point.Available, "available after version %d", point.Version)
+	}
+
+	health := AccountReport(events)
+	assert.Empty(t, health.Conservation)
+	assert.Equal(t, AccountBalance{Posted: usdAmount(71), Held: usdAmount(0)}, health.Balances["USD"])
+}
+
+func TestBalanceHistoryAppliesReversals(t *testing.T) {
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1)
+	debit := NewLedgerEvent(Debit, usdAmount(40), "acc_1", "corr_2").WithVersion(2)
+	events := []*LedgerEvent{credit, debit, NewReversal(debit, "corr_3").WithVersion(3), NewReversal(credit, "corr_4").WithVersion(4)}
+
+	history := BalanceHistory(events)
+	require.Len(t, history, len(events))
+	for i, posted := range []float64{100, 60, 100, 0} {
+		assert.Equal(t, usdAmount(posted), history[i].Posted, "posted after version %d", history[i].Version)
+		assert.Empty(t, history[i].Issue)
+	}
+}
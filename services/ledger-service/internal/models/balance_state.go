@@ -0,0 +1,295 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+)
+
+var (
+	// ErrAccountMismatch is returned when an event is folded into another account's balance
+	ErrAccountMismatch = errors.New("account mismatch")
+	// ErrReleaseExceedsHold is returned when a release is larger than the amount currently held
+	ErrReleaseExceedsHold = errors.New("release exceeds held amount")
+	// ErrUnknownHold is returned when a release or capture names a hold that is not active
+	ErrUnknownHold = errors.New("unknown hold")
+)
+
+// BalanceRules are the conditions events are folded into a BalanceState under
+type BalanceRules struct {
+	// Now is the instant hold expiry and validity windows are evaluated at
+	Now time.Time
+	// AllowOverdraft lets debits and holds take the available balance below zero
+	AllowOverdraft bool
+}
+
+// HeldFunds is the unreleased remainder of a hold event, in minor units at the balance precision
+type HeldFunds struct {
+	EventID   string     `json:"eventId"`
+	Remaining int64      `json:"remaining"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// live reports whether the hold still counts towards the held amount at now
+func (h HeldFunds) live(now time.Time) bool {
+	return h.Remaining > 0 && (h.ExpiresAt == nil || now.Before(*h.ExpiresAt))
+}
+
+// BalanceState is the fold of an account's events in one currency: the posted balance, the
+// holds against it and the highest version folded. It is the single definition of how events
+// move a balance, shared by the balance projection and the reports built on account streams.
+// Debits, credits and adjustments move the posted balance; holds and releases the held amount.
+// A hold stops counting once it expires. Releases and debits naming a hold with HoldID draw on
+// that hold's remainder, which they may not exceed; other releases draw on live holds oldest
+// first. A reversal does the opposite of the event it reverses: it takes back a credit or
+// adjustment, returns a debit and cancels what is left of a hold, and each event is reversed
+// at most once. Control events, and events outside their validity window, only advance the
+// version.
+type BalanceState struct {
+	AccountID string      `json:"accountId"`
+	Posted    Money       `json:"posted"`
+	Holds     []HeldFunds `json:"holds,omitempty"`
+	// Reversible maps the ID of each folded event a reversal can undo to its type, or to
+	// Reversal once it has been reversed
+	Reversible map[string]EventType `json:"reversible,omitempty"`
+	Version    int64                `json:"version"`
+}
+
+// NewBalanceState creates the state of an account with nothing posted or held, at zero's
+// currency and precision
+func NewBalanceState(accountID string, zero Money) BalanceState {
+	zero.MinorUnits = 0
+	return BalanceState{AccountID: accountID, Posted: zero}
+}
+
+// Clone returns a copy of the state that shares no holds or reversible events with it
+func (s BalanceState) Clone() BalanceState {
+	s.Holds = cloneSlice(s.Holds)
+	if s.Reversible != nil {
+		s.Reversible = maps.Clone(s.Reversible)
+	}
+	return s
+}
+
+// Held returns the amount held by holds live at now
+func (s BalanceState) Held(now time.Time) Money {
+	held := s.Posted
+	held.MinorUnits = 0
+	for _, hold := range s.Holds {
+		if hold.live(now) {
+			held.MinorUnits += hold.Remaining
+		}
+	}
+	return held
+}
+
+// Available returns the posted balance less the amount held at now
+func (s BalanceState) Available(now time.Time) Money {
+	available := s.Posted
+	available.MinorUnits -= s.Held(now).MinorUnits
+	return available
+}
+
+// Apply folds event into the state under rules, leaving the state unchanged if it fails. It
+// fails for an event of another account or currency, an amount the balance precision cannot
+// represent, a debit, hold or reversal the overdraft rule rejects, a release or capture its
+// holds cannot cover, and a reversal of an event already reversed or of unknown type.
+func (s *BalanceState) Apply(event *LedgerEvent, rules BalanceRules) error {
+	if event.AccountID != s.AccountID {
+		return fmt.Errorf("%w: event for %s applied to %s", ErrAccountMismatch, event.AccountID, s.AccountID)
+	}
+	now := rules.Now
+	if event.IsControl() || !event.IsValidAt(now) {
+		s.Version = maxVersion(s.Version, event.Version)
+		return nil
+	}
+	if event.Amount.Currency != s.Posted.Currency {
+		return fmt.Errorf("%w: %s event applied to %s balance", ErrCurrencyMismatch, event.Amount.Currency, s.Posted.Currency)
+	}
+
+	rescaled, err := event.Amount.Rescale(s.Posted.Precision)
+	if err != nil {
+		return err
+	}
+
+	posted := s.Posted
+	amount := rescaled.MinorUnits
+	switch event.Type {
+	case Credit, Adjustment:
+		if posted, err = posted.Add(rescaled); err != nil {
+			return err
+		}
+	case Debit:
+		// A capture draws on funds its hold already reserved, so it needs no available balance
+		if event.HoldID != nil {
+			if err := s.checkHold(*event.HoldID, amount, now); err != nil {
+				return err
+			}
+		} else if err := s.checkAvailable(rescaled, rules); err != nil {
+			return err
+		}
+		if posted, err = posted.Sub(rescaled); err != nil {
+			return err
+		}
+		if event.HoldID != nil {
+			s.releaseHold(*event.HoldID, amount)
+		}
+	case Hold:
+		if err := s.checkAvailable(rescaled, rules); err != nil {
+			return err
+		}
+		s.Holds = append(s.Holds, HeldFunds{EventID: event.ID, Remaining: amount, ExpiresAt: event.ExpiresAt})
+	case Release:
+		if event.HoldID != nil {
+			if err := s.checkHold(*event.HoldID, amount, now); err != nil {
+				return err
+			}
+			s.releaseHold(*event.HoldID, amount)
+			break
+		}
+		held := s.Held(now)
+		if amount > held.MinorUnits {
+			return fmt.Errorf("%w: releasing %.*f with %.*f held", ErrReleaseExceedsHold,
+				rescaled.Precision, rescaled.Float(), held.Precision, held.Float())
+		}
+		s.release(amount, now)
+	case Reversal:
+		if posted, err = s.reverse(event, rules); err != nil {
+			return err
+		}
+	}
+
+	s.Posted = posted
+	s.Version = maxVersion(s.Version, event.Version)
+	switch event.Type {
+	case Debit, Credit, Adjustment, Hold, Release:
+		s.markReversible(event.ID, event.Type)
+	case Reversal:
+		s.markReversible(event.ReversedEventID(), Reversal)
+	}
+	return nil
+}
+
+// reverse returns the posted balance after reversal, cancelling the rest of a reversed hold.
+// The reversed event's type is taken from the events folded so far, falling back to the type
+// recorded on the reversal for originals folded elsewhere.
+func (s *BalanceState) reverse(reversal *LedgerEvent, rules BalanceRules) (Money, error) {
+	originalID := reversal.ReversedEventID()
+	reversedType := reversal.reversedTypeIn(s.Reversible)
+	if reversedType == Reversal {
+		return Money{}, fmt.Errorf("%w: %s by %s", ErrAlreadyReversed, originalID, reversal.ID)
+	}
+
+	delta, err := PostedAmount(reversal, s.Reversible)
+	if err == nil {
+		delta, err = delta.Rescale(s.Posted.Precision)
+	}
+	if err != nil {
+		return Money{}, err
+	}
+	if delta.Sign() < 0 {
+		withdrawn, err := delta.Neg()
+		if err != nil {
+			return Money{}, err
+		}
+		if err := s.checkAvailable(withdrawn, rules); err != nil {
+			return Money{}, err
+		}
+	}
+	posted, err := s.Posted.Add(delta)
+	if err != nil {
+		return Money{}, err
+	}
+	if reversedType == Hold {
+		s.cancelHold(originalID)
+	}
+	return posted, nil
+}
+
+// markReversible records the type of the folded event id
+func (s *BalanceState) markReversible(id string, eventType EventType) {
+	if s.Reversible == nil {
+		s.Reversible = make(map[string]EventType)
+	}
+	s.Reversible[id] = eventType
+}
+
+// cancelHold drops whatever is left of the hold holdID
+func (s *BalanceState) cancelHold(holdID string) {
+	for i := range s.Holds {
+		if s.Holds[i].EventID == holdID {
+			s.Holds[i].Remaining = 0
+			return
+		}
+	}
+}
+
+// release consumes amount from live holds, oldest first
+func (s *BalanceState) release(amount int64, now time.Time) {
+	for i := range s.Holds {
+		if amount <= 0 {
+			return
+		}
+		if !s.Holds[i].live(now) {
+			continue
+		}
+		consumed := amount
+		if s.Holds[i].Remaining < consumed {
+			consumed = s.Holds[i].Remaining
+		}
+		s.Holds[i].Remaining -= consumed
+		amount -= consumed
+	}
+}
+
+// checkHold checks that the unexpired hold holdID has at least amount left to release or capture
+func (s *BalanceState) checkHold(holdID string, amount int64, now time.Time) error {
+	for _, hold := range s.Holds {
+		if hold.EventID != holdID {
+			continue
+		}
+		if hold.ExpiresAt != nil && !now.Before(*hold.ExpiresAt) {
+			break
+		}
+		if amount > hold.Remaining {
+			precision := s.Posted.Precision
+			return fmt.Errorf("%w: drawing %.*f on hold %s with %.*f left", ErrReleaseExceedsHold,
+				precision, NewMoney(amount, "", precision).Float(), holdID,
+				precision, NewMoney(hold.Remaining, "", precision).Float())
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownHold, holdID)
+}
+
+// releaseHold consumes amount from the hold holdID, which checkHold has checked
+func (s *BalanceState) releaseHold(holdID string, amount int64) {
+	for i := range s.Holds {
+		if s.Holds[i].EventID == holdID {
+			s.Holds[i].Remaining -= amount
+			return
+		}
+	}
+}
+
+// checkAvailable enforces the overdraft rule for an amount leaving the available balance
+func (s *BalanceState) checkAvailable(amount Money, rules BalanceRules) error {
+	if rules.AllowOverdraft {
+		return nil
+	}
+	available := s.Available(rules.Now)
+	if amount.MinorUnits > available.MinorUnits {
+		return fmt.Errorf("%w: %.*f requested, %.*f available", ErrInsufficientFunds,
+			amount.Precision, amount.Float(), available.Precision, available.Float())
+	}
+	return nil
+}
+
+// maxVersion keeps the version at the highest folded event when events are replayed out of version order
+func maxVersion(current, folded int64) int64 {
+	if folded > current {
+		return folded
+	}
+	return current
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUSDState(t *testing.T) BalanceState {
+	t.Helper()
+	zero, err := ZeroMoney("USD")
+	require.NoError(t, err)
+	return NewBalanceState("acc_1", zero)
+}
+
+func TestBalanceStateReversalUndoesOriginal(t *testing.T) {
+	rules := BalanceRules{Now: time.Now()}
+	state := newUSDState(t)
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1)
+	debit := NewLedgerEvent(Debit, usdAmount(30), "acc_1", "corr_2").WithVersion(2)
+	for _, event := range []*LedgerEvent{credit, debit} {
+		require.NoError(t, state.Apply(event, rules))
+	}
+
+	require.NoError(t, state.Apply(NewReversal(debit, "corr_3").WithVersion(3), rules))
+	assert.Equal(t, usdAmount(100), state.Posted, "a reversed debit is returned")
+
+	require.NoError(t, state.Apply(NewReversal(credit, "corr_4").WithVersion(4), rules))
+	assert.Equal(t, usdAmount(0), state.Posted, "a reversed credit is taken back")
+	assert.Equal(t, int64(4), state.Version)
+
+	err := state.Apply(NewReversal(debit, "corr_5").WithVersion(5), rules)
+	assert.ErrorIs(t, err, ErrAlreadyReversed)
+	assert.Equal(t, usdAmount(0), state.Posted)
+}
+
+func TestBalanceStateReversalOfUnfoldedOriginal(t *testing.T) {
+	rules := BalanceRules{Now: time.Now()}
+	state := newUSDState(t)
+	debit := NewLedgerEvent(Debit, usdAmount(30), "acc_1", "corr_1").WithVersion(1)
+
+	require.NoError(t, state.Apply(NewReversal(debit, "corr_2").WithVersion(2), rules))
+	assert.Equal(t, usdAmount(30), state.Posted, "the type recorded on the reversal is used")
+
+	unrecorded := NewLedgerEvent(Reversal, usdAmount(10), "acc_1", "corr_3").WithReverses("evt_elsewhere").WithVersion(3)
+	assert.ErrorIs(t, state.Apply(unrecorded, rules), ErrUnknownReversedType)
+}
+
+func TestBalanceStateReversalObeysOverdraftRule(t *testing.T) {
+	rules := BalanceRules{Now: time.Now()}
+	state := newUSDState(t)
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, state.Apply(credit, rules))
+	require.NoError(t, state.Apply(NewLedgerEvent(Debit, usdAmount(80), "acc_1", "corr_2").WithVersion(2), rules))
+
+	reversal := NewReversal(credit, "corr_3").WithVersion(3)
+	assert.ErrorIs(t, state.Apply(reversal, rules), ErrInsufficientFunds)
+
+	rules.AllowOverdraft = true
+	require.NoError(t, state.Apply(reversal, rules))
+	assert.Equal(t, usdAmount(-80), state.Posted)
+}
+
+func TestBalanceStateReversedHoldIsCancelled(t *testing.T) {
+	rules := BalanceRules{Now: time.Now()}
+	state := newUSDState(t)
+	hold := NewLedgerEvent(Hold, usdAmount(40), "acc_1", "corr_2").WithVersion(2)
+	require.NoError(t, state.Apply(NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1), rules))
+	require.NoError(t, state.Apply(hold, rules))
+
+	require.NoError(t, state.Apply(NewReversal(hold, "corr_3").WithVersion(3), rules))
+	assert.Equal(t, usdAmount(100), state.Posted)
+	assert.Equal(t, usdAmount(0), state.Held(rules.Now))
+}
+
+func TestBalanceStateCloneCopiesReversible(t *testing.T) {
+	rules := BalanceRules{Now: time.Now()}
+	state := newUSDState(t)
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, state.Apply(credit, rules))
+
+	clone := state.Clone()
+	require.NoError(t, clone.Apply(NewReversal(credit, "corr_2").WithVersion(2), rules))
+	assert.Equal(t, Credit, state.Reversible[credit.ID])
+	assert.Equal(t, usdAmount(100), state.Posted)
+}
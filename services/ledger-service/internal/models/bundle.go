@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ErrBundleTampered is returned when a bundle's contents do not match its signed root and manifest
+var ErrBundleTampered = errors.New("bundle tampered")
+
+// BundleManifest summarizes the events in an export bundle
+type BundleManifest struct {
+	Count      int       `json:"count"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	AccountIDs []string  `json:"accountIds"`
+}
+
+// Bundle is a self-verifying export of events for auditors. The signature covers the
+// Merkle root of the events together with the manifest.
+type Bundle struct {
+	Events     []*LedgerEvent `json:"events"`
+	MerkleRoot string         `json:"merkleRoot"`
+	Manifest   BundleManifest `json:"manifest"`
+	KeyID      string         `json:"keyId"`
+	Algorithm  string         `json:"algorithm"`
+	Signature  string         `json:"signature"`
+}
+
+// ExportBundle packages the events with their Merkle root and manifest, signed by signer
+func ExportBundle(events []*LedgerEvent, signer Signer) (Bundle, error) {
+	root, err := MerkleRoot(events)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("failed to compute bundle root: %w", err)
+	}
+
+	bundle := Bundle{
+		Events:     events,
+		MerkleRoot: hex.EncodeToString(root[:]),
+		Manifest:   manifestFor(events),
+		KeyID:      signer.KeyID(),
+		Algorithm:  signer.Algorithm(),
+	}
+
+	payload, err := bundle.signedPayload()
+	if err != nil {
+		return Bundle{}, err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return Bundle{}, fmt.Errorf("failed to sign bundle: %w", err)
+	}
+	bundle.Signature = hex.EncodeToString(signature)
+	return bundle, nil
+}
+
+// VerifyBundle checks the bundle signature and that its events still match the signed root and manifest
+func VerifyBundle(bundle Bundle, verifier Verifier) error {
+	if bundle.KeyID != verifier.KeyID() {
+		return fmt.Errorf("%w: bundle signed with key %q, verifier has %q", ErrInvalidSignature, bundle.KeyID, verifier.KeyID())
+	}
+
+	payload, err := bundle.signedPayload()
+	if err != nil {
+		return err
+	}
+	signature, err := hex.DecodeString(bundle.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	if err := verifier.Verify(payload, signature); err != nil {
+		return err
+	}
+
+	root, err := MerkleRoot(bundle.Events)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBundleTampered, err)
+	}
+	if hex.EncodeToString(root[:]) != bundle.MerkleRoot {
+		return fmt.Errorf("%w: events do not match merkle root", ErrBundleTampered)
+	}
+	if !reflect.DeepEqual(manifestFor(bundle.Events), bundle.Manifest) {
+		return fmt.Errorf("%w: events do not match manifest", ErrBundleTampered)
+	}
+	return nil
+}
+
+// signedPayload returns the bytes covered by the bundle signature
+func (b Bundle) signedPayload() ([]byte, error) {
+	payload, err := json.Marshal(struct {
+		MerkleRoot string         `json:"merkleRoot"`
+		Manifest   BundleManifest `json:"manifest"`
+	}{b.MerkleRoot, b.Manifest})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bundle for signing: %w", err)
+	}
+	return payload, nil
+}
+
+// manifestFor summarizes events; timestamps are normalized to UTC so the manifest survives a JSON round trip
+func manifestFor(events []*LedgerEvent) BundleManifest {
+	manifest := BundleManifest{Count: len(events), AccountIDs: []string{}}
+	seen := make(map[string]bool)
+	for i, event := range events {
+		ts := event.Timestamp.UTC()
+		if i == 0 || ts.Before(manifest.From) {
+			manifest.From = ts
+		}
+		if i == 0 || ts.After(manifest.To) {
+			manifest.To = ts
+		}
+		if !seen[event.AccountID] {
+			seen[event.AccountID] = true
+			manifest.AccountIDs = append(manifest.AccountIDs, event.AccountID)
+		}
+	}
+	sort.Strings(manifest.AccountIDs)
+	return manifest
+}
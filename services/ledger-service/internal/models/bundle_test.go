@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundleEvents() []*LedgerEvent {
+	events := make([]*LedgerEvent, 0, 3)
+	for version := int64(1); version <= 3; version++ {
+		events = append(events, NewLedgerEvent(Credit, NewMoney(int64(version)*1000, "USD", 2), "acc_1", "corr_1").
+			WithVersion(version))
+	}
+	return events
+}
+
+func TestVerifyBundle(t *testing.T) {
+	key := NewHMACKey("audit-2026", []byte("secret"))
+
+	bundle, err := ExportBundle(bundleEvents(), key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, bundle.Manifest.Count)
+	assert.Equal(t, []string{"acc_1"}, bundle.Manifest.AccountIDs)
+	require.NoError(t, VerifyBundle(bundle, key))
+
+	encoded, err := json.Marshal(bundle)
+	require.NoError(t, err)
+	var decoded Bundle
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.NoError(t, VerifyBundle(decoded, key))
+}
+
+func TestVerifyBundleDetectsRemovedEvent(t *testing.T) {
+	key := NewHMACKey("audit-2026", []byte("secret"))
+	bundle, err := ExportBundle(bundleEvents(), key)
+	require.NoError(t, err)
+
+	bundle.Events = append(bundle.Events[:1], bundle.Events[2:]...)
+
+	assert.ErrorIs(t, VerifyBundle(bundle, key), ErrBundleTampered)
+}
+
+func TestVerifyBundleDetectsAlteredEvent(t *testing.T) {
+	key := NewHMACKey("audit-2026", []byte("secret"))
+	bundle, err := ExportBundle(bundleEvents(), key)
+	require.NoError(t, err)
+
+	bundle.Events[1].Amount.MinorUnits = 100000
+
+	assert.ErrorIs(t, VerifyBundle(bundle, key), ErrBundleTampered)
+}
+
+func TestVerifyBundleRejectsWrongKey(t *testing.T) {
+	bundle, err := ExportBundle(bundleEvents(), NewHMACKey("audit-2026", []byte("secret")))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, VerifyBundle(bundle, NewHMACKey("audit-2026", []byte("other"))), ErrInvalidSignature)
+}
@@ -0,0 +1,253 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrEventNotFound is returned when an event ID is not present in a stream
+var ErrEventNotFound = errors.New("event not found")
+
+// Events are chained by hash: each event's chain hash is SHA-256(previousHash || digest), where
+// digest is the SHA-256 of the event's canonical bytes and previousHash is the chain hash of the
+// event before it (zero bytes for the first event). The canonical bytes do not include
+// PreviousHash, so an event's digest is fixed when it is signed and chaining never alters it.
+//
+// A chain may keep its hashes truncated to HashLength bytes to save storage, both as the hash an
+// event records and as the previous hash fed into the next. The trade-off is the collision bound:
+// forging a link that collides with an n-byte hash takes about 2^(4n) work by the birthday bound,
+// 2^64 at the minimum of 16 bytes against 2^128 at the full 32. Every event records the length
+// it was chained at, and a chain that mixes lengths does not verify.
+
+// Hash lengths in bytes for chain hashes
+const (
+	// FullHashLength is the untruncated SHA-256 length, used when HashLength is 0
+	FullHashLength = 32
+	// MinHashLength is the shortest truncation allowed
+	MinHashLength = 16
+)
+
+// WithHashLength sets the length, in bytes, the event's chain hashes are truncated to; 0
+// keeps them at FullHashLength. Set it before ChainAfter, to the length of the chain the event
+// joins.
+func (e *LedgerEvent) WithHashLength(length int) *LedgerEvent {
+	e.HashLength = length
+	return e
+}
+
+// hashLength returns the length of the event's chain hashes in bytes
+func (e *LedgerEvent) hashLength() int {
+	if e.HashLength == 0 {
+		return FullHashLength
+	}
+	return e.HashLength
+}
+
+// checkHashLength rejects hash lengths outside [MinHashLength, FullHashLength]; 0 is full length
+func checkHashLength(length int) error {
+	if length != 0 && (length < MinHashLength || length > FullHashLength) {
+		return fmt.Errorf("hash length must be between %d and %d bytes, got %d", MinHashLength, FullHashLength, length)
+	}
+	return nil
+}
+
+// ComputeHash returns the hex-encoded chain hash of the event, truncated to its hash length, or
+// "" if it cannot be canonicalized
+func (e *LedgerEvent) ComputeHash() string {
+	hash, err := e.chainHash()
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash)
+}
+
+// ChainAfter links the event to prev by setting PreviousHash to prev's chain hash; a nil prev
+// starts a new chain
+func (e *LedgerEvent) ChainAfter(prev *LedgerEvent) *LedgerEvent {
+	e.PreviousHash = ""
+	if prev != nil {
+		e.PreviousHash = prev.ComputeHash()
+	}
+	return e
+}
+
+// VerifyChain checks that each event links to the one before it, starting from the first
+// event's PreviousHash. A break is reported as a *ChainBreakError carrying its index.
+func VerifyChain(events []*LedgerEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return VerifyChainFrom(events[0].PreviousHash, events)
+}
+
+// chainHash returns SHA-256(previousHash || digest) truncated to the event's hash length
+func (e *LedgerEvent) chainHash() ([]byte, error) {
+	length := e.hashLength()
+	if err := checkHashLength(length); err != nil {
+		return nil, err
+	}
+	previous := make([]byte, length)
+	if e.PreviousHash != "" {
+		decoded, err := hex.DecodeString(e.PreviousHash)
+		if err != nil || len(decoded) != length {
+			return nil, fmt.Errorf("malformed %d-byte chain hash %q", length, e.PreviousHash)
+		}
+		previous = decoded
+	}
+	digest, err := e.hash()
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(append(previous, digest[:]...))
+	return hash[:length], nil
+}
+
+// decodeHash parses a full-length hex chain hash; the empty string is the zero hash that starts
+// a chain
+func decodeHash(encoded string) ([32]byte, error) {
+	var hash [32]byte
+	if encoded == "" {
+		return hash, nil
+	}
+	decoded, err := hex.DecodeString(encoded)
+	if err != nil || len(decoded) != len(hash) {
+		return hash, fmt.Errorf("malformed chain hash %q", encoded)
+	}
+	copy(hash[:], decoded)
+	return hash, nil
+}
+
+// Proof shows that an event follows a known chain hash without shipping the events in between.
+// It carries the digest of each event from just after Start up to and including the target.
+type Proof struct {
+	Start      [32]byte
+	Digests    [][32]byte
+	TargetID   string
+	TargetHash [32]byte
+}
+
+// ChainProof builds a proof for the event targetID from a chained slice of events. The first
+// event's PreviousHash is the proof's starting point, so passing the events after a checkpoint
+// yields the minimal proof from that checkpoint. Proofs need full-length chain hashes.
+func ChainProof(events []*LedgerEvent, targetID string) (Proof, error) {
+	if len(events) == 0 {
+		return Proof{}, ErrEmptyBatch
+	}
+
+	start, err := decodeHash(events[0].PreviousHash)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	proof := Proof{Start: start, TargetID: targetID}
+	current := start
+	for _, event := range events {
+		previous, err := decodeHash(event.PreviousHash)
+		if err != nil {
+			return Proof{}, err
+		}
+		if previous != current {
+			return Proof{}, fmt.Errorf("chain broken at event %s", event.ID)
+		}
+
+		digest, err := event.hash()
+		if err != nil {
+			return Proof{}, fmt.Errorf("failed to hash event %s: %w", event.ID, err)
+		}
+		proof.Digests = append(proof.Digests, digest)
+		current = hashPair(current, digest)
+
+		if event.ID == targetID {
+			proof.TargetHash = current
+			return proof, nil
+		}
+	}
+	return Proof{}, fmt.Errorf("%w: %s", ErrEventNotFound, targetID)
+}
+
+// VerifyChainProof recomputes the chain from the proof's start and reports whether it passes
+// through checkpointHash and ends at the proof's target hash. Callers holding the target event
+// should also check that its ComputeHash matches TargetHash.
+func VerifyChainProof(proof Proof, checkpointHash [32]byte) bool {
+	if len(proof.Digests) == 0 {
+		return false
+	}
+
+	current := proof.Start
+	seen := current == checkpointHash
+	last := len(proof.Digests) - 1
+	for _, digest := range proof.Digests[:last] {
+		current = hashPair(current, digest)
+		seen = seen || current == checkpointHash
+	}
+	return seen && hashPair(current, proof.Digests[last]) == proof.TargetHash
+}
+
+// chainProofVersion tags the binary proof layout
+const chainProofVersion = 1
+
+// MarshalBinary encodes the proof as: version (1 byte), start (32), target hash (32),
+// target ID length (2, big endian) and bytes, digest count (4, big endian) and digests (32 each)
+func (p Proof) MarshalBinary() ([]byte, error) {
+	if len(p.TargetID) > 0xffff {
+		return nil, fmt.Errorf("target ID too long: %d bytes", len(p.TargetID))
+	}
+
+	buf := make([]byte, 0, 1+32+32+2+len(p.TargetID)+4+32*len(p.Digests))
+	buf = append(buf, chainProofVersion)
+	buf = append(buf, p.Start[:]...)
+	buf = append(buf, p.TargetHash[:]...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(p.TargetID)))
+	buf = append(buf, p.TargetID...)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(len(p.Digests)))
+	for _, digest := range p.Digests {
+		buf = append(buf, digest[:]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a proof produced by MarshalBinary
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	malformed := errors.New("malformed chain proof")
+	if len(data) < 1+32+32+2 || data[0] != chainProofVersion {
+		return malformed
+	}
+	data = data[1:]
+
+	var proof Proof
+	copy(proof.Start[:], data[:32])
+	copy(proof.TargetHash[:], data[32:64])
+	data = data[64:]
+
+	idLen := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if len(data) < idLen+4 {
+		return malformed
+	}
+	proof.TargetID = string(data[:idLen])
+	data = data[idLen:]
+
+	count := int(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if len(data) != 32*count {
+		return malformed
+	}
+	proof.Digests = make([][32]byte, count)
+	for i := range proof.Digests {
+		copy(proof.Digests[i][:], data[32*i:32*(i+1)])
+	}
+
+	*p = proof
+	return nil
+}
+
+// hashPair returns SHA-256(left || right)
+func hashPair(left, right [32]byte) [32]byte {
+	var buf [64]byte
+	copy(buf[:32], left[:])
+	copy(buf[32:], right[:])
+	return sha256.Sum256(buf[:])
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainedEvents(t *testing.T, n int) []*LedgerEvent {
+	t.Helper()
+	events := make([]*LedgerEvent, n)
+	for i := range events {
+		events[i] = NewLedgerEvent(Credit, NewMoney(int64(i+1)*100, "USD", 2), "acc_1", "corr_1").
+			WithVersion(int64(i + 1))
+		if i > 0 {
+			events[i].ChainAfter(events[i-1])
+		}
+	}
+	return events
+}
+
+func mustHash(t *testing.T, event *LedgerEvent) [32]byte {
+	t.Helper()
+	hash, err := decodeHash(event.ComputeHash())
+	require.NoError(t, err)
+	return hash
+}
+
+func TestChainProofFromCheckpoint(t *testing.T) {
+	events := chainedEvents(t, 6)
+	checkpoint := mustHash(t, events[1])
+	target := events[4]
+
+	proof, err := ChainProof(events[2:], target.ID)
+	require.NoError(t, err)
+
+	assert.Len(t, proof.Digests, 3)
+	assert.Equal(t, target.ComputeHash(), hex.EncodeToString(proof.TargetHash[:]))
+	assert.True(t, VerifyChainProof(proof, checkpoint))
+	assert.False(t, VerifyChainProof(proof, mustHash(t, events[5])), "checkpoint after the target")
+	assert.False(t, VerifyChainProof(proof, mustHash(t, events[0])), "checkpoint before the proof start")
+}
+
+func TestChainProofFromGenesisPassesEarlierCheckpoint(t *testing.T) {
+	events := chainedEvents(t, 6)
+	proof, err := ChainProof(events, events[4].ID)
+	require.NoError(t, err)
+
+	assert.True(t, VerifyChainProof(proof, mustHash(t, events[1])))
+}
+
+func TestChainProofDetectsTampering(t *testing.T) {
+	events := chainedEvents(t, 6)
+	proof, err := ChainProof(events[2:], events[4].ID)
+	require.NoError(t, err)
+
+	proof.Digests[1][0] ^= 0xff
+	assert.False(t, VerifyChainProof(proof, mustHash(t, events[1])))
+
+	events[3].Amount.MinorUnits = 40000
+	_, err = ChainProof(events[2:], events[4].ID)
+	assert.Error(t, err)
+}
+
+func TestChainProofBinaryRoundTrip(t *testing.T) {
+	events := chainedEvents(t, 4)
+	proof, err := ChainProof(events[1:], events[3].ID)
+	require.NoError(t, err)
+
+	encoded, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, encoded, 1+32+32+2+len(events[3].ID)+4+3*32)
+
+	var decoded Proof
+	require.NoError(t, decoded.UnmarshalBinary(encoded))
+	assert.Equal(t, proof, decoded)
+	assert.True(t, VerifyChainProof(decoded, mustHash(t, events[0])))
+
+	assert.Error(t, decoded.UnmarshalBinary(encoded[:len(encoded)-1]))
+}
+
+func TestChainProofUnknownTarget(t *testing.T) {
+	_, err := ChainProof(chainedEvents(t, 2), "evt_missing")
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestVerifyChainReportsBreakIndex(t *testing.T) {
+	events := chainedEvents(t, 5)
+	require.NoError(t, VerifyChain(events))
+	require.NoError(t, VerifyChain(events[2:]), "a slice starting mid-chain verifies from its first link")
+
+	roundTripped := make([]*LedgerEvent, len(events))
+	for i, event := range events {
+		data, err := event.ToJSON()
+		require.NoError(t, err)
+		roundTripped[i], err = LedgerEventFromJSON(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, VerifyChain(roundTripped), "hashes are stable across serialization")
+
+	require.NoError(t, events[2].SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+	require.NoError(t, VerifyChain(events), "the signature is not part of the hash")
+
+	events[2].Amount.MinorUnits = 999
+	var chainErr *ChainBreakError
+	require.ErrorAs(t, VerifyChain(events), &chainErr)
+	assert.Equal(t, 3, chainErr.Index)
+	assert.Equal(t, events[3].ID, chainErr.EventID)
+}
+
+func TestTruncatedChainVerifies(t *testing.T) {
+	events := make([]*LedgerEvent, 4)
+	for i := range events {
+		events[i] = NewLedgerEvent(Credit, usdAmount(float64(i+1)), "acc_1", "corr_1").
+			WithVersion(int64(i + 1)).
+			WithHashLength(16)
+		if i > 0 {
+			events[i].ChainAfter(events[i-1])
+		}
+		require.NoError(t, events[i].Validate())
+	}
+	assert.Len(t, events[3].PreviousHash, 32)
+	assert.Len(t, events[3].ComputeHash(), 32)
+	require.NoError(t, VerifyChain(events))
+
+	encoded, err := events[2].ToJSON()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, events[2].ComputeHash(), decoded.ComputeHash(), "the length survives a round trip")
+
+	events[2].Metadata["tampered"] = true
+	var chainErr *ChainBreakError
+	require.ErrorAs(t, VerifyChain(events), &chainErr)
+	assert.Equal(t, 3, chainErr.Index)
+}
+
+func TestChainRejectsMixedHashLengths(t *testing.T) {
+	full := NewLedgerEvent(Credit, usdAmount(1), "acc_1", "corr_1").WithVersion(1)
+	truncated := NewLedgerEvent(Credit, usdAmount(2), "acc_1", "corr_1").WithVersion(2).WithHashLength(16)
+	truncated.PreviousHash = full.ComputeHash()
+
+	var chainErr *ChainBreakError
+	require.ErrorAs(t, VerifyChain([]*LedgerEvent{full, truncated}), &chainErr)
+	assert.Equal(t, 1, chainErr.Index)
+	assert.Empty(t, truncated.ComputeHash(), "a 32-byte previous hash cannot feed a 16-byte chain")
+
+	truncated.PreviousHash = full.ComputeHash()[:32]
+	require.ErrorAs(t, VerifyChain([]*LedgerEvent{full, truncated}), &chainErr)
+	assert.Contains(t, chainErr.Reason, "16-byte hashes after a 32-byte hash")
+
+	assert.Error(t, NewLedgerEvent(Credit, usdAmount(1), "acc_1", "corr_1").WithVersion(1).WithHashLength(8).Validate())
+}
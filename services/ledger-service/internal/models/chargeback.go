@@ -0,0 +1,180 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidChargebackTransition is returned when a chargeback event does not follow from the
+// dispute's current stage
+var ErrInvalidChargebackTransition = errors.New("invalid chargeback transition")
+
+// MetaChargebackDeadline records the deadline a chargeback stage must be answered by, as RFC 3339
+const MetaChargebackDeadline = "chargebackDeadline"
+
+// chargebackTransitions lists the stages each chargeback stage may move to. A dispute starts
+// when it is received and ends when it is won or lost; a received chargeback can be settled
+// directly, for example when the merchant accepts the loss or the issuer withdraws it.
+var chargebackTransitions = map[EventType][]EventType{
+	"":                    {ChargebackReceived},
+	ChargebackReceived:    {ChargebackRepresented, ChargebackWon, ChargebackLost},
+	ChargebackRepresented: {ChargebackArbitration, ChargebackWon, ChargebackLost},
+	ChargebackArbitration: {ChargebackWon, ChargebackLost},
+}
+
+// IsChargeback returns true if the event is a chargeback lifecycle event
+func (e *LedgerEvent) IsChargeback() bool {
+	switch e.Type {
+	case ChargebackReceived, ChargebackRepresented, ChargebackArbitration, ChargebackWon, ChargebackLost:
+		return true
+	}
+	return false
+}
+
+// NewChargeback records a chargeback received against disputed for amount, which must be
+// answered by respondBy
+func NewChargeback(disputed *LedgerEvent, amount Money, correlationID string, respondBy time.Time) *LedgerEvent {
+	chargeback := NewLedgerEvent(ChargebackReceived, amount, disputed.AccountID, correlationID).
+		AddReference(RefDisputes, disputed.ID).
+		WithMetadata(MetaChargebackDeadline, respondBy.UTC().Format(time.RFC3339))
+	chargeback.PaymentID = disputed.PaymentID
+	return chargeback
+}
+
+// NextChargebackStage records the dispute of previous moving to stage, carrying its disputed
+// amount. A nil deadline records a stage with nothing left to answer, such as a final one.
+func NextChargebackStage(previous *LedgerEvent, stage EventType, correlationID string, deadline *time.Time) *LedgerEvent {
+	next := NewLedgerEvent(stage, previous.Amount, previous.AccountID, correlationID)
+	for _, disputedID := range previous.ReferencesOfKind(RefDisputes) {
+		next.AddReference(RefDisputes, disputedID)
+	}
+	if deadline != nil {
+		next.WithMetadata(MetaChargebackDeadline, deadline.UTC().Format(time.RFC3339))
+	}
+	next.PaymentID = previous.PaymentID
+	return next
+}
+
+// ChargebackState is a dispute folded from its chargeback events
+type ChargebackState struct {
+	// DisputedEventID is the event the chargeback disputes
+	DisputedEventID string
+	AccountID       string
+	// Stage is the type of the dispute's latest chargeback event
+	Stage EventType
+	// Disputed is the amount in dispute as of the latest stage
+	Disputed Money
+	// Deadline is the deadline of the latest stage, nil if it has none
+	Deadline *time.Time
+	// Deadlines records the deadline set by each stage that had one
+	Deadlines map[EventType]time.Time
+	// Reversal is the reversal of the disputed amount, set only once the dispute is lost
+	Reversal *LedgerEvent
+}
+
+// IsFinal reports whether the dispute has been won or lost
+func (s *ChargebackState) IsFinal() bool {
+	return s.Stage == ChargebackWon || s.Stage == ChargebackLost
+}
+
+// ResolveChargeback folds a dispute's chargeback events, in order, into its current state.
+// Events that are not chargeback events are ignored. It returns ErrInvalidChargebackTransition
+// for an event that does not follow from the stage before it or that belongs to another
+// dispute. A lost dispute yields a NewReversal of the disputed event for the disputed amount,
+// so events must then include the disputed event; the reversal is built afresh on every call,
+// so callers append it once.
+func ResolveChargeback(events []*LedgerEvent) (*ChargebackState, error) {
+	var (
+		state *ChargebackState
+		last  *LedgerEvent
+	)
+	for _, event := range events {
+		if !event.IsChargeback() {
+			continue
+		}
+
+		var stage EventType
+		if state != nil {
+			stage = state.Stage
+		}
+		if !canTransition(stage, event.Type) {
+			return nil, fmt.Errorf("%w: %s after %q in event %s", ErrInvalidChargebackTransition, event.Type, stage, event.ID)
+		}
+
+		disputed := event.ReferencesOfKind(RefDisputes)
+		if len(disputed) != 1 {
+			return nil, fmt.Errorf("%w: event %s must dispute exactly one event", ErrInvalidChargebackTransition, event.ID)
+		}
+		if state == nil {
+			state = &ChargebackState{
+				DisputedEventID: disputed[0],
+				AccountID:       event.AccountID,
+				Deadlines:       make(map[EventType]time.Time),
+			}
+		} else if disputed[0] != state.DisputedEventID || event.AccountID != state.AccountID {
+			return nil, fmt.Errorf("%w: event %s belongs to another dispute", ErrInvalidChargebackTransition, event.ID)
+		}
+
+		deadline, err := chargebackDeadline(event)
+		if err != nil {
+			return nil, err
+		}
+		state.Stage = event.Type
+		state.Disputed = event.Amount
+		state.Deadline = deadline
+		if deadline != nil {
+			state.Deadlines[event.Type] = *deadline
+		}
+		last = event
+	}
+	if state == nil {
+		return nil, fmt.Errorf("%w: no chargeback events", ErrInvalidChargebackTransition)
+	}
+
+	if state.Stage == ChargebackLost {
+		original := findEvent(events, state.DisputedEventID)
+		if original == nil {
+			return nil, fmt.Errorf("%w: disputed event %s is needed to reverse the lost dispute", ErrInvalidChargebackTransition, state.DisputedEventID)
+		}
+		reversal := NewReversal(original, last.CorrelationID)
+		reversal.Amount, reversal.Currency = state.Disputed, state.Disputed.Currency
+		reversal.PaymentID = last.PaymentID
+		state.Reversal = reversal
+	}
+	return state, nil
+}
+
+// findEvent returns the event with the given ID, or nil if events do not hold it
+func findEvent(events []*LedgerEvent, id string) *LedgerEvent {
+	for _, event := range events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
+
+// canTransition reports whether a dispute at stage may move to next
+func canTransition(stage, next EventType) bool {
+	for _, allowed := range chargebackTransitions[stage] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// chargebackDeadline reads the deadline recorded on a chargeback event
+func chargebackDeadline(event *LedgerEvent) (*time.Time, error) {
+	raw, ok := event.Metadata[MetaChargebackDeadline]
+	if !ok {
+		return nil, nil
+	}
+	encoded, _ := raw.(string)
+	deadline, err := time.Parse(time.RFC3339, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("event %s has malformed chargeback deadline %v", event.ID, raw)
+	}
+	return &deadline, nil
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargebackRepresentedAndWonHasNoReversal(t *testing.T) {
+	payment := NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "acc_1", "corr_pay").WithPaymentID("pay_1")
+	respondBy := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	received := NewChargeback(payment, NewMoney(5000, "USD", 2), "corr_cb", respondBy)
+	require.NoError(t, received.Validate())
+
+	decideBy := respondBy.AddDate(0, 0, 30)
+	represented := NextChargebackStage(received, ChargebackRepresented, "corr_cb", &decideBy)
+	won := NextChargebackStage(represented, ChargebackWon, "corr_cb", nil)
+
+	state, err := ResolveChargeback([]*LedgerEvent{payment, received, represented, won})
+	require.NoError(t, err)
+	assert.Equal(t, ChargebackWon, state.Stage)
+	assert.True(t, state.IsFinal())
+	assert.Equal(t, payment.ID, state.DisputedEventID)
+	assert.Equal(t, map[EventType]time.Time{ChargebackReceived: respondBy, ChargebackRepresented: decideBy}, state.Deadlines)
+	assert.Nil(t, state.Deadline)
+	assert.Nil(t, state.Reversal)
+	assert.False(t, won.AffectsBalance())
+}
+
+func TestChargebackLostProducesReversal(t *testing.T) {
+	payment := NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "acc_1", "corr_pay").WithPaymentID("pay_1")
+	received := NewChargeback(payment, NewMoney(2000, "USD", 2), "corr_cb", time.Now().Add(24*time.Hour))
+
+	state, err := ResolveChargeback([]*LedgerEvent{received})
+	require.NoError(t, err)
+	assert.Equal(t, ChargebackReceived, state.Stage)
+	require.NotNil(t, state.Deadline)
+	assert.Nil(t, state.Reversal, "no reversal before the dispute is lost")
+
+	lost := NextChargebackStage(received, ChargebackLost, "corr_cb", nil)
+	_, err = ResolveChargeback([]*LedgerEvent{received, lost})
+	assert.ErrorIs(t, err, ErrInvalidChargebackTransition, "the reversal needs the disputed event")
+
+	state, err = ResolveChargeback([]*LedgerEvent{payment, received, lost})
+	require.NoError(t, err)
+	require.NotNil(t, state.Reversal)
+	assert.Equal(t, Reversal, state.Reversal.Type)
+	assert.Equal(t, Debit, state.Reversal.ReversedType())
+	assert.True(t, state.Reversal.Amount.Equal(NewMoney(2000, "USD", 2)))
+	assert.Equal(t, []string{payment.ID}, state.Reversal.ReferencesOfKind(RefReverses))
+	assert.Equal(t, "pay_1", *state.Reversal.PaymentID)
+	require.NoError(t, state.Reversal.Validate())
+}
+
+func TestChargebackRejectsInvalidTransitions(t *testing.T) {
+	payment := NewLedgerEvent(Debit, NewMoney(5000, "USD", 2), "acc_1", "corr_pay")
+	received := NewChargeback(payment, payment.Amount, "corr_cb", time.Now())
+	won := NextChargebackStage(received, ChargebackWon, "corr_cb", nil)
+
+	_, err := ResolveChargeback([]*LedgerEvent{NextChargebackStage(received, ChargebackRepresented, "corr_cb", nil)})
+	assert.ErrorIs(t, err, ErrInvalidChargebackTransition, "a dispute starts when received")
+
+	_, err = ResolveChargeback([]*LedgerEvent{received, NextChargebackStage(received, ChargebackArbitration, "corr_cb", nil)})
+	assert.ErrorIs(t, err, ErrInvalidChargebackTransition, "arbitration follows representment")
+
+	_, err = ResolveChargeback([]*LedgerEvent{received, won, NextChargebackStage(won, ChargebackLost, "corr_cb", nil)})
+	assert.ErrorIs(t, err, ErrInvalidChargebackTransition, "final stages are final")
+
+	other := NewChargeback(NewLedgerEvent(Debit, payment.Amount, "acc_1", "corr_2"), payment.Amount, "corr_cb", time.Now())
+	_, err = ResolveChargeback([]*LedgerEvent{received, NextChargebackStage(other, ChargebackLost, "corr_cb", nil)})
+	assert.ErrorIs(t, err, ErrInvalidChargebackTransition, "events of another dispute")
+}
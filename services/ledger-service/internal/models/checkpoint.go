@@ -0,0 +1,117 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ChainBreakError reports the position at which a chain of events stops linking up
+type ChainBreakError struct {
+	Index   int
+	EventID string
+	Reason  string
+}
+
+// Error describes where and why the chain broke
+func (e *ChainBreakError) Error() string {
+	return fmt.Sprintf("chain broken at index %d (event %s): %s", e.Index, e.EventID, e.Reason)
+}
+
+// VerifyChainFrom checks that events link to start and to each other by PreviousHash
+func VerifyChainFrom(start string, events []*LedgerEvent) error {
+	expected := start
+	for i, event := range events {
+		if err := checkChainLink(i, event, expected); err != nil {
+			return err
+		}
+		expected = event.ComputeHash()
+	}
+	return nil
+}
+
+// Checkpoint is a signed snapshot of an account stream at a version. Events appended after it
+// link to the checkpoint's hash instead of the previous event, so verifying the chain only
+// needs the checkpoint and the events that follow it.
+type Checkpoint struct {
+	AccountID string    `json:"accountId"`
+	Version   int64     `json:"version"`
+	HeadHash  string    `json:"headHash"`
+	Balance   Money     `json:"balance"`
+	CreatedAt time.Time `json:"createdAt"`
+	KeyID     string    `json:"keyId"`
+	Signature string    `json:"signature"`
+}
+
+// NewCheckpoint creates a checkpoint over an account's head and signs it
+func NewCheckpoint(accountID string, version int64, headHash string, balance Money, signer Signer) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{
+		AccountID: accountID,
+		Version:   version,
+		HeadHash:  headHash,
+		Balance:   balance,
+		CreatedAt: time.Now().UTC(),
+		KeyID:     signer.KeyID(),
+	}
+
+	payload, err := checkpoint.payload()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign checkpoint: %w", err)
+	}
+	checkpoint.Signature = hex.EncodeToString(signature)
+	return checkpoint, nil
+}
+
+// Hash returns the hex-encoded SHA-256 of the checkpoint's signed payload, truncated to the
+// length of its head hash so the chain keeps one hash length; the first event after the
+// checkpoint carries it as its PreviousHash
+func (c *Checkpoint) Hash() string {
+	payload, err := c.payload()
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(payload)
+	length := len(hash)
+	if headLength := len(c.HeadHash) / 2; headLength >= MinHashLength && headLength < length {
+		length = headLength
+	}
+	return hex.EncodeToString(hash[:length])
+}
+
+// Verify checks the checkpoint signature
+func (c *Checkpoint) Verify(verifier Verifier) error {
+	if c.KeyID != verifier.KeyID() {
+		return fmt.Errorf("%w: checkpoint signed with key %q, verifier has %q", ErrInvalidSignature, c.KeyID, verifier.KeyID())
+	}
+	signature, err := hex.DecodeString(c.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed checkpoint signature", ErrInvalidSignature)
+	}
+	payload, err := c.payload()
+	if err != nil {
+		return err
+	}
+	return verifier.Verify(payload, signature)
+}
+
+// payload returns the bytes covered by the checkpoint signature
+func (c *Checkpoint) payload() ([]byte, error) {
+	payload, err := json.Marshal(struct {
+		AccountID string `json:"accountId"`
+		Version   int64  `json:"version"`
+		HeadHash  string `json:"headHash"`
+		Balance   Money  `json:"balance"`
+		CreatedAt int64  `json:"createdAt"`
+		KeyID     string `json:"keyId"`
+	}{c.AccountID, c.Version, c.HeadHash, c.Balance, c.CreatedAt.UnixNano(), c.KeyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Clock supplies the current time, so time-dependent rules can be evaluated at an arbitrary instant
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the wall clock
+type SystemClock struct{}
+
+// Now returns the current UTC time
+func (SystemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// FixedClock always returns the same instant; it is used for replays and tests
+type FixedClock time.Time
+
+// Now returns the fixed instant
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
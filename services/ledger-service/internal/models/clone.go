@@ -0,0 +1,100 @@
+package models
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clone returns a deep copy of the event that shares nothing mutable with it: metadata is
+// copied at every depth, and the optional pointer fields, fee and tax breakdowns, references,
+// prior signatures and attestations are copied too. The builder methods mutate their receiver,
+// so clone an event before building on it if the original has been stored or handed out.
+func (e *LedgerEvent) Clone() *LedgerEvent {
+	copied := *e
+	copied.PaymentID = cloneString(e.PaymentID)
+	copied.ReferenceID = cloneString(e.ReferenceID)
+	copied.ReversesEventID = cloneString(e.ReversesEventID)
+	copied.HoldID = cloneString(e.HoldID)
+	copied.EffectiveAt = cloneTime(e.EffectiveAt)
+	copied.ExpiresAt = cloneTime(e.ExpiresAt)
+	copied.ValidFrom = cloneTime(e.ValidFrom)
+	copied.ValidUntil = cloneTime(e.ValidUntil)
+	if e.Metadata != nil {
+		copied.Metadata = cloneMetadataValue(reflect.ValueOf(e.Metadata)).Interface().(map[string]interface{})
+	}
+	if e.Fees != nil {
+		copied.Fees = &FeeBreakdown{Components: cloneSlice(e.Fees.Components)}
+	}
+	if e.Taxes != nil {
+		// Decimal rates are immutable, so the components copy by value
+		copied.Taxes = &TaxBreakdown{Components: cloneSlice(e.Taxes.Components)}
+	}
+	copied.References = cloneSlice(e.References)
+	copied.PriorSignatures = cloneSlice(e.PriorSignatures)
+	copied.Attestations = cloneSlice(e.Attestations)
+	return &copied
+}
+
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	return &copied
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// cloneSlice copies a slice of values, keeping nil as nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+// cloneMetadataValue copies the maps, slices and arrays at and below value. Other values,
+// including pointers, are immutable in practice and shared.
+func cloneMetadataValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(cloneMetadataValue(value.Elem()))
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), cloneMetadataValue(iter.Value()))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(cloneMetadataValue(value.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(cloneMetadataValue(value.Index(i)))
+		}
+		return copied
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	original := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").
+		WithPaymentID("pay_1").
+		WithReferenceID("ref_1").
+		WithEffectiveAt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)).
+		WithMetadata("channel", "card").
+		WithMetadata("terminal", map[string]interface{}{"id": "t_1", "tags": []interface{}{"a"}}).
+		WithMetadata("flags", []string{"x"}).
+		WithFeeBreakdown(FeeBreakdown{Components: []FeeComponent{{Type: ProcessorFee, Amount: usdAmount(10)}}})
+	original.AddReference(RefCaptures, "evt_hold")
+	require.NoError(t, original.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+	snapshot := jsonRoundTrip(t, original)
+
+	clone := original.Clone()
+	assert.Equal(t, original, clone)
+
+	clone.WithMetadata("channel", "wire").WithPaymentID("pay_2")
+	*clone.ReferenceID = "ref_2"
+	*clone.EffectiveAt = clone.EffectiveAt.Add(time.Hour)
+	clone.Metadata["terminal"].(map[string]interface{})["id"] = "t_2"
+	clone.Metadata["terminal"].(map[string]interface{})["tags"].([]interface{})[0] = "b"
+	clone.Metadata["flags"].([]string)[0] = "y"
+	clone.Fees.Components[0].Amount = usdAmount(20)
+	clone.References[0].EventID = "evt_other"
+
+	assert.Equal(t, snapshot, jsonRoundTrip(t, original))
+	assert.Equal(t, []string{"x"}, original.Metadata["flags"])
+	assert.NoError(t, original.VerifyWith(NewHMACKey("ledger-1", []byte("secret"))))
+}
+
+func TestCloneKeepsNilFieldsNil(t *testing.T) {
+	original := NewLedgerEvent(AccountFreeze, Money{}, "acc_1", "corr_1")
+	original.Metadata = nil
+
+	clone := original.Clone()
+	assert.Equal(t, original, clone)
+	assert.Nil(t, clone.Metadata)
+	assert.Nil(t, clone.PaymentID)
+	assert.Nil(t, clone.Fees)
+	assert.Nil(t, clone.References)
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrAccountClosed is returned when a balance-affecting event, reversal or hold targets a closed account
+var ErrAccountClosed = errors.New("account closed")
+
+// CloseAccount returns the events closing the account whose stream, in version order, is
+// events: an adjustment per currency bringing a non-zero posted balance to zero, followed by an
+// AccountClose event, at the versions after the stream's last. The events share correlationID.
+// Closing an account that is already closed returns no events. Outstanding holds are left as
+// they are; release them first if the account should close with nothing held.
+func CloseAccount(events []*LedgerEvent, correlationID string) ([]*LedgerEvent, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("cannot close an account without events")
+	}
+	accountID := events[0].AccountID
+	if IsClosed(events) {
+		return []*LedgerEvent{}, nil
+	}
+
+	balances := newAccountBalances()
+	version := int64(0)
+	for _, event := range events {
+		if event.AccountID != accountID {
+			return nil, fmt.Errorf("events span accounts %s and %s", accountID, event.AccountID)
+		}
+		if err := balances.apply(event); err != nil {
+			return nil, fmt.Errorf("cannot compute balance of account %s at event %s: %w", accountID, event.ID, err)
+		}
+		if event.Version > version {
+			version = event.Version
+		}
+	}
+
+	currencies := make([]string, 0, len(balances.states))
+	for currency := range balances.states {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	closing := make([]*LedgerEvent, 0, len(currencies)+1)
+	for _, currency := range currencies {
+		posted := balances.states[currency].Posted
+		if posted.Sign() == 0 {
+			continue
+		}
+		version++
+		closing = append(closing, NewLedgerEvent(Adjustment, posted.negate(), accountID, correlationID).
+			WithVersion(version).
+			WithMetadata("reason", "account closure"))
+	}
+	version++
+	return append(closing, newControlEvent(AccountClose, accountID, correlationID).WithVersion(version)), nil
+}
+
+// IsClosed reports whether an account stream contains an AccountClose event
+func IsClosed(stream []*LedgerEvent) bool {
+	for _, event := range stream {
+		if event.Type == AccountClose {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckClosed rejects balance-affecting events, reversals and holds against an account whose
+// stream has been closed
+func CheckClosed(stream []*LedgerEvent, event *LedgerEvent) error {
+	if !event.PostsBalance() && !event.IsHold() {
+		return nil
+	}
+	if IsClosed(stream) {
+		return fmt.Errorf("%w: %s rejected on account %s", ErrAccountClosed, event.Type, event.AccountID)
+	}
+	return nil
+}
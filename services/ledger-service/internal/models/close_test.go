@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseAccountZeroesBalance(t *testing.T) {
+	stream := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithVersion(1),
+		NewLedgerEvent(Debit, usdAmount(30), "acc_1", "corr_2").WithVersion(2),
+		NewLedgerEvent(Adjustment, usdAmount(-2.5), "acc_1", "corr_3").WithVersion(3),
+	}
+
+	closing, err := CloseAccount(stream, "corr_close")
+	require.NoError(t, err)
+	require.Len(t, closing, 2)
+	for _, event := range closing {
+		require.NoError(t, event.Validate())
+		assert.Equal(t, "corr_close", event.CorrelationID)
+	}
+	assert.Equal(t, Adjustment, closing[0].Type)
+	assert.Equal(t, usdAmount(-67.5), closing[0].Amount)
+	assert.Equal(t, int64(4), closing[0].Version)
+	assert.Equal(t, AccountClose, closing[1].Type)
+	assert.Equal(t, int64(5), closing[1].Version)
+
+	closed := append(stream, closing...)
+	health := AccountReport(closed)
+	assert.Equal(t, int64(0), health.Balances["USD"].Posted.MinorUnits)
+	assert.ErrorIs(t, CheckClosed(closed, NewLedgerEvent(Debit, usdAmount(1), "acc_1", "corr_4")), ErrAccountClosed)
+	assert.ErrorIs(t, CheckClosed(closed, NewLedgerEvent(Hold, usdAmount(1), "acc_1", "corr_4")), ErrAccountClosed)
+	assert.ErrorIs(t, CheckClosed(closed, NewReversal(closing[0], "corr_4")), ErrAccountClosed)
+	assert.NoError(t, CheckClosed(closed, NewAccountFreeze("acc_1", "corr_4", "audit")))
+
+	again, err := CloseAccount(closed, "corr_close")
+	require.NoError(t, err)
+	assert.Empty(t, again)
+}
+
+func TestCloseAccountWithZeroBalanceOnlyCloses(t *testing.T) {
+	stream := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1),
+		NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_2").WithVersion(2),
+	}
+	closing, err := CloseAccount(stream, "corr_close")
+	require.NoError(t, err)
+	require.Len(t, closing, 1)
+	assert.Equal(t, AccountClose, closing[0].Type)
+
+	_, err = CloseAccount(append(stream, NewLedgerEvent(Credit, usdAmount(1), "acc_2", "corr_3")), "corr_close")
+	assert.Error(t, err)
+}
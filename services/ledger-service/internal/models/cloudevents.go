@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"google.golang.org/protobuf/proto"
+
+	ledgerv1 "fintech-platform/ledger-service/api/ledger/v1"
+)
+
+// CloudEvents attributes of ledger events
+const (
+	// CloudEventTypePrefix namespaces ledger event types, e.g. com.fintech-platform.ledger.credit
+	CloudEventTypePrefix = "com.fintech-platform.ledger."
+	// CloudEventSource is the source of every ledger CloudEvent
+	CloudEventSource = "/ledger-service"
+	// CloudEventCorrelationExtension carries the correlation ID; extension names must be lowercase alphanumeric
+	CloudEventCorrelationExtension = "correlationid"
+	// CloudEventProtobuf is the data content type of CloudEvents carrying the event's protobuf encoding
+	CloudEventProtobuf = "application/protobuf"
+)
+
+// ErrUnsupportedContentType is returned for CloudEvents whose data is not in a format the ledger can decode
+var ErrUnsupportedContentType = errors.New("unsupported CloudEvent data content type")
+
+// ToCloudEvent wraps the event in a CloudEvent: the CE id is the event ID, the CE type its
+// namespaced type, the subject its account, and the data the event's JSON encoding
+func (e *LedgerEvent) ToCloudEvent() (cloudevents.Event, error) {
+	return e.ToCloudEventAs(cloudevents.ApplicationJSON)
+}
+
+// ToCloudEventAs wraps the event in a CloudEvent like ToCloudEvent, with the data encoded in
+// the given content type: cloudevents.ApplicationJSON or CloudEventProtobuf. Other content
+// types are an ErrUnsupportedContentType.
+func (e *LedgerEvent) ToCloudEventAs(contentType string) (cloudevents.Event, error) {
+	ce := cloudevents.NewEvent()
+	ce.SetID(e.ID)
+	ce.SetType(CloudEventTypePrefix + strings.ToLower(string(e.Type)))
+	ce.SetSource(CloudEventSource)
+	ce.SetSubject(e.AccountID)
+	ce.SetTime(e.Timestamp)
+	ce.SetExtension(CloudEventCorrelationExtension, e.CorrelationID)
+
+	var data interface{} = e
+	switch contentType {
+	case cloudevents.ApplicationJSON:
+	case CloudEventProtobuf:
+		pb, err := e.ToProto()
+		if err != nil {
+			return ce, err
+		}
+		if data, err = proto.Marshal(pb); err != nil {
+			return ce, fmt.Errorf("failed to encode event %s as protobuf: %w", e.ID, err)
+		}
+	default:
+		return ce, fmt.Errorf("%w: %q for event %s", ErrUnsupportedContentType, contentType, e.ID)
+	}
+	if err := ce.SetData(contentType, data); err != nil {
+		return ce, fmt.Errorf("failed to encode event %s as CloudEvent data: %w", e.ID, err)
+	}
+	return ce, nil
+}
+
+// ToCloudEventJSON returns the event's CloudEvent, see ToCloudEvent, in the structured JSON
+// format, for consumers that take CloudEvents envelopes rather than raw events
+func (e *LedgerEvent) ToCloudEventJSON() ([]byte, error) {
+	ce, err := e.ToCloudEvent()
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(ce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CloudEvent %s: %w", e.ID, err)
+	}
+	return encoded, nil
+}
+
+// LedgerEventFromCloudEventJSON decodes a ledger event from a CloudEvent in the structured JSON
+// format, checking it as FromCloudEvent does
+func LedgerEventFromCloudEventJSON(data []byte) (*LedgerEvent, error) {
+	var ce cloudevents.Event
+	if err := json.Unmarshal(data, &ce); err != nil {
+		return nil, fmt.Errorf("failed to decode CloudEvent: %w", err)
+	}
+	return FromCloudEvent(ce)
+}
+
+// FromCloudEvent decodes a ledger event from a CloudEvent produced by ToCloudEvent. The
+// CloudEvent must be a valid v1.0 event from CloudEventSource. The data is decoded according to
+// its content type, JSON or CloudEventProtobuf; data without a content type is taken to be JSON.
+// The CE id and type must agree with the decoded event.
+func FromCloudEvent(ce cloudevents.Event) (*LedgerEvent, error) {
+	if err := ce.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CloudEvent %s: %w", ce.ID(), err)
+	}
+	if ce.SpecVersion() != cloudevents.VersionV1 {
+		return nil, fmt.Errorf("CloudEvent %s has spec version %s, expected %s", ce.ID(), ce.SpecVersion(), cloudevents.VersionV1)
+	}
+	if ce.Source() != CloudEventSource {
+		return nil, fmt.Errorf("CloudEvent %s has source %q, expected %q", ce.ID(), ce.Source(), CloudEventSource)
+	}
+	if !strings.HasPrefix(ce.Type(), CloudEventTypePrefix) {
+		return nil, fmt.Errorf("CloudEvent %s has non-ledger type %q", ce.ID(), ce.Type())
+	}
+
+	var event LedgerEvent
+	switch contentType := ce.DataMediaType(); contentType {
+	case "", cloudevents.ApplicationJSON, "text/json":
+		if err := ce.DataAs(&event); err != nil {
+			return nil, fmt.Errorf("failed to decode CloudEvent %s: %w", ce.ID(), err)
+		}
+	case CloudEventProtobuf:
+		var pb ledgerv1.LedgerEvent
+		if err := proto.Unmarshal(ce.Data(), &pb); err != nil {
+			return nil, fmt.Errorf("failed to decode CloudEvent %s: %w", ce.ID(), err)
+		}
+		decoded, err := LedgerEventFromProto(&pb)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CloudEvent %s: %w", ce.ID(), err)
+		}
+		event = *decoded
+	default:
+		return nil, fmt.Errorf("%w: %q on CloudEvent %s", ErrUnsupportedContentType, contentType, ce.ID())
+	}
+
+	if event.ID != ce.ID() {
+		return nil, fmt.Errorf("CloudEvent id %s does not match event %s", ce.ID(), event.ID)
+	}
+	if want := CloudEventTypePrefix + strings.ToLower(string(event.Type)); want != ce.Type() {
+		return nil, fmt.Errorf("CloudEvent type %q does not match %s event %s", ce.Type(), event.Type, event.ID)
+	}
+	return &event, nil
+}
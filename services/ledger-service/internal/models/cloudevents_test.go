@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEventRoundTrip(t *testing.T) {
+	for _, contentType := range []string{cloudevents.ApplicationJSON, CloudEventProtobuf} {
+		t.Run(contentType, func(t *testing.T) {
+			effective := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+			event := NewLedgerEvent(Credit, usdAmount(42.5), "acc_1", "corr_1").
+				WithVersion(3).
+				WithPaymentID("pay_1").
+				WithMetadata("channel", "card").
+				WithMetadata("attempt", 2.0).
+				WithEffectiveAt(effective)
+			event.AddReference(RefCaptures, "evt_hold")
+			require.NoError(t, event.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+
+			ce, err := event.ToCloudEventAs(contentType)
+			require.NoError(t, err)
+			assert.Equal(t, event.ID, ce.ID())
+			assert.Equal(t, "com.fintech-platform.ledger.credit", ce.Type())
+			assert.Equal(t, "acc_1", ce.Subject())
+			assert.Equal(t, "corr_1", ce.Extensions()[CloudEventCorrelationExtension])
+			assert.Equal(t, contentType, ce.DataContentType())
+			require.NoError(t, ce.Validate())
+
+			// Through the structured JSON wire format and back
+			wire, err := json.Marshal(ce)
+			require.NoError(t, err)
+			var received cloudevents.Event
+			require.NoError(t, json.Unmarshal(wire, &received))
+
+			decoded, err := FromCloudEvent(received)
+			require.NoError(t, err)
+			assert.Equal(t, event.ID, decoded.ID)
+			assert.True(t, event.Timestamp.Equal(decoded.Timestamp))
+			assert.True(t, effective.Equal(*decoded.EffectiveAt))
+			decoded.Timestamp, decoded.EffectiveAt = event.Timestamp, event.EffectiveAt
+			assert.Equal(t, event, decoded)
+			assert.NoError(t, decoded.VerifyWith(NewHMACKey("ledger-1", []byte("secret"))))
+		})
+	}
+}
+
+func TestFromCloudEventRejections(t *testing.T) {
+	event := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	ce, err := event.ToCloudEvent()
+	require.NoError(t, err)
+
+	xml := ce.Clone()
+	require.NoError(t, xml.SetData("application/xml", []byte("<event/>")))
+	_, err = FromCloudEvent(xml)
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+
+	_, err = event.ToCloudEventAs("application/xml")
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+
+	truncated := ce.Clone()
+	require.NoError(t, truncated.SetData(CloudEventProtobuf, []byte{0x0a, 0x01}))
+	_, err = FromCloudEvent(truncated)
+	assert.Error(t, err)
+
+	foreign := ce.Clone()
+	foreign.SetType("com.example.order.created")
+	_, err = FromCloudEvent(foreign)
+	assert.Error(t, err)
+
+	mismatched := ce.Clone()
+	mismatched.SetType(CloudEventTypePrefix + "credit")
+	_, err = FromCloudEvent(mismatched)
+	assert.Error(t, err)
+}
+
+func TestCloudEventJSONRoundTrip(t *testing.T) {
+	event := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1).WithMetadata("channel", "card")
+	require.NoError(t, event.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+
+	wire, err := event.ToCloudEventJSON()
+	require.NoError(t, err)
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal(wire, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, CloudEventSource, envelope["source"])
+	assert.Equal(t, "com.fintech-platform.ledger.debit", envelope["type"])
+	assert.Equal(t, "corr_1", envelope[CloudEventCorrelationExtension])
+	assert.Contains(t, envelope, "data")
+
+	decoded, err := LedgerEventFromCloudEventJSON(wire)
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.NoError(t, decoded.VerifyWith(NewHMACKey("ledger-1", []byte("secret"))))
+}
+
+func TestCloudEventJSONRejectsBadEnvelopes(t *testing.T) {
+	wire, err := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1).ToCloudEventJSON()
+	require.NoError(t, err)
+
+	for attribute, value := range map[string]interface{}{
+		"specversion": "0.3",
+		"source":      "/somewhere-else",
+		"id":          "",
+	} {
+		var envelope map[string]interface{}
+		require.NoError(t, json.Unmarshal(wire, &envelope))
+		envelope[attribute] = value
+		tampered, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		_, err = LedgerEventFromCloudEventJSON(tampered)
+		assert.Error(t, err, attribute)
+	}
+
+	var envelope map[string]interface{}
+	require.NoError(t, json.Unmarshal(wire, &envelope))
+	delete(envelope, "specversion")
+	missing, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	_, err = LedgerEventFromCloudEventJSON(missing)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrConservationViolated is returned when balances in a currency do not add up to the money
+// that entered the ledger from outside
+var ErrConservationViolated = errors.New("money conservation violated")
+
+// ConservationOption configures CheckConservation
+type ConservationOption func(*conservationConfig)
+
+type conservationConfig struct {
+	excluded map[AccountID]struct{}
+}
+
+// ExcludeAccounts leaves the given accounts, typically suspense accounts, out of the total
+func ExcludeAccounts(accountIDs ...AccountID) ConservationOption {
+	return func(c *conservationConfig) {
+		for _, id := range accountIDs {
+			c.excluded[id] = struct{}{}
+		}
+	}
+}
+
+// CheckConservation sums the signed posted amounts in currency across all accounts and checks
+// that they equal externalFunding, the net of external deposits minus withdrawals. A surplus
+// means the ledger created money and a shortfall that it destroyed some; either is reported
+// as ErrConservationViolated. Events in other currencies are ignored.
+func CheckConservation(events []*LedgerEvent, currency string, externalFunding Money, opts ...ConservationOption) error {
+	if externalFunding.Currency != currency {
+		return fmt.Errorf("%w: checking %s against %s funding", ErrCurrencyMismatch, currency, externalFunding.Currency)
+	}
+
+	config := conservationConfig{excluded: make(map[AccountID]struct{})}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	total := Money{Currency: currency, Precision: externalFunding.Precision}
+	originals := ReversibleTypes(events)
+	for _, event := range events {
+		if !event.PostsBalance() || event.Amount.Currency != currency {
+			continue
+		}
+		if _, excluded := config.excluded[event.AccountID]; excluded {
+			continue
+		}
+		amount, err := PostedAmount(event, originals)
+		if err != nil {
+			return err
+		}
+		total = total.add(amount)
+	}
+
+	drift, err := total.add(externalFunding.negate()).Normalize()
+	if err != nil {
+		return err
+	}
+	switch drift.Sign() {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("%w: %s %s created", ErrConservationViolated, drift.decimal(), currency)
+	}
+	return fmt.Errorf("%w: %s %s destroyed", ErrConservationViolated, drift.negate().decimal(), currency)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func usdAmount(amount float64) Money {
+	return NewMoney(int64(math.Round(amount*100)), "USD", 2)
+}
+
+func TestCheckConservationBalancedTransfers(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "fund_1"),
+		NewLedgerEvent(Debit, usdAmount(30), "acc_1", "xfer_1"),
+		NewLedgerEvent(Credit, usdAmount(30), "acc_2", "xfer_1"),
+		NewLedgerEvent(Credit, NewMoney(5000, "JPY", 0), "acc_3", "fund_2"),
+	}
+
+	assert.NoError(t, CheckConservation(events, "USD", usdAmount(100)))
+}
+
+func TestCheckConservationCountsReversals(t *testing.T) {
+	debit := NewLedgerEvent(Debit, usdAmount(30), "acc_1", "xfer_1")
+	credit := NewLedgerEvent(Credit, usdAmount(30), "acc_2", "xfer_1")
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "fund_1"),
+		debit,
+		credit,
+		NewReversal(debit, "xfer_1_rev"),
+	}
+	assert.ErrorIs(t, CheckConservation(events, "USD", usdAmount(100)), ErrConservationViolated,
+		"a transfer reversed on one side only creates money")
+
+	events = append(events, NewReversal(credit, "xfer_1_rev"))
+	assert.NoError(t, CheckConservation(events, "USD", usdAmount(100)))
+}
+
+func TestCheckConservationCatchesImbalance(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "fund_1"),
+		NewLedgerEvent(Debit, usdAmount(30), "acc_1", "xfer_1"),
+		NewLedgerEvent(Credit, usdAmount(30.01), "acc_2", "xfer_1"),
+	}
+
+	err := CheckConservation(events, "USD", usdAmount(100))
+	require.ErrorIs(t, err, ErrConservationViolated)
+	assert.Contains(t, err.Error(), "0.01 USD created")
+}
+
+func TestCheckConservationExcludesSuspense(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "fund_1"),
+		NewLedgerEvent(Debit, usdAmount(40), "acc_1", "xfer_1"),
+		NewLedgerEvent(Credit, usdAmount(40), "suspense", "xfer_1"),
+	}
+
+	assert.ErrorIs(t, CheckConservation(events, "USD", usdAmount(100), ExcludeAccounts("suspense")), ErrConservationViolated)
+	assert.NoError(t, CheckConservation(events, "USD", usdAmount(60), ExcludeAccounts("suspense")))
+}
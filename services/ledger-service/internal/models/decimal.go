@@ -0,0 +1,210 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// RoundingMode selects how a value is rounded to a number of decimal places
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties to the even neighbour (banker's rounding)
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, ties away from zero
+	RoundHalfUp
+	// RoundDown truncates towards zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+)
+
+// Decimal is an exact rational number for rates and intermediate results that must not pick
+// up float error. The zero value is 0.
+type Decimal struct {
+	r *big.Rat
+}
+
+// NewDecimal returns unscaled × 10^-scale, e.g. NewDecimal(125, 3) is 0.125
+func NewDecimal(unscaled int64, scale int) Decimal {
+	r := new(big.Rat).SetInt64(unscaled)
+	return Decimal{r: r.Quo(r, pow10(scale))}
+}
+
+// ParseDecimal parses a decimal string such as "0.0525", or a fraction such as "1/3"
+func ParseDecimal(s string) (Decimal, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r: r}, nil
+}
+
+// DecimalFromMoney returns the amount exactly
+func DecimalFromMoney(m Money) Decimal {
+	return NewDecimal(m.MinorUnits, m.Precision)
+}
+
+// MoneyFromDecimal rounds d to the precision and returns it as an amount of the currency
+func MoneyFromDecimal(d Decimal, currency string, precision int, round RoundingMode) (Money, error) {
+	minor, err := d.minorUnits(precision, round)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(minor, currency, precision), nil
+}
+
+// rat returns the value, treating the zero Decimal as 0
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + other
+func (d Decimal) Add(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), other.rat())}
+}
+
+// Sub returns d - other
+func (d Decimal) Sub(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Sub(d.rat(), other.rat())}
+}
+
+// Abs returns |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{r: new(big.Rat).Abs(d.rat())}
+}
+
+// Mul returns d × other
+func (d Decimal) Mul(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), other.rat())}
+}
+
+// Quo returns d / other; other must not be zero
+func (d Decimal) Quo(other Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Quo(d.rat(), other.rat())}
+}
+
+// QuoInt returns d / n
+func (d Decimal) QuoInt(n int64) Decimal {
+	return Decimal{r: new(big.Rat).Quo(d.rat(), new(big.Rat).SetInt64(n))}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// Cmp compares d and other, returning -1, 0 or +1
+func (d Decimal) Cmp(other Decimal) int {
+	return d.rat().Cmp(other.rat())
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String returns d exactly, with as many decimal places as it needs. A value without a finite
+// decimal expansion, such as 1/3, is rounded to 18 places.
+func (d Decimal) String() string {
+	if places, ok := d.decimalPlaces(); ok {
+		return d.rat().FloatString(places)
+	}
+	return d.rat().FloatString(18)
+}
+
+// decimalPlaces returns the number of decimal places that hold d exactly, or false if no
+// number does because its denominator has a prime factor other than 2 and 5
+func (d Decimal) decimalPlaces() (int, bool) {
+	denom := new(big.Int).Set(d.rat().Denom())
+	twos := int(denom.TrailingZeroBits())
+	denom.Rsh(denom, uint(twos))
+
+	fives := 0
+	five, remainder := big.NewInt(5), new(big.Int)
+	for {
+		quotient, _ := new(big.Int).QuoRem(denom, five, remainder)
+		if remainder.Sign() != 0 {
+			break
+		}
+		denom = quotient
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	return max(twos, fives), true
+}
+
+// Round rounds d to the given number of decimal places
+func (d Decimal) Round(places int, mode RoundingMode) Decimal {
+	scale := pow10(places)
+	scaled := new(big.Rat).Mul(d.rat(), scale)
+
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		away := false
+		switch mode {
+		case RoundUp:
+			away = true
+		case RoundHalfUp, RoundHalfEven:
+			// Compare twice the remainder with the denominator to find which side of the half it is
+			twice := new(big.Int).Abs(remainder)
+			twice.Lsh(twice, 1)
+			switch twice.Cmp(scaled.Denom()) {
+			case 1:
+				away = true
+			case 0:
+				away = mode == RoundHalfUp || quotient.Bit(0) == 1
+			}
+		}
+		if away {
+			quotient.Add(quotient, big.NewInt(int64(scaled.Sign())))
+		}
+	}
+
+	result := new(big.Rat).SetInt(quotient)
+	return Decimal{r: result.Quo(result, scale)}
+}
+
+// minorUnits rounds d to the given number of decimal places and returns it scaled to an integer
+func (d Decimal) minorUnits(places int, mode RoundingMode) (int64, error) {
+	scaled := new(big.Rat).Mul(d.Round(places, mode).rat(), pow10(places))
+	if !scaled.Num().IsInt64() {
+		return 0, fmt.Errorf("amount %s overflows at precision %d", d, places)
+	}
+	return scaled.Num().Int64(), nil
+}
+
+// pow10 returns 10^n as a rational
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+// MarshalJSON encodes d as a decimal string, or as a fraction such as "1/3" when it has no
+// finite decimal expansion, so it round-trips exactly
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if _, ok := d.decimalPlaces(); !ok {
+		return json.Marshal(d.rat().RatString())
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a decimal string or fraction written by MarshalJSON
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("decimal must be a string: %w", err)
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalRoundingModes(t *testing.T) {
+	cases := []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.345", RoundHalfEven, "2.34"},
+		{"2.355", RoundHalfEven, "2.36"},
+		{"2.345", RoundHalfUp, "2.35"},
+		{"-2.345", RoundHalfUp, "-2.35"},
+		{"2.349", RoundDown, "2.34"},
+		{"-2.349", RoundDown, "-2.34"},
+		{"2.341", RoundUp, "2.35"},
+		{"2.34", RoundUp, "2.34"},
+	}
+	for _, c := range cases {
+		value, err := ParseDecimal(c.value)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, value.Round(2, c.mode).String(), "%s %d", c.value, c.mode)
+	}
+}
+
+func TestDecimalJSONRoundTripsExactly(t *testing.T) {
+	long, err := ParseDecimal("0.1234567890123456789012345")
+	require.NoError(t, err)
+	assert.Equal(t, "0.1234567890123456789012345", long.String())
+	assert.Equal(t, "0.001", NewDecimal(1, 3).String())
+	assert.Equal(t, "0.333333333333333333", NewDecimal(1, 0).QuoInt(3).String())
+
+	for _, value := range []Decimal{long, NewDecimal(-1, 0).QuoInt(3), NewDecimal(125, 3), {}} {
+		encoded, err := json.Marshal(value)
+		require.NoError(t, err)
+		var decoded Decimal
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Zero(t, value.Cmp(decoded), "%s", encoded)
+	}
+
+	encoded, err := json.Marshal(NewDecimal(2, 0).QuoInt(3))
+	require.NoError(t, err)
+	assert.Equal(t, `"2/3"`, string(encoded))
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownField is returned when proving a field that is not part of the signed payload
+var ErrUnknownField = errors.New("unknown field")
+
+// FieldCommitments is a signed commitment to each field of an event's canonical payload,
+// from which single fields can later be disclosed without revealing the others. Each field is
+// a Merkle leaf SHA-256(salt || name || 0x00 || JSON value) with a random per-field salt, so
+// the hashes handed out as proof siblings do not leak low-entropy fields such as the account.
+// The salts are secret: keep the commitments with the event owner and share only proofs.
+type FieldCommitments struct {
+	Root      string `json:"root"`
+	KeyID     string `json:"keyId"`
+	Signature string `json:"signature"`
+
+	fields []string
+	values map[string]json.RawMessage
+	salts  map[string][]byte
+	levels [][][32]byte
+}
+
+// FieldProof discloses one committed field: its value, its salt and the Merkle path to the root
+type FieldProof struct {
+	Field    string          `json:"field"`
+	Value    json.RawMessage `json:"value"`
+	Salt     string          `json:"salt"`
+	Index    int             `json:"index"`
+	Siblings []string        `json:"siblings"`
+}
+
+// SignCommitments commits to every field of the event's canonical payload and signs the
+// Merkle root with signer
+func (e *LedgerEvent) SignCommitments(signer Signer) (*FieldCommitments, error) {
+	if err := checkMetadataDepth(e.Metadata); err != nil {
+		return nil, err
+	}
+
+	payload, err := e.canonicalPayload()
+	if err != nil {
+		return nil, fmt.Errorf("failed to canonicalize event: %w", err)
+	}
+	c := &FieldCommitments{
+		fields: make([]string, 0, len(payload)),
+		values: make(map[string]json.RawMessage, len(payload)),
+		salts:  make(map[string][]byte, len(payload)),
+	}
+	for field := range payload {
+		c.fields = append(c.fields, field)
+	}
+	sort.Strings(c.fields)
+
+	leaves := make([][32]byte, len(c.fields))
+	for i, field := range c.fields {
+		value, err := json.Marshal(payload[field])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal field %s: %w", field, err)
+		}
+		salt := make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		c.values[field], c.salts[field] = value, salt
+		leaves[i] = fieldLeaf(salt, field, value)
+	}
+
+	c.levels = merkleLevels(leaves)
+	root := c.levels[len(c.levels)-1][0]
+	signature, err := signer.Sign(root[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign field commitments: %w", err)
+	}
+	c.Root = hex.EncodeToString(root[:])
+	c.KeyID = signer.KeyID()
+	c.Signature = hex.EncodeToString(signature)
+	return c, nil
+}
+
+// ProveField returns the proof disclosing a single field
+func (c *FieldCommitments) ProveField(field string) (FieldProof, error) {
+	index := sort.SearchStrings(c.fields, field)
+	if index == len(c.fields) || c.fields[index] != field {
+		return FieldProof{}, fmt.Errorf("%w: %q", ErrUnknownField, field)
+	}
+
+	proof := FieldProof{
+		Field: field,
+		Value: c.values[field],
+		Salt:  hex.EncodeToString(c.salts[field]),
+		Index: index,
+	}
+	for _, sibling := range merkleSiblings(c.levels, index) {
+		proof.Siblings = append(proof.Siblings, hex.EncodeToString(sibling[:]))
+	}
+	return proof, nil
+}
+
+// VerifyField reports whether the proof leads to the committed root and the root's signature
+// verifies with verifier
+func VerifyField(proof FieldProof, commitments FieldCommitments, verifier Verifier) bool {
+	root, err := hex.DecodeString(commitments.Root)
+	if err != nil || len(root) != sha256.Size {
+		return false
+	}
+	signature, err := hex.DecodeString(commitments.Signature)
+	if err != nil || commitments.KeyID != verifier.KeyID() || verifier.Verify(root, signature) != nil {
+		return false
+	}
+
+	salt, err := hex.DecodeString(proof.Salt)
+	if err != nil || proof.Index < 0 {
+		return false
+	}
+	siblings := make([][32]byte, len(proof.Siblings))
+	for i, encoded := range proof.Siblings {
+		decoded, err := hex.DecodeString(encoded)
+		if err != nil || len(decoded) != sha256.Size {
+			return false
+		}
+		copy(siblings[i][:], decoded)
+	}
+	node, position := merklePath(fieldLeaf(salt, proof.Field, proof.Value), proof.Index, siblings)
+	return position == 0 && hex.EncodeToString(node[:]) == commitments.Root
+}
+
+// fieldLeaf returns SHA-256(salt || field || 0x00 || value)
+func fieldLeaf(salt []byte, field string, value []byte) [32]byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(field))
+	h.Write([]byte{0})
+	h.Write(value)
+	var leaf [32]byte
+	copy(leaf[:], h.Sum(nil))
+	return leaf
+}
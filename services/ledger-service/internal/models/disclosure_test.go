@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveAmountWithoutRevealingAccount(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := NewLedgerEvent(Debit, usdAmount(250), "acc_private", "corr_1").WithVersion(4).WithMetadata("channel", "card")
+
+	commitments, err := event.SignCommitments(key)
+	require.NoError(t, err)
+
+	proof, err := commitments.ProveField("amount")
+	require.NoError(t, err)
+
+	// The disclosed material is the proof and the public commitment, never the salts of other fields
+	disclosed, err := json.Marshal(struct {
+		Proof      FieldProof       `json:"proof"`
+		Commitment FieldCommitments `json:"commitment"`
+	}{proof, *commitments})
+	require.NoError(t, err)
+	assert.NotContains(t, string(disclosed), "acc_private")
+	assert.NotContains(t, string(disclosed), "corr_1")
+
+	var received struct {
+		Proof      FieldProof       `json:"proof"`
+		Commitment FieldCommitments `json:"commitment"`
+	}
+	require.NoError(t, json.Unmarshal(disclosed, &received))
+	assert.True(t, VerifyField(received.Proof, received.Commitment, key))
+
+	var amount Money
+	require.NoError(t, json.Unmarshal(received.Proof.Value, &amount))
+	assert.Equal(t, usdAmount(250), amount)
+}
+
+func TestVerifyFieldRejectsTampering(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	commitments, err := event.SignCommitments(key)
+	require.NoError(t, err)
+
+	for _, field := range []string{"accountId", "amount", "version", "type"} {
+		proof, err := commitments.ProveField(field)
+		require.NoError(t, err)
+		assert.True(t, VerifyField(proof, *commitments, key), field)
+	}
+
+	proof, err := commitments.ProveField("amount")
+	require.NoError(t, err)
+
+	forged := proof
+	forged.Value = json.RawMessage(`{"amount":10000,"currency":"USD","precision":2}`)
+	assert.False(t, VerifyField(forged, *commitments, key))
+
+	relabelled := proof
+	relabelled.Field = "version"
+	assert.False(t, VerifyField(relabelled, *commitments, key))
+
+	assert.False(t, VerifyField(proof, *commitments, NewHMACKey("ledger-1", []byte("other"))))
+
+	_, err = commitments.ProveField("secretField")
+	assert.ErrorIs(t, err, ErrUnknownField)
+}
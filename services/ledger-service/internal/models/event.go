@@ -1,48 +1,78 @@
 package models
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"fintech-platform/ledger-service/internal/currency"
 )
 
 // EventType represents the type of ledger event
 type EventType string
 
 const (
-	Debit     EventType = "DEBIT"
-	Credit    EventType = "CREDIT"
-	Hold      EventType = "HOLD"
-	Release   EventType = "RELEASE"
-	Reversal  EventType = "REVERSAL"
+	Debit      EventType = "DEBIT"
+	Credit     EventType = "CREDIT"
+	Hold       EventType = "HOLD"
+	Release    EventType = "RELEASE"
+	Reversal   EventType = "REVERSAL"
 	Adjustment EventType = "ADJUSTMENT"
-)
 
-// Money represents a monetary amount with currency
-type Money struct {
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency"`
-	Precision int     `json:"precision"`
-}
+	// Control events change account state without moving money
+	AccountFreeze   EventType = "ACCOUNT_FREEZE"
+	AccountUnfreeze EventType = "ACCOUNT_UNFREEZE"
+	AccountClose    EventType = "ACCOUNT_CLOSE"
+
+	// Chargeback events track a dispute through its stages; they record the disputed amount
+	// without moving money, see ChargebackState
+	ChargebackReceived    EventType = "CHARGEBACK_RECEIVED"
+	ChargebackRepresented EventType = "CHARGEBACK_REPRESENTED"
+	ChargebackArbitration EventType = "CHARGEBACK_ARBITRATION"
+	ChargebackWon         EventType = "CHARGEBACK_WON"
+	ChargebackLost        EventType = "CHARGEBACK_LOST"
+)
 
 // LedgerEvent represents an immutable ledger event
 type LedgerEvent struct {
-	ID           string                 `json:"id"`
-	Type         EventType              `json:"type"`
-	Amount       Money                  `json:"amount"`
-	Currency     string                 `json:"currency"`
-	AccountID    string                 `json:"accountId"`
-	PaymentID    *string                `json:"paymentId,omitempty"`
-	ReferenceID  *string                `json:"referenceId,omitempty"`
-	Timestamp    time.Time              `json:"timestamp"`
-	Metadata     map[string]interface{} `json:"metadata"`
-	Signature    string                 `json:"signature"`
-	Version      int64                  `json:"version"`
-	CorrelationID string                `json:"correlationId"`
+	ID                 string                 `json:"id"`
+	TenantID           string                 `json:"tenantId,omitempty"`
+	Type               EventType              `json:"type"`
+	Amount             Money                  `json:"amount"`
+	Currency           string                 `json:"currency"`
+	AccountID          string                 `json:"accountId"`
+	PaymentID          *string                `json:"paymentId,omitempty"`
+	ReferenceID        *string                `json:"referenceId,omitempty"`
+	References         []EventRef             `json:"references,omitempty"`
+	ReversesEventID    *string                `json:"reversesEventId,omitempty"`
+	HoldID             *string                `json:"holdId,omitempty"`
+	Timestamp          time.Time              `json:"timestamp"`
+	EffectiveAt        *time.Time             `json:"effectiveAt,omitempty"`
+	ExpiresAt          *time.Time             `json:"expiresAt,omitempty"`
+	ValidFrom          *time.Time             `json:"validFrom,omitempty"`
+	ValidUntil         *time.Time             `json:"validUntil,omitempty"`
+	Metadata           map[string]interface{} `json:"metadata"`
+	Fees               *FeeBreakdown          `json:"fees,omitempty"`
+	Taxes              *TaxBreakdown          `json:"taxes,omitempty"`
+	Signature          string                 `json:"signature"`
+	SignatureAlgorithm string                 `json:"signatureAlgorithm,omitempty"`
+	KeyID              string                 `json:"keyId,omitempty"`
+	PriorSignatures    []PriorSignature       `json:"priorSignatures,omitempty"`
+	Attestations       []Attestation          `json:"attestations,omitempty"`
+	PreviousHash       string                 `json:"previousHash,omitempty"`
+	HashLength         int                    `json:"hashLength,omitempty"`
+	Version            int64                  `json:"version"`
+	Priority           int                    `json:"priority,omitempty"`
+	CorrelationID      string                 `json:"correlationId"`
+	IdempotencyKey     string                 `json:"idempotencyKey,omitempty"`
 }
 
 // NewLedgerEvent creates a new ledger event with required fields
@@ -81,16 +111,35 @@ func (e *LedgerEvent) WithMetadata(key string, value interface{}) *LedgerEvent {
 	return e
 }
 
+// WithValidity restricts the event to the window [from, until); a nil bound leaves that side open
+func (e *LedgerEvent) WithValidity(from, until *time.Time) *LedgerEvent {
+	e.ValidFrom = from
+	e.ValidUntil = until
+	return e
+}
+
+// WithExpiry makes a hold expire d after the event's timestamp; an expired hold no longer counts
+// towards the held amount, as if it had been released
+func (e *LedgerEvent) WithExpiry(d time.Duration) *LedgerEvent {
+	expiresAt := e.Timestamp.Add(d)
+	e.ExpiresAt = &expiresAt
+	return e
+}
+
 // WithVersion sets the version of the event
 func (e *LedgerEvent) WithVersion(version int64) *LedgerEvent {
 	e.Version = version
 	return e
 }
 
-// Sign generates a cryptographic signature for the event
-func (e *LedgerEvent) Sign(privateKey string) error {
-	// Create a canonical representation of the event for signing
-	eventData := map[string]interface{}{
+// canonicalPayload returns the fields covered by the event signature, with the metadata in
+// its canonical form
+func (e *LedgerEvent) canonicalPayload() (map[string]interface{}, error) {
+	metadata, err := canonicalMetadata(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	payload := map[string]interface{}{
 		"id":            e.ID,
 		"type":          string(e.Type),
 		"amount":        e.Amount,
@@ -99,72 +148,324 @@ func (e *LedgerEvent) Sign(privateKey string) error {
 		"paymentId":     e.PaymentID,
 		"referenceId":   e.ReferenceID,
 		"timestamp":     e.Timestamp.Unix(),
-		"metadata":      e.Metadata,
+		"metadata":      metadata,
 		"version":       e.Version,
 		"correlationId": e.CorrelationID,
 	}
+	// Optional structures are only covered when present, so events without them keep their signatures
+	if e.Fees != nil {
+		payload["fees"] = e.Fees
+	}
+	if e.Taxes != nil {
+		payload["taxes"] = e.Taxes
+	}
+	if e.EffectiveAt != nil {
+		payload["effectiveAt"] = e.EffectiveAt.Unix()
+	}
+	if e.ValidFrom != nil {
+		payload["validFrom"] = e.ValidFrom.Unix()
+	}
+	if e.ValidUntil != nil {
+		payload["validUntil"] = e.ValidUntil.Unix()
+	}
+	if len(e.References) > 0 {
+		payload["references"] = e.References
+	}
+	if e.ReversesEventID != nil {
+		payload["reversesEventId"] = *e.ReversesEventID
+	}
+	if e.HoldID != nil {
+		payload["holdId"] = *e.HoldID
+	}
+	if e.IdempotencyKey != "" {
+		payload["idempotencyKey"] = e.IdempotencyKey
+	}
+	if e.Priority != 0 {
+		payload["priority"] = e.Priority
+	}
+	if e.TenantID != "" {
+		payload["tenantId"] = e.TenantID
+	}
+	if _, nanos := splitAlgorithm(e.SignatureAlgorithm); nanos {
+		payload["timestamp"] = e.Timestamp.UTC().Format(time.RFC3339Nano)
+		// Hold expiry is covered from nanosecond signatures on; second-precision signatures
+		// keep the canonical form they were issued under, which predates it
+		if e.ExpiresAt != nil {
+			payload["expiresAt"] = e.ExpiresAt.Unix()
+		}
+	}
+	// Legacy shared-secret signatures predate the algorithm field, so that algorithm is left out
+	// and signatures issued before it was recorded keep verifying
+	if e.SignatureAlgorithm != "" && e.SignatureAlgorithm != AlgorithmSHA256Shared {
+		payload["signatureAlgorithm"] = e.SignatureAlgorithm
+	}
+	return payload, nil
+}
 
-	// Convert to JSON bytes
-	jsonBytes, err := json.Marshal(eventData)
+// canonicalMetadata returns metadata as it reads back from JSON: every object a map, encoded
+// with sorted keys at every depth, and every number a float64, encoded in encoding/json's
+// float format. Metadata that is already JSON-shaped is unchanged, so existing signatures keep
+// verifying, while structs, json.Numbers and other Go values sign the same before and after
+// the event is stored and reloaded. Numbers a float64 cannot hold exactly are rejected with
+// ErrInexactMetadataNumber rather than rounded into another number's encoding.
+func canonicalMetadata(metadata map[string]interface{}) (interface{}, error) {
+	if metadata == nil {
+		return metadata, nil
+	}
+	encoded, err := json.Marshal(metadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event for signing: %w", err)
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var canonical interface{}
+	if err := decoder.Decode(&canonical); err != nil {
+		return nil, err
+	}
+	return floatNumbers(canonical, "metadata")
+}
+
+// CanonicalBytes returns the deterministic encoding of the fields covered by the event
+// signature, the bytes Sign, SignWith and their Verify counterparts hash. Keys are sorted at
+// every depth and metadata numbers are normalized, so the bytes survive a round trip through
+// ToJSON and LedgerEventFromJSON unchanged.
+func (e *LedgerEvent) CanonicalBytes() ([]byte, error) {
+	if err := checkMetadataDepth(e.Metadata); err != nil {
+		return nil, err
+	}
+	payload, err := e.canonicalPayload()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(payload)
+}
+
+// signingBytesAs returns the canonical bytes of the event as signed with algorithm
+func (e *LedgerEvent) signingBytesAs(algorithm string) ([]byte, error) {
+	signed := *e
+	signed.SignatureAlgorithm = algorithm
+	return signed.CanonicalBytes()
+}
+
+// hash returns the SHA-256 of the event's canonical bytes without the signature algorithm, so
+// re-signing under another algorithm keeps the event's chain and Merkle hashes
+func (e *LedgerEvent) hash() ([32]byte, error) {
+	canonical, err := e.signingBytesAs("")
+	if err != nil {
+		return [32]byte{}, err
 	}
+	return sha256.Sum256(canonical), nil
+}
 
-	// Create SHA-256 hash and combine with private key for signature
-	hash := sha256.Sum256(jsonBytes)
-	combined := fmt.Sprintf("%s:%s", hex.EncodeToString(hash[:]), privateKey)
+// signatureFor derives the signature of the canonical bytes under the given key
+func signatureFor(canonical []byte, key string) string {
+	hash := sha256.Sum256(canonical)
+	combined := fmt.Sprintf("%s:%s", hex.EncodeToString(hash[:]), key)
 	signatureHash := sha256.Sum256([]byte(combined))
-	
-	e.Signature = hex.EncodeToString(signatureHash[:])
+	return hex.EncodeToString(signatureHash[:])
+}
+
+// Sign generates a cryptographic signature for the event, covering its timestamp at nanosecond
+// precision unless WithTimestampPrecision selects SecondTimestamps
+//
+// Deprecated: the signature is a hash over the event and a shared secret, so anyone able to
+// verify it can also forge it. Use SignEd25519 or SignWith.
+func (e *LedgerEvent) Sign(privateKey string, opts ...SignOption) error {
+	recorded := recordedAlgorithm(AlgorithmSHA256Shared, opts)
+	canonical, err := e.signingBytesAs(recorded)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for signing: %w", err)
+	}
+
+	e.Signature = signatureFor(canonical, privateKey)
+	e.SignatureAlgorithm = recorded
 	return nil
 }
 
-// Verify verifies the cryptographic signature of the event
-func (e *LedgerEvent) Verify(publicKey string) bool {
+// Verify verifies a signature produced by Sign with the shared secret, as VerifyAs with
+// AlgorithmSHA256Shared. It never checks Ed25519 or HMAC signatures, whatever the event records.
+//
+// Deprecated: use VerifyEd25519 or VerifyWith.
+func (e *LedgerEvent) Verify(secret string) bool {
+	return e.VerifyAs(AlgorithmSHA256Shared, secret)
+}
+
+// VerifyAs verifies the event's signature as one produced with algorithm, which the caller
+// expects: an event recording any other algorithm fails, so editing the recorded algorithm
+// cannot move verification onto a weaker path. For AlgorithmEd25519 key is the hex-encoded
+// public key, for AlgorithmHMACSHA256 the shared secret of the event's KeyID, and for
+// AlgorithmSHA256Shared the secret given to Sign; only the last accepts events signed before
+// the algorithm was recorded.
+func (e *LedgerEvent) VerifyAs(algorithm, key string) bool {
 	if e.Signature == "" {
 		return false
 	}
+	recorded, _ := splitAlgorithm(e.SignatureAlgorithm)
+	if recorded == "" {
+		recorded = AlgorithmSHA256Shared
+	}
+	if recorded != algorithm {
+		return false
+	}
 
-	// Recreate the canonical representation
-	eventData := map[string]interface{}{
-		"id":            e.ID,
-		"type":          string(e.Type),
-		"amount":        e.Amount,
-		"currency":      e.Currency,
-		"accountId":     e.AccountID,
-		"paymentId":     e.PaymentID,
-		"referenceId":   e.ReferenceID,
-		"timestamp":     e.Timestamp.Unix(),
-		"metadata":      e.Metadata,
-		"version":       e.Version,
-		"correlationId": e.CorrelationID,
+	switch algorithm {
+	case AlgorithmEd25519:
+		pub, err := hex.DecodeString(key)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return false
+		}
+		return e.VerifyEd25519(ed25519.PublicKey(pub))
+	case AlgorithmHMACSHA256:
+		return e.VerifyWith(NewHMACKey(e.KeyID, []byte(key))) == nil
+	case AlgorithmSHA256Shared:
+		canonical, err := e.CanonicalBytes()
+		if err != nil {
+			return false
+		}
+		return e.Signature == signatureFor(canonical, key)
+	}
+	return false
+}
+
+// SignEd25519 signs the event's canonical bytes with an Ed25519 private key, so only the key
+// holder can sign while anyone with the public key can verify
+func (e *LedgerEvent) SignEd25519(priv ed25519.PrivateKey, opts ...SignOption) error {
+	if len(priv) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid ed25519 private key length %d", len(priv))
+	}
+	recorded := recordedAlgorithm(AlgorithmEd25519, opts)
+	canonical, err := e.signingBytesAs(recorded)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for signing: %w", err)
 	}
 
-	jsonBytes, err := json.Marshal(eventData)
+	e.Signature = hex.EncodeToString(ed25519.Sign(priv, canonical))
+	e.SignatureAlgorithm = recorded
+	return nil
+}
+
+// VerifyEd25519 reports whether the event carries a valid Ed25519 signature by pub's private key
+func (e *LedgerEvent) VerifyEd25519(pub ed25519.PublicKey) bool {
+	if algorithm, _ := splitAlgorithm(e.SignatureAlgorithm); algorithm != AlgorithmEd25519 {
+		return false
+	}
+	if e.Signature == "" || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	signature, err := hex.DecodeString(e.Signature)
+	if err != nil {
+		return false
+	}
+	canonical, err := e.CanonicalBytes()
 	if err != nil {
 		return false
 	}
+	return ed25519.Verify(pub, canonical, signature)
+}
 
-	hash := sha256.Sum256(jsonBytes)
-	combined := fmt.Sprintf("%s:%s", hex.EncodeToString(hash[:]), publicKey)
-	expectedSignatureHash := sha256.Sum256([]byte(combined))
-	expectedSignature := hex.EncodeToString(expectedSignatureHash[:])
+// SignWith signs the event's canonical bytes with signer, recording the signer's key ID and algorithm
+func (e *LedgerEvent) SignWith(signer Signer, opts ...SignOption) error {
+	recorded := recordedAlgorithm(signer.Algorithm(), opts)
+	canonical, err := e.signingBytesAs(recorded)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for signing: %w", err)
+	}
 
-	return e.Signature == expectedSignature
+	signature, err := signer.Sign(canonical)
+	if err != nil {
+		return fmt.Errorf("failed to sign event: %w", err)
+	}
+
+	e.Signature = hex.EncodeToString(signature)
+	e.SignatureAlgorithm = recorded
+	e.KeyID = signer.KeyID()
+	return nil
 }
 
-// ToJSON converts the event to JSON bytes
+// VerifyWith verifies a signature produced by SignWith, returning ErrInvalidSignature on mismatch.
+// A verifier for a key the event was signed with before Resign checks the recorded prior signature.
+func (e *LedgerEvent) VerifyWith(verifier Verifier) error {
+	if e.Signature == "" {
+		return fmt.Errorf("%w: event is not signed", ErrInvalidSignature)
+	}
+
+	encoded, algorithm := e.Signature, e.SignatureAlgorithm
+	if e.KeyID != verifier.KeyID() {
+		prior, ok := e.priorSignature(verifier.KeyID())
+		if !ok {
+			return fmt.Errorf("%w: signed with key %q, verifier has %q", ErrInvalidSignature, e.KeyID, verifier.KeyID())
+		}
+		encoded, algorithm = prior.Signature, prior.Algorithm
+	}
+
+	signature, err := hex.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	canonical, err := e.signingBytesAs(algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event for verification: %w", err)
+	}
+	return verifier.Verify(canonical, signature)
+}
+
+// DebugCanonical returns an annotated view of the bytes covered by the signature:
+// each field in encoded order with its JSON value, then the raw bytes as hex and their SHA-256.
+// It is meant for diagnosing signature mismatches between teams, not for parsing.
+func (e *LedgerEvent) DebugCanonical() string {
+	canonical, err := e.CanonicalBytes()
+	if err != nil {
+		return fmt.Sprintf("canonical form unavailable: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(canonical, &fields); err != nil {
+		return fmt.Sprintf("canonical form unavailable: %v", err)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "canonical form (%d fields, keys sorted):\n", len(keys))
+	for i, key := range keys {
+		fmt.Fprintf(&b, "  [%d] %s = %s\n", i, key, fields[key])
+	}
+	hash := sha256.Sum256(canonical)
+	fmt.Fprintf(&b, "bytes (%d): %s\n", len(canonical), hex.EncodeToString(canonical))
+	fmt.Fprintf(&b, "sha256: %s\n", hex.EncodeToString(hash[:]))
+	return b.String()
+}
+
+// ToJSON converts the event to JSON bytes, recording CurrentSchemaVersion in a top-level
+// schemaVersion field
 func (e *LedgerEvent) ToJSON() ([]byte, error) {
-	return json.Marshal(e)
+	return json.Marshal(versionedEvent{SchemaVersion: CurrentSchemaVersion, LedgerEvent: e})
 }
 
-// LedgerEventFromJSON creates a LedgerEvent from JSON bytes
+// LedgerEventFromJSON creates a LedgerEvent from JSON bytes, first migrating payloads of older
+// schema versions to the current shape. Payloads of a version newer than CurrentSchemaVersion
+// fail with ErrUnsupportedSchemaVersion. Amounts recorded as floats are migrated to minor units
+// at their precision, see Money.UnmarshalJSON.
 func LedgerEventFromJSON(jsonBytes []byte) (*LedgerEvent, error) {
-	var event LedgerEvent
-	err := json.Unmarshal(jsonBytes, &event)
+	var fields eventFields
+	if err := json.Unmarshal(jsonBytes, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ledger event: %w", err)
+	}
+	if err := migrateSchema(fields); err != nil {
+		return nil, err
+	}
+	migrated, err := json.Marshal(fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal ledger event: %w", err)
 	}
+
+	var event LedgerEvent
+	if err := json.Unmarshal(migrated, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ledger event: %w", err)
+	}
 	return &event, nil
 }
 
@@ -178,12 +479,28 @@ func (e *LedgerEvent) Validate() error {
 		return fmt.Errorf("event type is required")
 	}
 
-	if e.Amount.Amount <= 0 {
-		return fmt.Errorf("amount must be greater than 0")
-	}
-
-	if e.Currency == "" {
-		return fmt.Errorf("currency is required")
+	if e.IsControl() {
+		if e.Amount.MinorUnits != 0 {
+			return fmt.Errorf("%s event must not carry an amount", e.Type)
+		}
+	} else {
+		// Corrective adjustments may reduce a balance, so only they may be negative
+		switch {
+		case e.IsAdjustment() && e.Amount.Sign() == 0:
+			return fmt.Errorf("adjustment amount must not be 0")
+		case !e.IsAdjustment() && e.Amount.Sign() <= 0:
+			return fmt.Errorf("amount must be greater than 0")
+		}
+
+		if e.Currency == "" {
+			return fmt.Errorf("currency is required")
+		}
+		if err := checkCurrencyCode(e.Currency); err != nil {
+			return err
+		}
+		if e.Amount.Currency != e.Currency {
+			return fmt.Errorf("%w: event currency %s, amount currency %s", ErrCurrencyMismatch, e.Currency, e.Amount.Currency)
+		}
 	}
 
 	if e.AccountID == "" {
@@ -202,6 +519,10 @@ func (e *LedgerEvent) Validate() error {
 		return fmt.Errorf("version must be greater than 0")
 	}
 
+	if err := checkHashLength(e.HashLength); err != nil {
+		return err
+	}
+
 	// Validate event type
 	validTypes := map[EventType]bool{
 		Debit:      true,
@@ -210,12 +531,94 @@ func (e *LedgerEvent) Validate() error {
 		Release:    true,
 		Reversal:   true,
 		Adjustment: true,
+
+		AccountFreeze:   true,
+		AccountUnfreeze: true,
+		AccountClose:    true,
+
+		ChargebackReceived:    true,
+		ChargebackRepresented: true,
+		ChargebackArbitration: true,
+		ChargebackWon:         true,
+		ChargebackLost:        true,
 	}
 
 	if !validTypes[e.Type] {
 		return fmt.Errorf("invalid event type: %s", e.Type)
 	}
 
+	if err := e.checkReversalLink(); err != nil {
+		return err
+	}
+
+	if err := e.checkHoldLink(); err != nil {
+		return err
+	}
+
+	if err := checkIdempotencyKey(e.IdempotencyKey); err != nil {
+		return err
+	}
+
+	if e.Fees != nil {
+		if err := e.Fees.Validate(e.Amount); err != nil {
+			return err
+		}
+	}
+	if e.Taxes != nil {
+		if err := e.Taxes.Validate(e.Amount); err != nil {
+			return err
+		}
+	}
+
+	if e.ExpiresAt != nil {
+		if !e.IsHold() {
+			return fmt.Errorf("%s event must not expire", e.Type)
+		}
+		if !e.ExpiresAt.After(e.Timestamp) {
+			return fmt.Errorf("hold expires at %s, not after it is created at %s",
+				e.ExpiresAt.Format(time.RFC3339), e.Timestamp.Format(time.RFC3339))
+		}
+	}
+
+	if e.ValidFrom != nil && e.ValidUntil != nil && e.ValidUntil.Before(*e.ValidFrom) {
+		return fmt.Errorf("validity window ends at %s before it starts at %s",
+			e.ValidUntil.Format(time.RFC3339), e.ValidFrom.Format(time.RFC3339))
+	}
+
+	if err := checkMetadataDepth(e.Metadata); err != nil {
+		return err
+	}
+	if _, err := canonicalMetadata(e.Metadata); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	return nil
+}
+
+// checkCurrencyCode rejects codes that are not uppercase ISO 4217 codes in the currency table
+func checkCurrencyCode(code string) error {
+	if code != strings.ToUpper(code) {
+		return fmt.Errorf("%w: currency code %q must be uppercase", ErrUnknownCurrency, code)
+	}
+	if !currency.IsValid(code) {
+		return fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return nil
+}
+
+// CheckPrecision reports an ErrPrecisionMismatch if the amount's precision differs from its
+// currency's minor-unit exponent. Validate accepts such amounts, since a finer precision can
+// be deliberate, so services that require the standard precision call this as well.
+func (e *LedgerEvent) CheckPrecision() error {
+	if e.IsControl() {
+		return nil
+	}
+	if err := checkCurrencyCode(e.Amount.Currency); err != nil {
+		return err
+	}
+	if standard, _ := currency.Precision(e.Amount.Currency); e.Amount.Precision != standard {
+		return fmt.Errorf("%w: %s has precision %d, amount has %d", ErrPrecisionMismatch, e.Amount.Currency, standard, e.Amount.Precision)
+	}
 	return nil
 }
 
@@ -249,20 +652,62 @@ func (e *LedgerEvent) IsAdjustment() bool {
 	return e.Type == Adjustment
 }
 
+// IsExpired returns true if the event is a hold whose expiry is at or before now
+func (e *LedgerEvent) IsExpired(now time.Time) bool {
+	return e.IsHold() && e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// IsValidAt returns true if now falls within the event's validity window. ValidFrom is
+// inclusive and ValidUntil exclusive; an unset bound leaves that side of the window open.
+func (e *LedgerEvent) IsValidAt(now time.Time) bool {
+	if e.ValidFrom != nil && now.Before(*e.ValidFrom) {
+		return false
+	}
+	return e.ValidUntil == nil || now.Before(*e.ValidUntil)
+}
+
+// IsControl returns true if the event changes account state without moving money
+func (e *LedgerEvent) IsControl() bool {
+	return e.Type == AccountFreeze || e.Type == AccountUnfreeze || e.Type == AccountClose
+}
+
 // AffectsBalance returns true if the event affects the account balance
 func (e *LedgerEvent) AffectsBalance() bool {
 	return e.IsDebit() || e.IsCredit() || e.IsAdjustment()
 }
 
+// PostsBalance returns true if the event can move the posted balance: a balance-affecting
+// event, or a reversal of one
+func (e *LedgerEvent) PostsBalance() bool {
+	return e.AffectsBalance() || e.IsReversal()
+}
+
 // AffectsHolds returns true if the event affects holds
 func (e *LedgerEvent) AffectsHolds() bool {
 	return e.IsHold() || e.IsRelease()
 }
 
+// WithdrawsFunds returns true if the event takes money out of the account: a debit, an
+// adjustment with a negative amount, or a reversal of a credit or positive adjustment, as
+// recorded on the reversal. Use Withdraws when the reversed events are at hand.
+func (e *LedgerEvent) WithdrawsFunds() bool {
+	return e.Withdraws(nil)
+}
+
+// Withdraws is WithdrawsFunds resolving what reversals undo through originals, as PostedAmount
+// does. A reversal whose effect cannot be determined counts as a withdrawal.
+func (e *LedgerEvent) Withdraws(originals map[string]EventType) bool {
+	if !e.PostsBalance() {
+		return false
+	}
+	posted, err := PostedAmount(e, originals)
+	return err != nil || posted.Sign() < 0
+}
+
 // String returns a string representation of the event
 func (e *LedgerEvent) String() string {
 	return fmt.Sprintf("LedgerEvent{ID: %s, Type: %s, Amount: %.2f %s, AccountID: %s, Timestamp: %s}",
-		e.ID, e.Type, e.Amount.Amount, e.Currency, e.AccountID, e.Timestamp.Format(time.RFC3339))
+		e.ID, e.Type, e.Amount.Float(), e.Currency, e.AccountID, e.Timestamp.Format(time.RFC3339))
 }
 
 // generateEventID generates a unique event ID
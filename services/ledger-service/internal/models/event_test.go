@@ -0,0 +1,307 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func debugLine(t *testing.T, debug, prefix string) string {
+	t.Helper()
+	for _, line := range strings.Split(debug, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			_, value, found := strings.Cut(line, ": ")
+			require.True(t, found)
+			return value
+		}
+	}
+	t.Fatalf("no %q line in debug output:\n%s", prefix, debug)
+	return ""
+}
+
+func TestDebugCanonicalMatchesSignedBytes(t *testing.T) {
+	event := NewLedgerEvent(Credit, NewMoney(4250, "USD", 2), "acc_1", "corr_1").
+		WithPaymentID("pay_1").
+		WithMetadata("channel", "card")
+	require.NoError(t, event.Sign("secret"))
+
+	debug := event.DebugCanonical()
+
+	canonical, err := hex.DecodeString(debugLine(t, debug, "bytes"))
+	require.NoError(t, err)
+	expected, err := event.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Equal(t, expected, canonical)
+
+	hash := sha256.Sum256(canonical)
+	assert.Equal(t, hex.EncodeToString(hash[:]), debugLine(t, debug, "sha256"))
+	assert.Equal(t, event.Signature, signatureFor(canonical, "secret"))
+
+	assert.Contains(t, debug, `accountId = "acc_1"`)
+	assert.Contains(t, debug, `paymentId = "pay_1"`)
+	assert.Contains(t, debug, `referenceId = null`)
+}
+
+func TestValidateRejectsInvertedValidityWindow(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := from.Add(-time.Hour)
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+
+	assert.Error(t, event.WithValidity(&from, &until).Validate())
+	assert.NoError(t, event.WithValidity(&from, nil).Validate())
+	assert.False(t, event.IsValidAt(until))
+	assert.True(t, event.IsValidAt(from))
+}
+
+func TestValidateAllowsOnlyAdjustmentsToBeNegative(t *testing.T) {
+	assert.NoError(t, NewLedgerEvent(Adjustment, usdAmount(-5), "acc_1", "corr_1").Validate())
+	assert.Error(t, NewLedgerEvent(Adjustment, usdAmount(0), "acc_1", "corr_1").Validate())
+	assert.Error(t, NewLedgerEvent(Credit, usdAmount(-5), "acc_1", "corr_1").Validate())
+	assert.Error(t, NewLedgerEvent(Debit, usdAmount(-5), "acc_1", "corr_1").Validate())
+}
+
+func TestHoldExpiry(t *testing.T) {
+	hold := NewLedgerEvent(Hold, usdAmount(10), "acc_1", "corr_1").WithExpiry(time.Hour)
+	require.NoError(t, hold.Validate())
+	assert.Equal(t, hold.Timestamp.Add(time.Hour), *hold.ExpiresAt)
+	assert.False(t, hold.IsExpired(hold.Timestamp.Add(59*time.Minute)))
+	assert.True(t, hold.IsExpired(hold.Timestamp.Add(time.Hour)))
+
+	assert.Error(t, NewLedgerEvent(Hold, usdAmount(10), "acc_1", "corr_1").WithExpiry(-time.Minute).Validate())
+	assert.Error(t, NewLedgerEvent(Hold, usdAmount(10), "acc_1", "corr_1").WithExpiry(0).Validate())
+	assert.Error(t, NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithExpiry(time.Hour).Validate())
+}
+
+func TestHoldExpiryIsSigned(t *testing.T) {
+	hold := NewLedgerEvent(Hold, usdAmount(10), "acc_1", "corr_1").WithExpiry(24 * time.Hour)
+	require.NoError(t, hold.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+
+	earlier := hold.Timestamp.Add(time.Minute)
+	hold.ExpiresAt = &earlier
+	assert.ErrorIs(t, hold.VerifyWith(NewHMACKey("ledger-1", []byte("secret"))), ErrInvalidSignature)
+
+	require.NoError(t, hold.Sign("secret"))
+	later := hold.Timestamp.Add(time.Hour)
+	hold.ExpiresAt = &later
+	assert.False(t, hold.Verify("secret"))
+}
+
+func TestValidateRejectsInvalidCurrencies(t *testing.T) {
+	for _, code := range []string{"USDD", "usd", "XYZ"} {
+		event := NewLedgerEvent(Credit, NewMoney(1000, code, 2), "acc_1", "corr_1")
+		assert.ErrorIs(t, event.Validate(), ErrUnknownCurrency, code)
+	}
+
+	diverged := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	diverged.Currency = "EUR"
+	assert.ErrorIs(t, diverged.Validate(), ErrCurrencyMismatch)
+
+	assert.NoError(t, NewLedgerEvent(Credit, NewMoney(500, "JPY", 0), "acc_1", "corr_1").Validate())
+}
+
+func TestCheckPrecisionFlagsNonStandardPrecision(t *testing.T) {
+	assert.NoError(t, NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").CheckPrecision())
+	assert.NoError(t, NewAccountFreeze("acc_1", "corr_1", "review").CheckPrecision())
+
+	fine := NewLedgerEvent(Credit, NewMoney(10005, "USD", 3), "acc_1", "corr_1")
+	assert.NoError(t, fine.Validate())
+	assert.ErrorIs(t, fine.CheckPrecision(), ErrPrecisionMismatch)
+
+	assert.ErrorIs(t, NewLedgerEvent(Credit, NewMoney(100, "KWD", 2), "acc_1", "corr_1").CheckPrecision(), ErrPrecisionMismatch)
+}
+
+func TestSignEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignEd25519(priv))
+	assert.Equal(t, withTimestampNanos(AlgorithmEd25519), event.SignatureAlgorithm)
+
+	assert.True(t, event.VerifyEd25519(pub))
+	assert.False(t, event.VerifyEd25519(otherPub))
+	assert.True(t, event.VerifyAs(AlgorithmEd25519, hex.EncodeToString(pub)))
+	assert.False(t, event.Verify(hex.EncodeToString(pub)), "Verify only checks shared-secret signatures")
+	assert.False(t, event.VerifyAs(AlgorithmSHA256Shared, hex.EncodeToString(pub)))
+
+	event.Amount.MinorUnits = 100000
+	assert.False(t, event.VerifyEd25519(pub))
+}
+
+func TestVerifyKeepsLegacySignatures(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.Sign("secret", WithTimestampPrecision(SecondTimestamps)))
+	assert.Equal(t, AlgorithmSHA256Shared, event.SignatureAlgorithm)
+	assert.True(t, event.Verify("secret"))
+
+	event.SignatureAlgorithm = ""
+	assert.True(t, event.Verify("secret"), "events signed before the algorithm was recorded still verify")
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	require.NoError(t, event.SignEd25519(priv))
+	assert.False(t, event.Verify("secret"), "an Ed25519 signature is not checked as a shared-secret one")
+}
+
+func TestVerifyRejectsAlgorithmDowngrade(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	pubHex := hex.EncodeToString(pub)
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignEd25519(priv))
+
+	// Tamper with the amount and re-sign along the legacy path, using the public key as the secret
+	for _, downgraded := range []string{"", AlgorithmSHA256Shared} {
+		forged := *event
+		forged.Amount = usdAmount(10000)
+		forged.SignatureAlgorithm = downgraded
+		canonical, err := forged.CanonicalBytes()
+		require.NoError(t, err)
+		forged.Signature = signatureFor(canonical, pubHex)
+
+		assert.False(t, forged.VerifyAs(AlgorithmEd25519, pubHex), "downgrade to %q", downgraded)
+		assert.False(t, forged.VerifyEd25519(pub), "downgrade to %q", downgraded)
+	}
+
+	hmacSigned := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, hmacSigned.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+	hmacSigned.SignatureAlgorithm = ""
+	assert.False(t, hmacSigned.VerifyAs(AlgorithmHMACSHA256, "secret"))
+}
+
+func TestSignatureAlgorithmIsSigned(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignWith(key))
+	assert.Equal(t, withTimestampNanos(AlgorithmHMACSHA256), event.SignatureAlgorithm)
+	assert.NoError(t, event.VerifyWith(key))
+	assert.True(t, event.VerifyAs(AlgorithmHMACSHA256, "secret"))
+	assert.False(t, event.Verify("secret"), "an HMAC signature is not checked as a legacy one")
+
+	event.SignatureAlgorithm = AlgorithmEd25519
+	assert.ErrorIs(t, event.VerifyWith(key), ErrInvalidSignature)
+	event.SignatureAlgorithm = ""
+	assert.ErrorIs(t, event.VerifyWith(key), ErrInvalidSignature)
+}
+
+func TestSignatureCoversSubSecondTimestamp(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	stamp := time.Date(2026, 4, 1, 9, 30, 0, 100, time.UTC)
+
+	first := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	first.Timestamp = stamp
+	second := *first
+	second.Timestamp = stamp.Add(time.Millisecond)
+	require.NoError(t, first.SignWith(key))
+	require.NoError(t, second.SignWith(key))
+	assert.NotEqual(t, first.Signature, second.Signature, "events in the same second sign differently")
+
+	reloaded, err := first.ToJSON()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(reloaded)
+	require.NoError(t, err)
+	assert.NoError(t, decoded.VerifyWith(key))
+
+	first.Timestamp = stamp.Add(time.Nanosecond)
+	assert.ErrorIs(t, first.VerifyWith(key), ErrInvalidSignature)
+}
+
+func TestSecondPrecisionSignaturesStillVerify(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	event.Timestamp = time.Date(2026, 4, 1, 9, 30, 0, 500, time.UTC)
+
+	// Signed as before nanosecond timestamps were recorded
+	event.SignatureAlgorithm, event.KeyID = AlgorithmHMACSHA256, key.KeyID()
+	canonical, err := event.CanonicalBytes()
+	require.NoError(t, err)
+	signature, err := key.Sign(canonical)
+	require.NoError(t, err)
+	event.Signature = hex.EncodeToString(signature)
+
+	assert.NoError(t, event.VerifyWith(key))
+	event.Timestamp = event.Timestamp.Add(time.Millisecond)
+	assert.NoError(t, event.VerifyWith(key), "second-precision signatures only cover whole seconds")
+}
+
+func TestResignAcrossAlgorithmsKeepsPriorSignatures(t *testing.T) {
+	oldKey := NewHMACKey("ledger-2025", []byte("old-secret"))
+	newKey := &ed25519Signer{id: "ledger-2026"}
+	_, newKey.priv, _ = ed25519.GenerateKey(nil)
+
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignWith(oldKey))
+	require.NoError(t, event.Resign(newKey))
+
+	assert.Equal(t, withTimestampNanos(AlgorithmEd25519), event.SignatureAlgorithm)
+	assert.NoError(t, event.VerifyWith(oldKey))
+	assert.True(t, event.VerifyEd25519(newKey.priv.Public().(ed25519.PublicKey)))
+}
+
+// ed25519Signer is a minimal Signer over an Ed25519 key for tests
+type ed25519Signer struct {
+	id   string
+	priv ed25519.PrivateKey
+}
+
+func (s *ed25519Signer) KeyID() string     { return s.id }
+func (s *ed25519Signer) Algorithm() string { return AlgorithmEd25519 }
+func (s *ed25519Signer) Sign(payload []byte) ([]byte, error) {
+	return ed25519.Sign(s.priv, payload), nil
+}
+
+func TestCanonicalBytesSurviveRoundTrip(t *testing.T) {
+	type terminal struct {
+		Zone string `json:"zone"`
+		ID   int    `json:"id"`
+	}
+	newEvent := func() *LedgerEvent {
+		return NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").
+			WithMetadata("terminal", terminal{Zone: "eu", ID: 7}).
+			WithMetadata("ratio", json.Number("1.0")).
+			WithMetadata("count", int64(3)).
+			WithMetadata("nested", map[string]interface{}{"z": 1.5, "a": []interface{}{uint8(2), map[string]int{"y": 1, "b": 2}}})
+	}
+	reload := func(event *LedgerEvent) *LedgerEvent {
+		data, err := event.ToJSON()
+		require.NoError(t, err)
+		reloaded, err := LedgerEventFromJSON(data)
+		require.NoError(t, err)
+		return reloaded
+	}
+
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := newEvent()
+	require.NoError(t, event.SignWith(key))
+	before, err := event.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(before), `"ratio":1,`)
+	assert.Contains(t, string(before), `"terminal":{"id":7,"zone":"eu"}`)
+
+	reloaded := reload(event)
+	after, err := reloaded.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+	assert.NoError(t, reloaded.VerifyWith(key))
+	assert.NoError(t, reload(reloaded).VerifyWith(key))
+
+	legacy := newEvent()
+	require.NoError(t, legacy.Sign("secret"))
+	assert.True(t, reload(legacy).Verify("secret"))
+}
+
+func TestValidateHoldLink(t *testing.T) {
+	assert.NoError(t, NewLedgerEvent(Release, usdAmount(10), "acc_1", "corr_1").WithHoldID("evt_hold").Validate())
+	assert.NoError(t, NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithHoldID("evt_hold").Validate())
+	assert.Error(t, NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithHoldID("evt_hold").Validate())
+	assert.Error(t, NewLedgerEvent(Release, usdAmount(10), "acc_1", "corr_1").WithHoldID("").Validate())
+}
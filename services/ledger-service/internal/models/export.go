@@ -0,0 +1,85 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ManifestChunk is the chunk index ExportChunked requests the manifest writer with
+const ManifestChunk = -1
+
+// ChunkInfo describes one NDJSON chunk of a chunked export
+type ChunkInfo struct {
+	Index        int    `json:"index"`
+	Count        int    `json:"count"`
+	SHA256       string `json:"sha256"`
+	FirstEventID string `json:"firstEventId"`
+	LastEventID  string `json:"lastEventId"`
+}
+
+// ChunkManifest lists the chunks of a chunked export with their hashes
+type ChunkManifest struct {
+	ChunkSize int         `json:"chunkSize"`
+	Count     int         `json:"count"`
+	Chunks    []ChunkInfo `json:"chunks"`
+}
+
+// ExportChunked writes the events as NDJSON, one event per line, in chunks of chunkSize events.
+// Events are written in recorded order with version and ID as tie-breakers, so the same events
+// always produce byte-identical chunks regardless of input order, and an interrupted export
+// can be resumed by rewriting only the chunks whose hashes are missing or differ. Chunk i is
+// written to w(i); once every chunk is written the ChunkManifest is written as JSON to
+// w(ManifestChunk).
+func ExportChunked(events []*LedgerEvent, chunkSize int, w func(chunkIndex int) io.Writer) error {
+	if chunkSize <= 0 {
+		return fmt.Errorf("chunk size must be positive, got %d", chunkSize)
+	}
+
+	sorted := SortEvents(events, Recorded)
+	manifest := ChunkManifest{ChunkSize: chunkSize, Count: len(sorted), Chunks: []ChunkInfo{}}
+	for start, index := 0, 0; start < len(sorted); start, index = start+chunkSize, index+1 {
+		end := start + chunkSize
+		if end > len(sorted) {
+			end = len(sorted)
+		}
+		info, err := writeChunk(index, sorted[start:end], w(index))
+		if err != nil {
+			return err
+		}
+		manifest.Chunks = append(manifest.Chunks, info)
+	}
+
+	out := w(ManifestChunk)
+	if out == nil {
+		return fmt.Errorf("no writer for chunk manifest")
+	}
+	if err := json.NewEncoder(out).Encode(manifest); err != nil {
+		return fmt.Errorf("failed to write chunk manifest: %w", err)
+	}
+	return nil
+}
+
+// writeChunk writes events as NDJSON to out and returns the chunk's manifest entry
+func writeChunk(index int, events []*LedgerEvent, out io.Writer) (ChunkInfo, error) {
+	if out == nil {
+		return ChunkInfo{}, fmt.Errorf("no writer for chunk %d", index)
+	}
+
+	hash := sha256.New()
+	encoder := json.NewEncoder(io.MultiWriter(out, hash))
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return ChunkInfo{}, fmt.Errorf("failed to write event %s to chunk %d: %w", event.ID, index, err)
+		}
+	}
+	return ChunkInfo{
+		Index:        index,
+		Count:        len(events),
+		SHA256:       hex.EncodeToString(hash.Sum(nil)),
+		FirstEventID: events[0].ID,
+		LastEventID:  events[len(events)-1].ID,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportRun collects the chunks and manifest written by one ExportChunked call
+type exportRun struct {
+	chunks   map[int]*bytes.Buffer
+	manifest bytes.Buffer
+}
+
+func runExport(t *testing.T, events []*LedgerEvent, chunkSize int) *exportRun {
+	t.Helper()
+	run := &exportRun{chunks: make(map[int]*bytes.Buffer)}
+	require.NoError(t, ExportChunked(events, chunkSize, func(index int) io.Writer {
+		if index == ManifestChunk {
+			return &run.manifest
+		}
+		run.chunks[index] = &bytes.Buffer{}
+		return run.chunks[index]
+	}))
+	return run
+}
+
+func TestExportChunkedIsDeterministic(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	events := make([]*LedgerEvent, 7)
+	for i := range events {
+		events[i] = NewLedgerEvent(Credit, usdAmount(float64(i+1)), "acc_1", "corr_1").
+			WithVersion(int64(i+1)).
+			WithMetadata("batch", "b1")
+		events[i].Timestamp = base.Add(time.Duration(i) * time.Minute)
+	}
+
+	first := runExport(t, events, 3)
+	shuffled := []*LedgerEvent{events[4], events[0], events[6], events[2], events[1], events[5], events[3]}
+	second := runExport(t, shuffled, 3)
+
+	require.Len(t, first.chunks, 3)
+	for i, chunk := range first.chunks {
+		assert.Equal(t, chunk.Bytes(), second.chunks[i].Bytes(), "chunk %d", i)
+	}
+	assert.Equal(t, first.manifest.String(), second.manifest.String())
+
+	var manifest ChunkManifest
+	require.NoError(t, json.Unmarshal(first.manifest.Bytes(), &manifest))
+	assert.Equal(t, 3, manifest.ChunkSize)
+	assert.Equal(t, 7, manifest.Count)
+	require.Len(t, manifest.Chunks, 3)
+	assert.Equal(t, []int{3, 3, 1}, []int{manifest.Chunks[0].Count, manifest.Chunks[1].Count, manifest.Chunks[2].Count})
+	assert.Equal(t, events[0].ID, manifest.Chunks[0].FirstEventID)
+	assert.Equal(t, events[6].ID, manifest.Chunks[2].LastEventID)
+
+	for _, info := range manifest.Chunks {
+		sum := sha256.Sum256(first.chunks[info.Index].Bytes())
+		assert.Equal(t, hex.EncodeToString(sum[:]), info.SHA256)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(first.chunks[0].Bytes()))
+	var lines int
+	for scanner.Scan() {
+		event, err := LedgerEventFromJSON(scanner.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, events[lines].ID, event.ID)
+		lines++
+	}
+	assert.Equal(t, 3, lines)
+}
+
+func TestExportChunkedRejectsBadChunkSize(t *testing.T) {
+	err := ExportChunked(nil, 0, func(int) io.Writer { return io.Discard })
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package models
+
+import "fmt"
+
+// MaxByType returns the event of the given type with the largest amount, or nil if the stream
+// has none. The events of that type must share a currency; amounts at different precisions
+// compare exactly. Ties go to the earliest event.
+func MaxByType(events []*LedgerEvent, eventType EventType) (*LedgerEvent, error) {
+	return extremeByType(events, eventType, 1)
+}
+
+// MinByType returns the event of the given type with the smallest amount, or nil if the stream
+// has none, with the same requirements as MaxByType
+func MinByType(events []*LedgerEvent, eventType EventType) (*LedgerEvent, error) {
+	return extremeByType(events, eventType, -1)
+}
+
+// extremeByType returns the first event of eventType whose amount no other exceeds in
+// direction: 1 for the largest, -1 for the smallest
+func extremeByType(events []*LedgerEvent, eventType EventType, direction int) (*LedgerEvent, error) {
+	var extreme *LedgerEvent
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		if extreme == nil {
+			extreme = event
+			continue
+		}
+		if event.Amount.Currency != extreme.Amount.Currency {
+			return nil, fmt.Errorf("%w: %s events in %s and %s",
+				ErrCurrencyMismatch, eventType, extreme.Amount.Currency, event.Amount.Currency)
+		}
+		if compareAmounts(event.Amount, extreme.Amount) == direction {
+			extreme = event
+		}
+	}
+	return extreme, nil
+}
+
+// compareAmounts compares two amounts of the same currency at the finer of their precisions,
+// returning -1, 0 or +1
+func compareAmounts(a, b Money) int {
+	precision := maxInt(a.Precision, b.Precision)
+	x, y := a.minorAt(precision), b.minorAt(precision)
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
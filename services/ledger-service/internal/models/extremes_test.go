@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxByTypeSelectsLargestDebit(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, usdAmount(500), "acc_1", "corr_1"),
+		NewLedgerEvent(Debit, usdAmount(40), "acc_1", "corr_2"),
+		NewLedgerEvent(Debit, NewMoney(12050, "USD", 3), "acc_1", "corr_3"),
+		NewLedgerEvent(Hold, usdAmount(900), "acc_1", "corr_4"),
+		NewLedgerEvent(Debit, usdAmount(120), "acc_1", "corr_5"),
+		NewLedgerEvent(Debit, usdAmount(7.5), "acc_1", "corr_6"),
+	}
+
+	largest, err := MaxByType(events, Debit)
+	require.NoError(t, err)
+	assert.Same(t, events[4], largest)
+
+	smallest, err := MinByType(events, Debit)
+	require.NoError(t, err)
+	assert.Same(t, events[5], smallest)
+
+	credit, err := MaxByType(events, Credit)
+	require.NoError(t, err)
+	assert.Same(t, events[0], credit)
+}
+
+func TestMaxByTypeWithoutMatchingEvents(t *testing.T) {
+	events := []*LedgerEvent{NewLedgerEvent(Credit, usdAmount(5), "acc_1", "corr_1")}
+
+	largest, err := MaxByType(events, Debit)
+	require.NoError(t, err)
+	assert.Nil(t, largest)
+
+	smallest, err := MinByType(nil, Debit)
+	require.NoError(t, err)
+	assert.Nil(t, smallest)
+}
+
+func TestMaxByTypeRejectsMixedCurrencies(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1"),
+		NewLedgerEvent(Credit, NewMoney(100, "EUR", 2), "acc_1", "corr_2"),
+		NewLedgerEvent(Debit, NewMoney(500, "EUR", 2), "acc_1", "corr_3"),
+	}
+
+	_, err := MaxByType(events, Debit)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = MinByType(events, Debit)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	credit, err := MaxByType(events, Credit)
+	require.NoError(t, err)
+	assert.Same(t, events[1], credit)
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFeeBreakdownMismatch is returned when fee components do not sum to the event amount
+var ErrFeeBreakdownMismatch = errors.New("fee breakdown does not match amount")
+
+// FeeType identifies the party a fee component is paid to
+type FeeType string
+
+const (
+	InterchangeFee FeeType = "INTERCHANGE"
+	SchemeFee      FeeType = "SCHEME"
+	ProcessorFee   FeeType = "PROCESSOR"
+)
+
+// FeeComponent is a single typed fee
+type FeeComponent struct {
+	Type   FeeType `json:"type"`
+	Amount Money   `json:"amount"`
+}
+
+// FeeBreakdown itemizes the fees making up a fee event's amount
+type FeeBreakdown struct {
+	Components []FeeComponent `json:"components"`
+}
+
+// Total sums the components, which must all be in the given currency
+func (b FeeBreakdown) Total(currency string) (Money, error) {
+	total := Money{Currency: currency}
+	for _, component := range b.Components {
+		if component.Amount.Currency != currency {
+			return Money{}, fmt.Errorf("%w: %s fee in %s, expected %s",
+				ErrCurrencyMismatch, component.Type, component.Amount.Currency, currency)
+		}
+		total = total.add(component.Amount)
+	}
+	return total, nil
+}
+
+// Validate checks that every component is a known, positive fee and that they sum to amount
+func (b FeeBreakdown) Validate(amount Money) error {
+	if len(b.Components) == 0 {
+		return fmt.Errorf("fee breakdown has no components")
+	}
+	for _, component := range b.Components {
+		switch component.Type {
+		case InterchangeFee, SchemeFee, ProcessorFee:
+		default:
+			return fmt.Errorf("invalid fee type: %s", component.Type)
+		}
+		if component.Amount.Sign() <= 0 {
+			return fmt.Errorf("%s fee must be greater than 0", component.Type)
+		}
+	}
+
+	total, err := b.Total(amount.Currency)
+	if err != nil {
+		return err
+	}
+	if total.minorAt(amount.Precision) != amount.MinorUnits {
+		return fmt.Errorf("%w: components sum to %.*f, amount is %.*f",
+			ErrFeeBreakdownMismatch, amount.Precision, total.Float(), amount.Precision, amount.Float())
+	}
+	return nil
+}
+
+// WithFeeBreakdown attaches an itemized fee breakdown to the event
+func (e *LedgerEvent) WithFeeBreakdown(breakdown FeeBreakdown) *LedgerEvent {
+	e.Fees = &breakdown
+	return e
+}
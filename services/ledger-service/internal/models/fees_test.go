@@ -0,0 +1,56 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feeEvent(total float64, components ...FeeComponent) *LedgerEvent {
+	return NewLedgerEvent(Debit, NewMoney(int64(math.Round(total*100)), "USD", 2), "acc_merchant", "corr_1").
+		WithFeeBreakdown(FeeBreakdown{Components: components})
+}
+
+func usdFee(feeType FeeType, amount float64) FeeComponent {
+	return FeeComponent{Type: feeType, Amount: NewMoney(int64(math.Round(amount*100)), "USD", 2)}
+}
+
+func TestFeeBreakdownSumsToAmount(t *testing.T) {
+	event := feeEvent(1.30,
+		usdFee(InterchangeFee, 0.90),
+		usdFee(SchemeFee, 0.10),
+		usdFee(ProcessorFee, 0.30),
+	)
+	require.NoError(t, event.Validate())
+
+	total, err := event.Fees.Total("USD")
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(130, "USD", 2), total)
+}
+
+func TestFeeBreakdownMismatchFailsValidation(t *testing.T) {
+	event := feeEvent(1.50,
+		usdFee(InterchangeFee, 0.90),
+		usdFee(ProcessorFee, 0.30),
+	)
+	assert.ErrorIs(t, event.Validate(), ErrFeeBreakdownMismatch)
+}
+
+func TestFeeBreakdownRejectsForeignCurrency(t *testing.T) {
+	event := feeEvent(1.00,
+		usdFee(InterchangeFee, 0.50),
+		FeeComponent{Type: SchemeFee, Amount: NewMoney(50, "EUR", 2)},
+	)
+	assert.ErrorIs(t, event.Validate(), ErrCurrencyMismatch)
+}
+
+func TestFeeBreakdownIsSigned(t *testing.T) {
+	event := feeEvent(1.00, usdFee(InterchangeFee, 0.70), usdFee(ProcessorFee, 0.30))
+	require.NoError(t, event.Sign("secret"))
+
+	event.Fees.Components[0].Amount.MinorUnits = 60
+	event.Fees.Components[1].Amount.MinorUnits = 40
+	assert.False(t, event.Verify("secret"))
+}
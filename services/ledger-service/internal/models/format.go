@@ -0,0 +1,214 @@
+package models
+
+import (
+	"strings"
+	"sync"
+
+	"fintech-platform/ledger-service/internal/currency"
+)
+
+// SymbolPosition places the currency symbol relative to the number
+type SymbolPosition int
+
+const (
+	// PositionDefault keeps the locale's default placement
+	PositionDefault SymbolPosition = iota
+	// PositionBefore writes the symbol before the number, e.g. $10.00
+	PositionBefore
+	// PositionAfter writes the symbol after the number, e.g. 10.00 €
+	PositionAfter
+)
+
+// SymbolSpacing controls whether a space separates the symbol from the number
+type SymbolSpacing int
+
+const (
+	// SpacingDefault keeps the locale's default spacing
+	SpacingDefault SymbolSpacing = iota
+	// SpacingNone writes the symbol next to the number
+	SpacingNone
+	// SpacingSpace puts a space between the symbol and the number
+	SpacingSpace
+)
+
+// NegativeStyle controls how a negative amount is marked
+type NegativeStyle int
+
+const (
+	// NegativeDefault keeps the locale's default style
+	NegativeDefault NegativeStyle = iota
+	// NegativeLeadingSign writes a minus sign before the symbol and number, e.g. -$10.00
+	NegativeLeadingSign
+	// NegativeSignedNumber writes the minus sign directly before the number, e.g. € -10,00
+	NegativeSignedNumber
+	// NegativeParentheses wraps the amount in parentheses, as accounting statements do, e.g. ($10.00)
+	NegativeParentheses
+)
+
+// DisplayOverride customizes how a currency is displayed. Zero fields fall back to the default.
+type DisplayOverride struct {
+	Symbol   string
+	Position SymbolPosition
+	Spacing  SymbolSpacing
+	Negative NegativeStyle
+}
+
+// displayOverrides is the registry consulted by Money.Format
+var displayOverrides = struct {
+	sync.RWMutex
+	byKey map[displayKey]DisplayOverride
+}{byKey: make(map[displayKey]DisplayOverride)}
+
+type displayKey struct {
+	currency string
+	locale   string
+}
+
+// RegisterDisplayOverride customizes how currency is displayed in locale, or in every locale
+// without an override of its own when locale is empty
+func RegisterDisplayOverride(currencyCode, locale string, override DisplayOverride) {
+	displayOverrides.Lock()
+	defer displayOverrides.Unlock()
+	displayOverrides.byKey[displayKey{currency: currencyCode, locale: locale}] = override
+}
+
+// ClearDisplayOverrides removes every registered override
+func ClearDisplayOverrides() {
+	displayOverrides.Lock()
+	defer displayOverrides.Unlock()
+	displayOverrides.byKey = make(map[displayKey]DisplayOverride)
+}
+
+// lookupDisplayOverride returns the override for the currency in locale, falling back to its
+// locale-independent override when fallback is set
+func lookupDisplayOverride(currencyCode, locale string, fallback bool) (DisplayOverride, bool) {
+	displayOverrides.RLock()
+	defer displayOverrides.RUnlock()
+	if override, ok := displayOverrides.byKey[displayKey{currency: currencyCode, locale: locale}]; ok && locale != "" {
+		return override, true
+	}
+	if !fallback {
+		return DisplayOverride{}, false
+	}
+	override, ok := displayOverrides.byKey[displayKey{currency: currencyCode}]
+	return override, ok
+}
+
+// numberConventions are a language's separators and default display
+type numberConventions struct {
+	decimal string
+	group   string
+	display DisplayOverride
+}
+
+var (
+	symbolBefore      = DisplayOverride{Position: PositionBefore, Spacing: SpacingNone, Negative: NegativeLeadingSign}
+	symbolBeforeSpace = DisplayOverride{Position: PositionBefore, Spacing: SpacingSpace, Negative: NegativeSignedNumber}
+	symbolAfter       = DisplayOverride{Position: PositionAfter, Spacing: SpacingSpace, Negative: NegativeLeadingSign}
+)
+
+// languageConventions lists the languages Format knows, keyed by the language of the locale
+var languageConventions = map[string]numberConventions{
+	"en": {decimal: ".", group: ",", display: symbolBefore},
+	"ja": {decimal: ".", group: ",", display: symbolBefore},
+	"ko": {decimal: ".", group: ",", display: symbolBefore},
+	"zh": {decimal: ".", group: ",", display: symbolBefore},
+	"nl": {decimal: ",", group: ".", display: symbolBeforeSpace},
+	"de": {decimal: ",", group: ".", display: symbolAfter},
+	"es": {decimal: ",", group: ".", display: symbolAfter},
+	"it": {decimal: ",", group: ".", display: symbolAfter},
+	"pt": {decimal: ",", group: ".", display: symbolAfter},
+	"fr": {decimal: ",", group: "\u202f", display: symbolAfter},
+	"cs": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"fi": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"pl": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"ru": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"sk": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"sv": {decimal: ",", group: "\u00a0", display: symbolAfter},
+	"uk": {decimal: ",", group: "\u00a0", display: symbolAfter},
+}
+
+// neutralConventions format amounts for unknown locales as "<code> <amount>"
+var neutralConventions = numberConventions{decimal: ".", display: symbolBeforeSpace}
+
+// conventionsFor returns the conventions of a locale such as "en-US", and whether Format knows it
+func conventionsFor(locale string) (numberConventions, bool) {
+	language, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	conventions, ok := languageConventions[strings.ToLower(language)]
+	if !ok {
+		return neutralConventions, false
+	}
+	return conventions, true
+}
+
+// Format renders the amount for display in locale, with the locale's decimal and thousands
+// separators, symbol placement and negative style, applying any registered DisplayOverride.
+// Locales of languages it does not know get the neutral "USD 1234.50" form, and only overrides
+// registered for that exact locale. It is for people, not for parsing.
+func (m Money) Format(locale string) string {
+	conventions, known := conventionsFor(locale)
+	display := conventions.display
+	display.Symbol = m.Currency
+	if known {
+		display.Symbol = currency.Symbol(m.Currency)
+	}
+	if override, ok := lookupDisplayOverride(m.Currency, locale, known); ok {
+		if override.Symbol != "" {
+			display.Symbol = override.Symbol
+		}
+		if override.Position != PositionDefault {
+			display.Position = override.Position
+		}
+		if override.Spacing != SpacingDefault {
+			display.Spacing = override.Spacing
+		}
+		if override.Negative != NegativeDefault {
+			display.Negative = override.Negative
+		}
+	}
+
+	whole, fraction, _ := strings.Cut(DecimalFromMoney(m).Abs().rat().FloatString(m.Precision), ".")
+	number := groupThousands(whole, conventions.group)
+	if fraction != "" {
+		number += conventions.decimal + fraction
+	}
+
+	negative := m.Sign() < 0
+	if negative && display.Negative == NegativeSignedNumber {
+		number = "-" + number
+	}
+	space := ""
+	if display.Spacing == SpacingSpace {
+		space = " "
+	}
+	formatted := display.Symbol + space + number
+	if display.Position == PositionAfter {
+		formatted = number + space + display.Symbol
+	}
+
+	switch {
+	case !negative || display.Negative == NegativeSignedNumber:
+		return formatted
+	case display.Negative == NegativeParentheses:
+		return "(" + formatted + ")"
+	}
+	return "-" + formatted
+}
+
+// groupThousands inserts separator between every group of three digits of whole
+func groupThousands(whole, separator string) string {
+	if separator == "" || len(whole) <= 3 {
+		return whole
+	}
+	var b strings.Builder
+	first := len(whole) % 3
+	if first == 0 {
+		first = 3
+	}
+	b.WriteString(whole[:first])
+	for i := first; i < len(whole); i += 3 {
+		b.WriteString(separator)
+		b.WriteString(whole[i : i+3])
+	}
+	return b.String()
+}
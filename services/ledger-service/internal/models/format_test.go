@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyFormatDefaults(t *testing.T) {
+	assert.Equal(t, "$10.50", usdAmount(10.5).Format("en-US"))
+	assert.Equal(t, "-$3.00", usdAmount(-3).Format("en-US"))
+	assert.Equal(t, "10,50 €", NewMoney(1050, "EUR", 2).Format("de-DE"))
+	assert.Equal(t, "¥500", NewMoney(500, "JPY", 0).Format("ja-JP"))
+	assert.Equal(t, "XYZ1.00", NewMoney(100, "XYZ", 2).Format("en-US"))
+}
+
+func TestMoneyFormatLocales(t *testing.T) {
+	assert.Equal(t, "$1,234.50", usdAmount(1234.5).Format("en-US"))
+	assert.Equal(t, "1.234,50 €", NewMoney(123450, "EUR", 2).Format("de-DE"))
+	assert.Equal(t, "-1.234,50 €", NewMoney(-123450, "EUR", 2).Format("de_AT"))
+	assert.Equal(t, "1\u202f234\u202f567,50 €", NewMoney(123456750, "EUR", 2).Format("fr-FR"))
+	assert.Equal(t, "€ -1.234,50", NewMoney(-123450, "EUR", 2).Format("nl-NL"))
+	assert.Equal(t, "¥1,234,567", NewMoney(1234567, "JPY", 0).Format("ja-JP"))
+	assert.Equal(t, "$0.05", usdAmount(0.05).Format("en-US"))
+
+	assert.Equal(t, "USD 1234.50", usdAmount(1234.5).Format("xx-YY"))
+	assert.Equal(t, "EUR -1234.50", NewMoney(-123450, "EUR", 2).Format(""))
+}
+
+func TestMoneyFormatDisplayOverride(t *testing.T) {
+	t.Cleanup(ClearDisplayOverrides)
+
+	RegisterDisplayOverride("USD", "", DisplayOverride{Symbol: "US$"})
+	RegisterDisplayOverride("USD", "fr-CA", DisplayOverride{Symbol: "$ US", Position: PositionAfter, Spacing: SpacingSpace})
+
+	assert.Equal(t, "US$10.50", usdAmount(10.5).Format("en-GB"))
+	assert.Equal(t, "10,50 $ US", usdAmount(10.5).Format("fr-CA"))
+	RegisterDisplayOverride("EUR", "", DisplayOverride{Symbol: "EUR"})
+	assert.Equal(t, "10,50 EUR", NewMoney(1050, "EUR", 2).Format("de-DE"), "unset fields keep the locale default")
+	assert.Equal(t, "£2.00", NewMoney(200, "GBP", 2).Format("en-GB"), "other currencies keep their defaults")
+	assert.Equal(t, "USD 10.50", usdAmount(10.5).Format("xx-YY"), "unknown locales ignore locale-independent overrides")
+
+	RegisterDisplayOverride("USD", "en-US", DisplayOverride{Negative: NegativeParentheses})
+	assert.Equal(t, "($1,234.50)", usdAmount(-1234.5).Format("en-US"))
+
+	ClearDisplayOverrides()
+	assert.Equal(t, "$10.50", usdAmount(10.5).Format("en-GB"))
+}
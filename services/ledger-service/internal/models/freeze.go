@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAccountFrozen is returned when a withdrawal or hold targets a frozen account
+var ErrAccountFrozen = errors.New("account frozen")
+
+// NewAccountFreeze creates an event freezing an account. While frozen the account accepts
+// credits but rejects debits, negative adjustments and holds.
+func NewAccountFreeze(accountID, correlationID, reason string) *LedgerEvent {
+	return newControlEvent(AccountFreeze, accountID, correlationID).WithMetadata("reason", reason)
+}
+
+// NewAccountUnfreeze creates an event lifting a freeze on an account
+func NewAccountUnfreeze(accountID, correlationID, reason string) *LedgerEvent {
+	return newControlEvent(AccountUnfreeze, accountID, correlationID).WithMetadata("reason", reason)
+}
+
+// newControlEvent creates an event that carries no amount
+func newControlEvent(eventType EventType, accountID, correlationID string) *LedgerEvent {
+	return NewLedgerEvent(eventType, Money{}, accountID, correlationID)
+}
+
+// IsFrozen reports whether an account stream, in version order, ends in a frozen state
+func IsFrozen(stream []*LedgerEvent) bool {
+	frozen := false
+	for _, event := range stream {
+		switch event.Type {
+		case AccountFreeze:
+			frozen = true
+		case AccountUnfreeze:
+			frozen = false
+		}
+	}
+	return frozen
+}
+
+// CheckFreeze rejects withdrawals and holds against an account whose stream leaves it frozen:
+// debits, negative adjustments, and reversals of credits and positive adjustments in stream
+func CheckFreeze(stream []*LedgerEvent, event *LedgerEvent) error {
+	if !event.Withdraws(ReversibleTypes(stream)) && !event.IsHold() {
+		return nil
+	}
+	if IsFrozen(stream) {
+		return fmt.Errorf("%w: %s rejected on account %s", ErrAccountFrozen, event.Type, event.AccountID)
+	}
+	return nil
+}
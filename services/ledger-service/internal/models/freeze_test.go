@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFreeze(t *testing.T) {
+	usd := NewMoney(1000, "USD", 2)
+	freeze := NewAccountFreeze("acc_1", "corr_compliance", "sanctions review")
+	require.NoError(t, freeze.Validate())
+
+	stream := []*LedgerEvent{
+		NewLedgerEvent(Credit, usd, "acc_1", "corr_1"),
+		freeze.WithVersion(2),
+	}
+	assert.True(t, IsFrozen(stream))
+	assert.ErrorIs(t, CheckFreeze(stream, NewLedgerEvent(Debit, usd, "acc_1", "corr_2")), ErrAccountFrozen)
+	assert.ErrorIs(t, CheckFreeze(stream, NewLedgerEvent(Hold, usd, "acc_1", "corr_2")), ErrAccountFrozen)
+	assert.NoError(t, CheckFreeze(stream, NewLedgerEvent(Credit, usd, "acc_1", "corr_2")))
+	assert.ErrorIs(t, CheckFreeze(stream, NewLedgerEvent(Adjustment, usd.negate(), "acc_1", "corr_2")), ErrAccountFrozen)
+	assert.NoError(t, CheckFreeze(stream, NewLedgerEvent(Adjustment, usd, "acc_1", "corr_2")))
+
+	credit, debit := stream[0], NewLedgerEvent(Debit, usd, "acc_1", "corr_0")
+	assert.ErrorIs(t, CheckFreeze(stream, NewReversal(credit, "corr_2")), ErrAccountFrozen, "reversing a credit takes money out")
+	assert.NoError(t, CheckFreeze(stream, NewReversal(debit, "corr_2")), "reversing a debit returns money")
+	unrecorded := NewReversal(credit, "corr_2")
+	delete(unrecorded.Metadata, MetaReversedType)
+	assert.ErrorIs(t, CheckFreeze(stream, unrecorded), ErrAccountFrozen, "the original in the stream decides")
+
+	stream = append(stream, NewAccountUnfreeze("acc_1", "corr_compliance", "cleared").WithVersion(3))
+	assert.False(t, IsFrozen(stream))
+	assert.NoError(t, CheckFreeze(stream, NewLedgerEvent(Debit, usd, "acc_1", "corr_3")))
+}
+
+func TestControlEventRejectsAmount(t *testing.T) {
+	freeze := NewAccountFreeze("acc_1", "corr_1", "fraud")
+	freeze.Amount = NewMoney(500, "USD", 2)
+	assert.Error(t, freeze.Validate())
+}
@@ -0,0 +1,49 @@
+package models
+
+import "fmt"
+
+// Gap is a problem in an account stream's version sequence: the versions From to To inclusive
+// are missing, or, when Duplicate is set, version From (equal to To) is held by more than one event
+type Gap struct {
+	AccountID string `json:"accountId"`
+	From      int64  `json:"from"`
+	To        int64  `json:"to"`
+	Duplicate bool   `json:"duplicate,omitempty"`
+}
+
+// String describes the gap for alerts
+func (g Gap) String() string {
+	switch {
+	case g.Duplicate:
+		return fmt.Sprintf("%s: duplicate version %d", g.AccountID, g.From)
+	case g.From == g.To:
+		return fmt.Sprintf("%s: missing version %d", g.AccountID, g.From)
+	}
+	return fmt.Sprintf("%s: missing versions %d-%d", g.AccountID, g.From, g.To)
+}
+
+// DetectGaps checks the versions of one account's events, sorted by version, and returns the
+// ranges missing between the first and last event and each version held by more than one event,
+// in version order. A replay starting mid-stream is not a gap; compare the first version with
+// the expected one separately. It fails if the events span accounts or are out of order.
+func DetectGaps(events []*LedgerEvent) ([]Gap, error) {
+	gaps := []Gap{}
+	for i := 1; i < len(events); i++ {
+		prev, event := events[i-1], events[i]
+		if event.AccountID != prev.AccountID {
+			return nil, fmt.Errorf("events span accounts %s and %s", prev.AccountID, event.AccountID)
+		}
+		switch {
+		case event.Version < prev.Version:
+			return nil, fmt.Errorf("event %s at version %d follows version %d; events must be sorted by version",
+				event.ID, event.Version, prev.Version)
+		case event.Version == prev.Version:
+			if len(gaps) == 0 || !gaps[len(gaps)-1].Duplicate || gaps[len(gaps)-1].From != event.Version {
+				gaps = append(gaps, Gap{AccountID: event.AccountID, From: event.Version, To: event.Version, Duplicate: true})
+			}
+		case event.Version > prev.Version+1:
+			gaps = append(gaps, Gap{AccountID: event.AccountID, From: prev.Version + 1, To: event.Version - 1})
+		}
+	}
+	return gaps, nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versioned(accountID string, versions ...int64) []*LedgerEvent {
+	events := make([]*LedgerEvent, len(versions))
+	for i, version := range versions {
+		events[i] = NewLedgerEvent(Credit, usdAmount(1), accountID, "corr_1").WithVersion(version)
+	}
+	return events
+}
+
+func TestDetectGapsReportsMissingRangesAndDuplicates(t *testing.T) {
+	gaps, err := DetectGaps(versioned("acc_1", 1, 2, 5, 5, 5, 6, 8))
+	require.NoError(t, err)
+	assert.Equal(t, []Gap{
+		{AccountID: "acc_1", From: 3, To: 4},
+		{AccountID: "acc_1", From: 5, To: 5, Duplicate: true},
+		{AccountID: "acc_1", From: 7, To: 7},
+	}, gaps)
+	assert.Equal(t, "acc_1: missing versions 3-4", gaps[0].String())
+	assert.Equal(t, "acc_1: duplicate version 5", gaps[1].String())
+	assert.Equal(t, "acc_1: missing version 7", gaps[2].String())
+
+	gaps, err = DetectGaps(versioned("acc_1", 4, 5, 6))
+	require.NoError(t, err)
+	assert.Empty(t, gaps)
+}
+
+func TestDetectGapsRejectsMixedOrUnsortedInput(t *testing.T) {
+	_, err := DetectGaps(append(versioned("acc_1", 1), versioned("acc_2", 2)...))
+	assert.Error(t, err)
+
+	_, err = DetectGaps(versioned("acc_1", 1, 3, 2))
+	assert.Error(t, err)
+}
@@ -0,0 +1,25 @@
+package models
+
+import "fmt"
+
+// WithHoldID links a release, or a debit capturing the held funds, to the hold it draws on.
+// A hold may be released or captured in several parts, as long as they do not add up to more
+// than it holds.
+func (e *LedgerEvent) WithHoldID(holdID string) *LedgerEvent {
+	e.HoldID = &holdID
+	return e
+}
+
+// checkHoldLink only lets releases and debits name a hold, and requires the name to be set
+func (e *LedgerEvent) checkHoldLink() error {
+	if e.HoldID == nil {
+		return nil
+	}
+	if !e.IsRelease() && !e.IsDebit() {
+		return fmt.Errorf("%s event must not draw on a hold", e.Type)
+	}
+	if *e.HoldID == "" {
+		return fmt.Errorf("hold ID must not be empty")
+	}
+	return nil
+}
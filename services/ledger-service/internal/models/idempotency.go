@@ -0,0 +1,52 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxIdempotencyKeyLength bounds idempotency keys so stores can index them
+const maxIdempotencyKeyLength = 255
+
+// WithIdempotencyKey sets the key identifying the creation attempt the event belongs to. Retries
+// of the same attempt reuse the key, so their events share a ContentHash.
+func (e *LedgerEvent) WithIdempotencyKey(key string) *LedgerEvent {
+	e.IdempotencyKey = key
+	return e
+}
+
+// checkIdempotencyKey accepts an absent key and rejects a blank or overlong one
+func checkIdempotencyKey(key string) error {
+	if key == "" {
+		return nil
+	}
+	if strings.TrimSpace(key) == "" {
+		return fmt.Errorf("idempotency key must not be blank")
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key is %d bytes, limit is %d", len(key), maxIdempotencyKeyLength)
+	}
+	return nil
+}
+
+// ContentHash returns the hex SHA-256 of the event's intent: its type, amount, account and
+// idempotency key. Unlike ComputeHash it ignores the ID, timestamp and everything else that
+// differs between retries, so stores can use it as a uniqueness constraint to reject duplicate
+// creation attempts. Amounts are compared by value, so 10.5 and 10.50 hash alike. Events
+// without an idempotency key have no content hash and "" is returned.
+func (e *LedgerEvent) ContentHash() string {
+	if e.IdempotencyKey == "" {
+		return ""
+	}
+	intent, err := json.Marshal([]string{
+		string(e.Type), e.Amount.decimal(), e.Amount.Currency, e.AccountID, e.IdempotencyKey,
+	})
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(intent)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentHashMatchesRetries(t *testing.T) {
+	first := NewLedgerEvent(Debit, NewMoney(1050, "USD", 2), "acc_1", "corr_1").WithIdempotencyKey("pay_1:attempt")
+	retry := NewLedgerEvent(Debit, NewMoney(105, "USD", 1), "acc_1", "corr_2").WithIdempotencyKey("pay_1:attempt")
+	retry.Timestamp = first.Timestamp.Add(time.Second)
+
+	require.NotEqual(t, first.ID, retry.ID)
+	assert.NotEmpty(t, first.ContentHash())
+	assert.Equal(t, first.ContentHash(), retry.ContentHash())
+
+	other := NewLedgerEvent(Debit, NewMoney(1050, "USD", 2), "acc_1", "corr_1").WithIdempotencyKey("pay_2:attempt")
+	assert.NotEqual(t, first.ContentHash(), other.ContentHash())
+	credit := NewLedgerEvent(Credit, NewMoney(1050, "USD", 2), "acc_1", "corr_1").WithIdempotencyKey("pay_1:attempt")
+	assert.NotEqual(t, first.ContentHash(), credit.ContentHash())
+
+	assert.Empty(t, NewLedgerEvent(Debit, NewMoney(1050, "USD", 2), "acc_1", "corr_1").ContentHash())
+}
+
+func TestValidateIdempotencyKey(t *testing.T) {
+	event := NewLedgerEvent(Debit, NewMoney(1050, "USD", 2), "acc_1", "corr_1")
+	assert.NoError(t, event.Validate(), "the key is optional")
+	assert.NoError(t, event.WithIdempotencyKey("pay_1").Validate())
+	assert.Error(t, event.WithIdempotencyKey("  ").Validate())
+	assert.Error(t, event.WithIdempotencyKey(strings.Repeat("k", 256)).Validate())
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrMalformedAmount is returned when an imported amount is not a number in the expected notation
+var ErrMalformedAmount = errors.New("malformed amount")
+
+// ImportMode selects how ImportAmount tells the decimal separator from digit grouping
+type ImportMode int
+
+const (
+	// DotDecimal reads "1,234.56": a dot separates decimals and commas group digits
+	DotDecimal ImportMode = iota
+	// CommaDecimal reads "1.234,56": a comma separates decimals and dots group digits
+	CommaDecimal
+	// InferSeparator decides per value, for feeds that mix both notations
+	InferSeparator
+)
+
+// ImportedAmount is the result of importing one amount
+type ImportedAmount struct {
+	// Money is the parsed amount; it is the zero value when Ambiguous is set
+	Money Money
+	// Ambiguous is set when InferSeparator cannot tell the notation apart, e.g. "1.234" is
+	// either 1234 or 1.234; such values need manual review
+	Ambiguous bool
+	// Candidates holds the readings of an ambiguous value that fit the currency, for the reviewer
+	Candidates []Money
+	// Reason explains why the value is ambiguous
+	Reason string
+}
+
+// ImportAmount parses raw, an amount in currencyCode from an external feed, in mode's notation.
+// Spaces and apostrophes are always digit grouping, and groups after the first must have three
+// digits. Amounts with more decimals than the currency allows are rejected rather than rounded.
+//
+// In InferSeparator mode the separator appearing last is the decimal one when both appear, and a
+// separator appearing more than once is grouping. A single separator is decimal unless it is
+// followed by exactly three digits and preceded by one to three digits other than a lone zero,
+// which reads as well either way: that value is returned as Ambiguous instead of guessed.
+func ImportAmount(raw, currencyCode string, mode ImportMode) (ImportedAmount, error) {
+	zero, err := ZeroMoney(currencyCode)
+	if err != nil {
+		return ImportedAmount{}, err
+	}
+
+	value := strings.NewReplacer(" ", "", "'", "", "\u00a0", "").Replace(strings.TrimSpace(raw))
+	decimal, ambiguous := decimalSeparator(value, mode)
+	if !ambiguous {
+		money, err := parseImported(value, decimal, zero)
+		return ImportedAmount{Money: money}, err
+	}
+
+	imported := ImportedAmount{
+		Ambiguous: true,
+		Reason:    fmt.Sprintf("%q reads as a decimal or a grouping separator", decimal),
+	}
+	for _, separator := range []byte{decimal, 0} {
+		if candidate, err := parseImported(value, separator, zero); err == nil {
+			imported.Candidates = append(imported.Candidates, candidate)
+		}
+	}
+	return imported, nil
+}
+
+// decimalSeparator returns the decimal separator of value under mode, 0 if it has none, and
+// whether InferSeparator could not decide
+func decimalSeparator(value string, mode ImportMode) (byte, bool) {
+	switch mode {
+	case DotDecimal:
+		return '.', false
+	case CommaDecimal:
+		return ',', false
+	}
+
+	last := strings.LastIndexAny(value, ".,")
+	if last < 0 {
+		return 0, false
+	}
+	separator := value[last]
+	if strings.Count(value, ".") > 0 && strings.Count(value, ",") > 0 {
+		return separator, false
+	}
+	if strings.Count(value, string(separator)) > 1 {
+		return 0, false
+	}
+
+	before := strings.TrimLeft(value[:last], "+-")
+	after := value[last+1:]
+	if len(after) != 3 || len(before) == 0 || len(before) > 3 || before == "0" {
+		return separator, false
+	}
+	return separator, true
+}
+
+// parseImported parses value with decimal as its decimal separator, 0 for none, and any other
+// separator as digit grouping, at the currency's precision
+func parseImported(value string, decimal byte, zero Money) (Money, error) {
+	malformed := func(reason string) error {
+		return fmt.Errorf("%w: %q %s", ErrMalformedAmount, value, reason)
+	}
+
+	digits := strings.TrimLeft(value, "+-")
+	if len(value)-len(digits) > 1 {
+		return Money{}, malformed("has more than one sign")
+	}
+	negative := strings.HasPrefix(value, "-")
+
+	whole, fraction := digits, ""
+	if decimal != 0 {
+		if i := strings.LastIndexByte(digits, decimal); i >= 0 {
+			whole, fraction = digits[:i], digits[i+1:]
+		}
+	}
+	whole, err := ungroup(whole, decimal)
+	if err != nil {
+		return Money{}, malformed(err.Error())
+	}
+	if whole == "" && fraction == "" {
+		return Money{}, malformed("has no digits")
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, malformed("is not a number")
+	}
+	if len(fraction) > zero.Precision {
+		return Money{}, fmt.Errorf("%w: %q has %d decimals, %s allows %d", ErrPrecisionLoss, value, len(fraction), zero.Currency, zero.Precision)
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", zero.Precision-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrAmountOverflow, value)
+	}
+	money := NewMoney(minor, zero.Currency, zero.Precision)
+	if negative {
+		money = money.negate()
+	}
+	return money, nil
+}
+
+// ungroup removes the grouping separators from the whole part of an amount, checking that every
+// group after the first has three digits. The grouping separator is the one that is not decimal.
+func ungroup(whole string, decimal byte) (string, error) {
+	grouping := strings.IndexAny(whole, ".,")
+	switch decimal {
+	case '.':
+		grouping = strings.IndexByte(whole, ',')
+	case ',':
+		grouping = strings.IndexByte(whole, '.')
+	}
+	if grouping < 0 {
+		return whole, nil
+	}
+
+	groups := strings.Split(whole, whole[grouping:grouping+1])
+	if len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return "", fmt.Errorf("has a leading group of %d digits", len(groups[0]))
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return "", fmt.Errorf("has a digit group of %d digits", len(group))
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// isDigits reports whether s consists of ASCII digits only
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportAmountInfersClearSeparators(t *testing.T) {
+	cases := []struct {
+		raw      string
+		currency string
+		want     Money
+	}{
+		{"1,234.56", "USD", NewMoney(123456, "USD", 2)},
+		{"1.234,56", "EUR", NewMoney(123456, "EUR", 2)},
+		{"1234.5", "USD", NewMoney(123450, "USD", 2)},
+		{"12,5", "EUR", NewMoney(1250, "EUR", 2)},
+		{"1.234.567", "EUR", NewMoney(123456700, "EUR", 2)},
+		{"1 234 567,89", "EUR", NewMoney(123456789, "EUR", 2)},
+		{"1'234.50", "CHF", NewMoney(123450, "CHF", 2)},
+		{"0.125", "KWD", NewMoney(125, "KWD", 3)},
+		{"1234,567", "KWD", NewMoney(1234567, "KWD", 3)},
+		{"-42", "USD", NewMoney(-4200, "USD", 2)},
+	}
+	for _, tc := range cases {
+		imported, err := ImportAmount(tc.raw, tc.currency, InferSeparator)
+		require.NoError(t, err, tc.raw)
+		assert.False(t, imported.Ambiguous, tc.raw)
+		assert.Equal(t, tc.want, imported.Money, tc.raw)
+	}
+}
+
+func TestImportAmountFlagsAmbiguousSeparator(t *testing.T) {
+	imported, err := ImportAmount("1.234", "KWD", InferSeparator)
+	require.NoError(t, err)
+	assert.True(t, imported.Ambiguous)
+	assert.Equal(t, Money{}, imported.Money)
+	assert.Equal(t, []Money{NewMoney(1234, "KWD", 3), NewMoney(1234000, "KWD", 3)}, imported.Candidates)
+	assert.NotEmpty(t, imported.Reason)
+
+	imported, err = ImportAmount("1,234", "USD", InferSeparator)
+	require.NoError(t, err)
+	assert.True(t, imported.Ambiguous)
+	assert.Equal(t, []Money{NewMoney(123400, "USD", 2)}, imported.Candidates, "1.234 USD is not a valid reading")
+}
+
+func TestImportAmountFixedModes(t *testing.T) {
+	imported, err := ImportAmount("1.234", "EUR", CommaDecimal)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(123400, "EUR", 2), imported.Money)
+
+	imported, err = ImportAmount("1,234", "USD", DotDecimal)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(123400, "USD", 2), imported.Money)
+
+	_, err = ImportAmount("1.234.56", "USD", DotDecimal)
+	assert.ErrorIs(t, err, ErrMalformedAmount)
+}
+
+func TestImportAmountRejectsMalformedValues(t *testing.T) {
+	for _, raw := range []string{"", "abc", "12,34.5", "1234,567.00", "--5", "1.2.3,4"} {
+		_, err := ImportAmount(raw, "USD", InferSeparator)
+		assert.ErrorIs(t, err, ErrMalformedAmount, raw)
+	}
+	_, err := ImportAmount("1.255", "USD", DotDecimal)
+	assert.ErrorIs(t, err, ErrPrecisionLoss)
+	_, err = ImportAmount("99999999999999999999", "USD", InferSeparator)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = ImportAmount("1", "XXX", InferSeparator)
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
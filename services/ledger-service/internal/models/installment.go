@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Metadata keys recorded on installment debits
+const (
+	MetaInstallmentNumber = "installmentNumber"
+	MetaInstallmentCount  = "installmentCount"
+)
+
+// ErrInstallmentMismatch is returned when a plan's installments do not sum to the authorized amount
+var ErrInstallmentMismatch = errors.New("installments do not sum to the authorized amount")
+
+// InstallmentPlan builds the scheduled debits that pay off an authorization in installments
+type InstallmentPlan struct {
+	authorization *LedgerEvent
+	correlationID string
+	dueDates      []time.Time
+	weights       []int64
+}
+
+// NewInstallmentPlan starts a plan paying off authorization, whose debits share correlationID
+func NewInstallmentPlan(authorization *LedgerEvent, correlationID string) *InstallmentPlan {
+	return &InstallmentPlan{authorization: authorization, correlationID: correlationID}
+}
+
+// Installment adds an installment due at dueAt carrying weight shares of the total
+func (p *InstallmentPlan) Installment(dueAt time.Time, weight int) *InstallmentPlan {
+	p.dueDates = append(p.dueDates, dueAt.UTC())
+	p.weights = append(p.weights, int64(weight))
+	return p
+}
+
+// Monthly adds count equal installments, the first due at first and each following one a
+// calendar month after the previous
+func (p *InstallmentPlan) Monthly(count int, first time.Time) *InstallmentPlan {
+	for i := 0; i < count; i++ {
+		p.Installment(first.AddDate(0, i, 0), 1)
+	}
+	return p
+}
+
+// Build returns one debit per installment on the authorization's account, in the order added.
+// Amounts are allocated in minor units with the same largest-remainder method as SplitCredit,
+// so they sum exactly to the authorized amount. Each debit is scheduled for its due date: it
+// takes effect then and is only valid from then on, so balances count it once it falls due. The
+// debits reference the authorization and record their position in the plan.
+func (p *InstallmentPlan) Build() ([]*LedgerEvent, error) {
+	auth := p.authorization
+	if auth == nil || auth.IsControl() {
+		return nil, fmt.Errorf("installment plan needs a monetary authorization")
+	}
+	if auth.Amount.Sign() <= 0 {
+		return nil, fmt.Errorf("authorized amount must be positive")
+	}
+	if len(p.dueDates) == 0 {
+		return nil, fmt.Errorf("installment plan has no installments")
+	}
+	for i, weight := range p.weights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("installment %d weight must be positive", i+1)
+		}
+		if i > 0 && !p.dueDates[i].After(p.dueDates[i-1]) {
+			return nil, fmt.Errorf("installment %d is not due after installment %d", i+1, i)
+		}
+	}
+
+	shares := allocate(auth.Amount.MinorUnits, p.weights)
+	installments := make([]*LedgerEvent, 0, len(shares))
+	for i, share := range shares {
+		if share == 0 {
+			return nil, fmt.Errorf("installment %d would be zero; %s cannot be split %d ways",
+				i+1, auth.Amount.decimal(), len(shares))
+		}
+		amount := auth.Amount
+		amount.MinorUnits = share
+		dueAt := p.dueDates[i]
+		installment := NewLedgerEvent(Debit, amount, auth.AccountID, p.correlationID).
+			WithReferenceID(auth.ID).
+			WithEffectiveAt(dueAt).
+			WithValidity(&dueAt, nil).
+			WithMetadata(MetaInstallmentNumber, i+1).
+			WithMetadata(MetaInstallmentCount, len(shares))
+		installment.PaymentID = auth.PaymentID
+		installment.TenantID = auth.TenantID
+		installments = append(installments, installment)
+	}
+
+	if err := ValidateInstallments(auth, installments); err != nil {
+		return nil, err
+	}
+	return installments, nil
+}
+
+// ValidateInstallments checks that the installments are debits on the authorization's account
+// that reference it and sum exactly to the authorized amount
+func ValidateInstallments(authorization *LedgerEvent, installments []*LedgerEvent) error {
+	total := Money{Currency: authorization.Amount.Currency, Precision: authorization.Amount.Precision}
+	for _, installment := range installments {
+		if !installment.IsDebit() || installment.AccountID != authorization.AccountID {
+			return fmt.Errorf("installment %s is not a debit on account %s", installment.ID, authorization.AccountID)
+		}
+		if installment.ReferenceID == nil || *installment.ReferenceID != authorization.ID {
+			return fmt.Errorf("installment %s does not reference authorization %s", installment.ID, authorization.ID)
+		}
+		var err error
+		if total, err = total.Add(installment.Amount); err != nil {
+			return fmt.Errorf("installment %s: %w", installment.ID, err)
+		}
+	}
+	if !total.Equal(authorization.Amount) {
+		return fmt.Errorf("%w: %s of %s", ErrInstallmentMismatch, total.decimal(), authorization.Amount.decimal())
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallmentPlanSumsToAuthorization(t *testing.T) {
+	auth := NewLedgerEvent(Hold, usdAmount(100), "acc_buyer", "corr_auth").WithPaymentID("pay_1")
+	first := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	installments, err := NewInstallmentPlan(auth, "corr_bnpl").Monthly(3, first).Build()
+	require.NoError(t, err)
+	require.Len(t, installments, 3)
+
+	var total int64
+	for i, installment := range installments {
+		total += installment.Amount.MinorUnits
+		assert.Equal(t, Debit, installment.Type)
+		assert.Equal(t, "corr_bnpl", installment.CorrelationID)
+		assert.Equal(t, auth.ID, *installment.ReferenceID)
+		assert.Equal(t, "pay_1", *installment.PaymentID)
+		assert.Equal(t, i+1, installment.Metadata[MetaInstallmentNumber])
+		assert.NoError(t, installment.Validate())
+	}
+	assert.Equal(t, auth.Amount.MinorUnits, total)
+	assert.Equal(t, []int64{3334, 3333, 3333},
+		[]int64{installments[0].Amount.MinorUnits, installments[1].Amount.MinorUnits, installments[2].Amount.MinorUnits})
+
+	assert.Equal(t, first, installments[0].EffectiveTime())
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), installments[1].EffectiveTime())
+	assert.False(t, installments[2].IsValidAt(first), "an installment only counts once due")
+
+	installments[1].Amount.MinorUnits--
+	assert.ErrorIs(t, ValidateInstallments(auth, installments), ErrInstallmentMismatch)
+}
+
+func TestInstallmentPlanRejections(t *testing.T) {
+	auth := NewLedgerEvent(Hold, usdAmount(0.02), "acc_buyer", "corr_auth")
+	due := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := NewInstallmentPlan(auth, "corr_bnpl").Build()
+	assert.Error(t, err)
+	_, err = NewInstallmentPlan(auth, "corr_bnpl").Monthly(3, due).Build()
+	assert.Error(t, err, "two cents cannot be paid in three installments")
+	_, err = NewInstallmentPlan(auth, "corr_bnpl").Installment(due, 1).Installment(due, 1).Build()
+	assert.Error(t, err, "due dates must increase")
+}
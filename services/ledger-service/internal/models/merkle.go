@@ -0,0 +1,134 @@
+package models
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyBatch is returned when a batch operation receives no events
+var ErrEmptyBatch = errors.New("empty batch")
+
+// Domain prefixes keep leaf and node hashes apart, so an inner node cannot be passed off as a leaf
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// MerkleRoot builds a binary SHA-256 Merkle tree over the events' leaves, in slice order, and
+// returns its root. Each parent is SHA-256(0x01 || left || right); when a level has an odd
+// number of nodes the last node is paired with itself.
+func MerkleRoot(events []*LedgerEvent) ([32]byte, error) {
+	levels, err := eventMerkleLevels(events)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return levels[len(levels)-1][0], nil
+}
+
+// MerkleLeaf returns the event's leaf in MerkleRoot trees: SHA-256(0x00 || chain hash), where
+// the chain hash is the one ComputeHash encodes. It covers PreviousHash, so a leaf also binds
+// the event's place in its chain.
+func MerkleLeaf(event *LedgerEvent) ([32]byte, error) {
+	chained, err := event.chainHash()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(append([]byte{merkleLeafPrefix}, chained...)), nil
+}
+
+// MerkleProof returns the inclusion proof for events[index] in the tree MerkleRoot builds: the
+// sibling of each node on the path from the leaf up to, but excluding, the root
+func MerkleProof(events []*LedgerEvent, index int) ([][32]byte, error) {
+	if index < 0 || index >= len(events) {
+		return nil, fmt.Errorf("index %d out of range for %d events", index, len(events))
+	}
+	levels, err := eventMerkleLevels(events)
+	if err != nil {
+		return nil, err
+	}
+	return merkleSiblings(levels, index), nil
+}
+
+// VerifyMerkleProof reports whether proof leads from leaf at index to root
+func VerifyMerkleProof(leaf, root [32]byte, proof [][32]byte, index int) bool {
+	if index < 0 {
+		return false
+	}
+	node, position := merklePath(leaf, index, proof)
+	return position == 0 && node == root
+}
+
+// eventMerkleLevels returns every level of the tree over the events' leaves
+func eventMerkleLevels(events []*LedgerEvent) ([][][32]byte, error) {
+	if len(events) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	leaves := make([][32]byte, len(events))
+	for i, event := range events {
+		leaf, err := MerkleLeaf(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash event %d: %w", i, err)
+		}
+		leaves[i] = leaf
+	}
+	return merkleLevels(leaves), nil
+}
+
+// merkleLevels returns every level of the tree over leaves, from the leaves to the root
+func merkleLevels(leaves [][32]byte) [][][32]byte {
+	levels := [][][32]byte{leaves}
+	for level := leaves; len(level) > 1; {
+		if len(level)%2 == 1 {
+			level = append(level[:len(level):len(level)], level[len(level)-1])
+		}
+		next := make([][32]byte, len(level)/2)
+		for i := range next {
+			next[i] = merkleNode(level[2*i], level[2*i+1])
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// merkleSiblings returns the siblings on the path from the leaf at index to the root; a node
+// paired with itself is its own sibling
+func merkleSiblings(levels [][][32]byte, index int) [][32]byte {
+	siblings := make([][32]byte, 0, len(levels)-1)
+	position := index
+	for _, level := range levels[:len(levels)-1] {
+		sibling := position ^ 1
+		if sibling == len(level) {
+			sibling = position
+		}
+		siblings = append(siblings, level[sibling])
+		position /= 2
+	}
+	return siblings
+}
+
+// merklePath hashes leaf up the tree with its siblings and returns the resulting node and the
+// position left over, which is 0 when index was within the tree
+func merklePath(leaf [32]byte, index int, siblings [][32]byte) ([32]byte, int) {
+	node, position := leaf, index
+	for _, sibling := range siblings {
+		if position%2 == 0 {
+			node = merkleNode(node, sibling)
+		} else {
+			node = merkleNode(sibling, node)
+		}
+		position /= 2
+	}
+	return node, position
+}
+
+// merkleNode returns SHA-256(0x01 || left || right)
+func merkleNode(left, right [32]byte) [32]byte {
+	var buf [65]byte
+	buf[0] = merkleNodePrefix
+	copy(buf[1:33], left[:])
+	copy(buf[33:], right[:])
+	return sha256.Sum256(buf[:])
+}
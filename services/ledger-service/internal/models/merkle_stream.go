@@ -0,0 +1,92 @@
+package models
+
+import "fmt"
+
+// StreamingMerkle computes the same root as MerkleRoot over leaves added one at a time,
+// holding only the O(log n) roots of the complete subtrees built so far
+type StreamingMerkle struct {
+	// pending[h] is the root of a complete subtree of 2^h leaves waiting for its right sibling
+	pending []*[32]byte
+	count   int
+}
+
+// NewStreamingMerkle creates an empty streaming Merkle builder
+func NewStreamingMerkle() *StreamingMerkle {
+	return &StreamingMerkle{}
+}
+
+// Add appends a 32-byte leaf hash, as MerkleLeaf returns it
+func (m *StreamingMerkle) Add(hash []byte) error {
+	if len(hash) != 32 {
+		return fmt.Errorf("merkle leaf must be 32 bytes, got %d", len(hash))
+	}
+	var node [32]byte
+	copy(node[:], hash)
+
+	height := 0
+	for ; height < len(m.pending) && m.pending[height] != nil; height++ {
+		node = merkleNode(*m.pending[height], node)
+		m.pending[height] = nil
+	}
+	if height == len(m.pending) {
+		m.pending = append(m.pending, nil)
+	}
+	m.pending[height] = &node
+	m.count++
+	return nil
+}
+
+// AddEvent appends the event's MerkleLeaf
+func (m *StreamingMerkle) AddEvent(event *LedgerEvent) error {
+	leaf, err := MerkleLeaf(event)
+	if err != nil {
+		return fmt.Errorf("failed to hash event %s: %w", event.ID, err)
+	}
+	return m.Add(leaf[:])
+}
+
+// Count returns the number of leaves added
+func (m *StreamingMerkle) Count() int {
+	return m.count
+}
+
+// Root returns the root of the tree over the leaves added so far, or nil if there are none.
+// The last node of a level with an odd number of nodes is paired with itself, as in MerkleRoot;
+// more leaves can be added after Root is called.
+func (m *StreamingMerkle) Root() []byte {
+	var carry *[32]byte
+	for height, node := range m.pending {
+		switch {
+		case carry == nil && node == nil:
+			continue
+		case carry == nil:
+			carry = node
+		case node != nil:
+			paired := merkleNode(*node, *carry)
+			carry = &paired
+			continue
+		}
+		// carry is the unpaired last node of this level
+		if !m.pendingAbove(height) {
+			root := *carry
+			return root[:]
+		}
+		paired := merkleNode(*carry, *carry)
+		carry = &paired
+	}
+	if carry == nil {
+		return nil
+	}
+	root := *carry
+	return root[:]
+}
+
+// pendingAbove reports whether any subtree is pending above height
+func (m *StreamingMerkle) pendingAbove(height int) bool {
+	for _, node := range m.pending[height+1:] {
+		if node != nil {
+			return true
+		}
+	}
+	return false
+}
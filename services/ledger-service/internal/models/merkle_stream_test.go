@@ -0,0 +1,56 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func syntheticLeaves(n int) [][32]byte {
+	leaves := make([][32]byte, n)
+	for i := range leaves {
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		leaves[i] = sha256.Sum256(buf[:])
+	}
+	return leaves
+}
+
+func streamRoot(t *testing.T, leaves [][32]byte) []byte {
+	t.Helper()
+	stream := NewStreamingMerkle()
+	for _, leaf := range leaves {
+		require.NoError(t, stream.Add(leaf[:]))
+	}
+	return stream.Root()
+}
+
+func TestStreamingMerkleMatchesBatch(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 6, 7, 8, 9, 31, 33, 100_000} {
+		leaves := syntheticLeaves(n)
+		levels := merkleLevels(leaves)
+		batch := levels[len(levels)-1][0]
+		assert.Equal(t, batch[:], streamRoot(t, leaves), "%d leaves", n)
+	}
+}
+
+func TestStreamingMerkleOverEvents(t *testing.T) {
+	events := chainedEvents(t, 11)
+	stream := NewStreamingMerkle()
+	for _, event := range events {
+		require.NoError(t, stream.AddEvent(event))
+	}
+	root, err := MerkleRoot(events)
+	require.NoError(t, err)
+	assert.Equal(t, root[:], stream.Root())
+	assert.Equal(t, 11, stream.Count())
+}
+
+func TestStreamingMerkleEmptyAndInvalid(t *testing.T) {
+	stream := NewStreamingMerkle()
+	assert.Nil(t, stream.Root())
+	assert.Error(t, stream.Add([]byte("short")))
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleProofRoundTrip(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8, 13} {
+		events := chainedEvents(t, n)
+		root, err := MerkleRoot(events)
+		require.NoError(t, err)
+
+		for i, event := range events {
+			proof, err := MerkleProof(events, i)
+			require.NoError(t, err)
+			leaf, err := MerkleLeaf(event)
+			require.NoError(t, err)
+
+			assert.True(t, VerifyMerkleProof(leaf, root, proof, i), "%d events, index %d", n, i)
+			if n > 1 {
+				assert.False(t, VerifyMerkleProof(leaf, root, proof, (i+1)%n), "%d events, wrong index", n)
+			}
+		}
+	}
+}
+
+func TestMerkleProofDuplicatesOddLeaf(t *testing.T) {
+	events := chainedEvents(t, 3)
+	leaves := make([][32]byte, 3)
+	for i, event := range events {
+		var err error
+		leaves[i], err = MerkleLeaf(event)
+		require.NoError(t, err)
+	}
+
+	root, err := MerkleRoot(events)
+	require.NoError(t, err)
+	assert.Equal(t, merkleNode(merkleNode(leaves[0], leaves[1]), merkleNode(leaves[2], leaves[2])), root)
+
+	proof, err := MerkleProof(events, 2)
+	require.NoError(t, err)
+	assert.Equal(t, [][32]byte{leaves[2], merkleNode(leaves[0], leaves[1])}, proof)
+}
+
+func TestMerkleProofRejectsTampering(t *testing.T) {
+	events := chainedEvents(t, 4)
+	root, err := MerkleRoot(events)
+	require.NoError(t, err)
+	proof, err := MerkleProof(events, 1)
+	require.NoError(t, err)
+
+	events[1].Amount.MinorUnits++
+	leaf, err := MerkleLeaf(events[1])
+	require.NoError(t, err)
+	assert.False(t, VerifyMerkleProof(leaf, root, proof, 1))
+
+	_, err = MerkleProof(events, 4)
+	assert.Error(t, err)
+	_, err = MerkleProof(nil, 0)
+	assert.Error(t, err)
+}
+
+func TestMerkleLeafCoversChainPosition(t *testing.T) {
+	events := chainedEvents(t, 2)
+	leaf, err := MerkleLeaf(events[1])
+	require.NoError(t, err)
+	chained := mustHash(t, events[1])
+	assert.Equal(t, sha256.Sum256(append([]byte{0x00}, chained[:]...)), leaf)
+
+	root, err := MerkleRoot(events)
+	require.NoError(t, err)
+	first, err := MerkleLeaf(events[0])
+	require.NoError(t, err)
+	assert.Equal(t, sha256.Sum256(append(append([]byte{0x01}, first[:]...), leaf[:]...)), root)
+
+	events[1].ChainAfter(nil)
+	relinked, err := MerkleRoot(events)
+	require.NoError(t, err)
+	assert.NotEqual(t, root, relinked, "unlinking an event changes its leaf")
+}
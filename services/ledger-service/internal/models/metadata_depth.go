@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+)
+
+// DefaultMaxMetadataDepth is the metadata nesting depth allowed unless SetMaxMetadataDepth changes it
+const DefaultMaxMetadataDepth = 8
+
+// ErrMetadataTooDeep is returned when event metadata nests maps or slices deeper than the configured limit
+var ErrMetadataTooDeep = errors.New("metadata nested too deeply")
+
+var maxMetadataDepth atomic.Int64
+
+func init() {
+	maxMetadataDepth.Store(DefaultMaxMetadataDepth)
+}
+
+// SetMaxMetadataDepth sets how deeply maps and slices may nest in event metadata, counting the
+// metadata map itself as depth 1, and returns the previous limit. A depth <= 0 disables the check.
+func SetMaxMetadataDepth(depth int) int {
+	return int(maxMetadataDepth.Swap(int64(depth)))
+}
+
+// MaxMetadataDepth returns the current metadata nesting limit
+func MaxMetadataDepth() int {
+	return int(maxMetadataDepth.Load())
+}
+
+// checkMetadataDepth returns ErrMetadataTooDeep with the path of the first container, in sorted
+// key order, that lies beyond the limit
+func checkMetadataDepth(metadata map[string]interface{}) error {
+	limit := MaxMetadataDepth()
+	if limit <= 0 || metadata == nil {
+		return nil
+	}
+	return checkDepth(reflect.ValueOf(metadata), "metadata", 1, limit)
+}
+
+// checkDepth walks maps, slices and arrays below value, which sits at depth
+func checkDepth(value reflect.Value, path string, depth, limit int) error {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return nil
+	}
+	// Byte slices encode as a single string
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+		return nil
+	}
+	if depth > limit {
+		return fmt.Errorf("%w: %s is at depth %d, limit is %d", ErrMetadataTooDeep, path, depth, limit)
+	}
+
+	if value.Kind() == reflect.Map {
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			if err := checkDepth(value.MapIndex(key), fmt.Sprintf("%s.%v", path, key.Interface()), depth+1, limit); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err := checkDepth(value.Index(i), fmt.Sprintf("%s[%d]", path, i), depth+1, limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nestedMetadata returns metadata whose maps nest depth levels deep, counting the metadata itself
+func nestedMetadata(depth int) map[string]interface{} {
+	metadata := map[string]interface{}{"leaf": "value"}
+	for i := 1; i < depth; i++ {
+		metadata = map[string]interface{}{"n": metadata}
+	}
+	return metadata
+}
+
+func TestMetadataDepthAtLimit(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	event.Metadata = nestedMetadata(DefaultMaxMetadataDepth)
+
+	assert.NoError(t, event.Validate())
+	assert.NoError(t, event.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+}
+
+func TestMetadataDepthBeyondLimit(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	event.Metadata = map[string]interface{}{
+		"flat": "value",
+		"tags": []interface{}{"a", nestedMetadata(DefaultMaxMetadataDepth - 1)},
+	}
+
+	err := event.Validate()
+	require.ErrorIs(t, err, ErrMetadataTooDeep)
+	assert.Contains(t, err.Error(), "metadata.tags[1].n.n.n.n.n.n")
+
+	err = event.SignWith(NewHMACKey("ledger-1", []byte("secret")))
+	assert.ErrorIs(t, err, ErrMetadataTooDeep)
+}
+
+func TestSetMaxMetadataDepth(t *testing.T) {
+	previous := SetMaxMetadataDepth(2)
+	defer SetMaxMetadataDepth(previous)
+
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	event.Metadata = nestedMetadata(2)
+	assert.NoError(t, event.Validate())
+
+	event.Metadata = nestedMetadata(3)
+	assert.ErrorIs(t, event.Validate(), ErrMetadataTooDeep)
+
+	SetMaxMetadataDepth(0)
+	assert.NoError(t, event.Validate())
+}
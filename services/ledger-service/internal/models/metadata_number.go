@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// ErrInexactMetadataNumber is returned when a metadata number has no exact float64 form, such as
+// an integer above 2^53, so that it cannot be told apart from its neighbours once canonicalized
+var ErrInexactMetadataNumber = errors.New("metadata number not exactly representable")
+
+// floatNumbers replaces the json.Numbers below value with their float64 values, rejecting any
+// whose shortest float64 form names a different number than the one written
+func floatNumbers(value interface{}, path string) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			converted, err := floatNumbers(child, path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	case []interface{}:
+		for i, child := range v {
+			converted, err := floatNumbers(child, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is %s", ErrInexactMetadataNumber, path, v)
+		}
+		written, ok := new(big.Rat).SetString(v.String())
+		shortest, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+		if !ok || written.Cmp(shortest) != 0 {
+			return nil, fmt.Errorf("%w: %s is %s", ErrInexactMetadataNumber, path, v)
+		}
+		return f, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLargeMetadataIntegersDoNotCollide(t *testing.T) {
+	exact := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithMetadata("ref", int64(1<<53))
+	require.NoError(t, exact.Validate())
+	require.NoError(t, exact.Sign("secret"))
+
+	neighbour := exact.Clone().WithMetadata("ref", int64(1<<53+1))
+	assert.ErrorIs(t, neighbour.Validate(), ErrInexactMetadataNumber)
+	assert.ErrorIs(t, neighbour.Sign("secret"), ErrInexactMetadataNumber)
+
+	for _, value := range []interface{}{uint64(math.MaxUint64), json.Number("1.00000000000000001"), json.Number("1e400")} {
+		event := exact.Clone().WithMetadata("nested", []interface{}{map[string]interface{}{"n": value}})
+		_, err := event.CanonicalBytes()
+		assert.ErrorIs(t, err, ErrInexactMetadataNumber, "%v", value)
+	}
+}
+
+func TestMetadataDecimalsStayCanonical(t *testing.T) {
+	a, b := 0.1, 0.2
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").
+		WithMetadata("rate", json.Number("0.10")).
+		WithMetadata("drift", a+b)
+	canonical, err := event.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(canonical), `"drift":0.30000000000000004`)
+	assert.Contains(t, string(canonical), `"rate":0.1`)
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// ErrUnknownMetadataKey is returned in strict mode for a metadata key the event type does not allow
+	ErrUnknownMetadataKey = errors.New("unknown metadata key")
+	// ErrMissingMetadataKey is returned in strict mode when a required metadata key is absent
+	ErrMissingMetadataKey = errors.New("missing required metadata key")
+)
+
+// MetadataPolicy declares the metadata keys an event type may and must carry
+type MetadataPolicy struct {
+	Allowed  []string
+	Required []string
+}
+
+// permits reports whether key is allowed by the policy; required keys are implicitly allowed
+func (p MetadataPolicy) permits(key string) bool {
+	for _, allowed := range p.Allowed {
+		if allowed == key {
+			return true
+		}
+	}
+	for _, required := range p.Required {
+		if required == key {
+			return true
+		}
+	}
+	return false
+}
+
+// MetadataValidator validates events and checks their metadata against per-type policies.
+// In strict mode policy violations are errors; in lenient mode they are only logged.
+type MetadataValidator struct {
+	policies map[EventType]MetadataPolicy
+	strict   bool
+	logger   logrus.FieldLogger
+}
+
+// NewMetadataValidator creates a validator for the given policies.
+// Event types without a policy accept any metadata.
+func NewMetadataValidator(policies map[EventType]MetadataPolicy, strict bool) *MetadataValidator {
+	return &MetadataValidator{
+		policies: policies,
+		strict:   strict,
+		logger:   logrus.StandardLogger(),
+	}
+}
+
+// WithLogger sets the logger used for lenient-mode warnings
+func (v *MetadataValidator) WithLogger(logger logrus.FieldLogger) *MetadataValidator {
+	v.logger = logger
+	return v
+}
+
+// Validate runs the event's own validation followed by its metadata policy
+func (v *MetadataValidator) Validate(e *LedgerEvent) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	policy, ok := v.policies[e.Type]
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(e.Metadata))
+	for key := range e.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !policy.permits(key) {
+			if err := v.violation(e, fmt.Errorf("%w: %q on %s event", ErrUnknownMetadataKey, key, e.Type)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range policy.Required {
+		if _, ok := e.Metadata[key]; !ok {
+			if err := v.violation(e, fmt.Errorf("%w: %q on %s event", ErrMissingMetadataKey, key, e.Type)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// violation returns err in strict mode and logs it otherwise
+func (v *MetadataValidator) violation(e *LedgerEvent, err error) error {
+	if v.strict {
+		return err
+	}
+	v.logger.WithField("eventId", e.ID).Warn(err.Error())
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var debitPolicies = map[EventType]MetadataPolicy{
+	Debit: {Allowed: []string{"description"}, Required: []string{"channel"}},
+}
+
+func newDebit() *LedgerEvent {
+	return NewLedgerEvent(Debit, NewMoney(2500, "USD", 2), "acc_1", "corr_1").
+		WithMetadata("channel", "card")
+}
+
+func TestMetadataValidatorStrictRejectsUnknownKey(t *testing.T) {
+	validator := NewMetadataValidator(debitPolicies, true)
+
+	require.NoError(t, validator.Validate(newDebit().WithMetadata("description", "groceries")))
+
+	err := validator.Validate(newDebit().WithMetadata("customerEmail", "a@example.com"))
+	require.ErrorIs(t, err, ErrUnknownMetadataKey)
+	assert.Contains(t, err.Error(), "customerEmail")
+}
+
+func TestMetadataValidatorStrictRequiresKeys(t *testing.T) {
+	validator := NewMetadataValidator(debitPolicies, true)
+
+	event := NewLedgerEvent(Debit, NewMoney(2500, "USD", 2), "acc_1", "corr_1")
+	assert.ErrorIs(t, validator.Validate(event), ErrMissingMetadataKey)
+}
+
+func TestMetadataValidatorLenientWarns(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	validator := NewMetadataValidator(debitPolicies, false).WithLogger(logger)
+
+	require.NoError(t, validator.Validate(newDebit().WithMetadata("customerEmail", "a@example.com")))
+	require.Len(t, hook.Entries, 1)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Contains(t, hook.LastEntry().Message, "customerEmail")
+}
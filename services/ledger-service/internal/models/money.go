@@ -0,0 +1,388 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"fintech-platform/ledger-service/internal/currency"
+)
+
+var (
+	// ErrUnknownCurrency is returned when a currency code is not in the currency table
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrPrecisionLoss is returned when an amount cannot be represented exactly at a precision
+	ErrPrecisionLoss = errors.New("amount not representable at precision")
+	// ErrPrecisionMismatch is returned when amounts at different precisions are combined
+	ErrPrecisionMismatch = errors.New("precision mismatch")
+	// ErrAmountOverflow is returned when arithmetic on minor units overflows int64
+	ErrAmountOverflow = errors.New("amount overflows minor units")
+)
+
+// maxPrecision bounds the decimal places an amount may carry, keeping 10^precision within int64
+const maxPrecision = 18
+
+// Money is an amount held as a whole number of minor units at Precision decimal places, so
+// {MinorUnits: 1025, Currency: "USD", Precision: 2} is 10.25 USD. Integer minor units add up
+// without drift, and equal amounts always encode, and so sign, identically.
+//
+// The JSON form is {"amount": 10.25, "currency": "USD", "precision": 2}, with the amount as a
+// decimal number, which is the form events have always been stored and signed in.
+type Money struct {
+	MinorUnits int64
+	Currency   string
+	Precision  int
+}
+
+// NewMoney returns minor × 10^-precision of the currency, e.g. NewMoney(1025, "USD", 2) is 10.25 USD
+func NewMoney(minor int64, currency string, precision int) Money {
+	return Money{MinorUnits: minor, Currency: currency, Precision: precision}
+}
+
+// ZeroMoney returns a zero amount in the given currency at its standard precision,
+// suitable as the starting value of an accumulator
+func ZeroMoney(code string) (Money, error) {
+	precision, ok := currency.Precision(code)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return Money{Currency: code, Precision: precision}, nil
+}
+
+// Float returns the amount as a float64. It is meant for display and metrics only; arithmetic
+// and comparisons must use the minor units.
+func (m Money) Float() float64 {
+	return float64(m.MinorUnits) / math.Pow10(m.Precision)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount
+func (m Money) Sign() int {
+	switch {
+	case m.MinorUnits < 0:
+		return -1
+	case m.MinorUnits > 0:
+		return 1
+	}
+	return 0
+}
+
+// Rescale returns the same amount at another precision, or ErrPrecisionLoss if lowering the
+// precision would drop non-zero digits
+func (m Money) Rescale(precision int) (Money, error) {
+	if precision < 0 || precision > maxPrecision {
+		return m, fmt.Errorf("precision %d out of range", precision)
+	}
+	if precision < m.Precision && m.MinorUnits%pow10Int(m.Precision-precision) != 0 {
+		return m, fmt.Errorf("%w: %s at precision %d", ErrPrecisionLoss, m.decimal(), precision)
+	}
+	m.MinorUnits, m.Precision = m.minorAt(precision), precision
+	return m, nil
+}
+
+// Normalize returns the amount at its currency's standard precision, dropping trailing zero
+// digits of a finer precision and padding a coarser one. Normalization never changes the
+// numeric value: digits that are not zero are kept, so 10.005 USD stays at precision 3.
+// Amounts in currencies outside the currency table are returned unchanged, and padding that
+// overflows the minor units is an ErrAmountOverflow.
+func (m Money) Normalize() (Money, error) {
+	standard, ok := currency.Precision(m.Currency)
+	if !ok {
+		return m, nil
+	}
+	if m.Precision < standard {
+		padded, err := m.Mul(pow10Int(standard - m.Precision))
+		if err != nil {
+			return Money{}, err
+		}
+		padded.Precision = standard
+		return padded, nil
+	}
+	for m.Precision > standard && m.MinorUnits%10 == 0 {
+		m.MinorUnits /= 10
+		m.Precision--
+	}
+	return m, nil
+}
+
+// Equal reports whether two amounts are in the same currency and have the same value,
+// so 10.00 and 10.000 USD are equal
+func (m Money) Equal(other Money) bool {
+	if m.Currency != other.Currency {
+		return false
+	}
+	precision := maxInt(m.Precision, other.Precision)
+	return m.minorAt(precision) == other.minorAt(precision)
+}
+
+// Add returns m + other. Both must have the same currency and precision; use Rescale to align
+// precisions first. Neither operand is modified.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.compatible(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.MinorUnits + other.MinorUnits
+	if (sum > m.MinorUnits) != (other.MinorUnits > 0) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrAmountOverflow, m.decimal(), other.decimal())
+	}
+	m.MinorUnits = sum
+	return m, nil
+}
+
+// Sub returns m - other, with the same requirements as Add
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.compatible(other); err != nil {
+		return Money{}, err
+	}
+	if other.MinorUnits == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrAmountOverflow, m.decimal(), other.decimal())
+	}
+	return m.Add(other.negate())
+}
+
+// Mul returns m × factor
+func (m Money) Mul(factor int64) (Money, error) {
+	if m.MinorUnits == 0 || factor == 0 {
+		m.MinorUnits = 0
+		return m, nil
+	}
+	product := m.MinorUnits * factor
+	if product/factor != m.MinorUnits || (factor == -1 && m.MinorUnits == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s × %d", ErrAmountOverflow, m.decimal(), factor)
+	}
+	m.MinorUnits = product
+	return m, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() (Money, error) {
+	if m.MinorUnits == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: -(%s)", ErrAmountOverflow, m.decimal())
+	}
+	return m.negate(), nil
+}
+
+// SumPromoting sums amounts of a single currency, promoting each to the highest precision among
+// them first, so 1.25 and 0.125 USD sum to 1.375 at precision 3. Promotion never rounds; an
+// amount that overflows at the promoted precision is an ErrAmountOverflow, and amounts in
+// different currencies are an ErrCurrencyMismatch since this never converts currencies.
+func SumPromoting(amounts []Money) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{}, fmt.Errorf("no amounts to sum")
+	}
+	precision := 0
+	for _, amount := range amounts {
+		precision = maxInt(precision, amount.Precision)
+	}
+
+	sum := NewMoney(0, amounts[0].Currency, precision)
+	for _, amount := range amounts {
+		promoted, err := amount.Mul(pow10Int(precision - amount.Precision))
+		if err != nil {
+			return Money{}, err
+		}
+		promoted.Precision = precision
+		if sum, err = sum.Add(promoted); err != nil {
+			return Money{}, err
+		}
+	}
+	return sum, nil
+}
+
+// compatible checks that other can be combined with m without conversion
+func (m Money) compatible(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	if m.Precision != other.Precision {
+		return fmt.Errorf("%w: %d and %d", ErrPrecisionMismatch, m.Precision, other.Precision)
+	}
+	return nil
+}
+
+// minorAt returns the amount in minor units at the given precision, rounding half away from
+// zero when the precision is lower than the amount's
+func (m Money) minorAt(precision int) int64 {
+	if precision >= m.Precision {
+		return m.MinorUnits * pow10Int(precision-m.Precision)
+	}
+	scale := pow10Int(m.Precision - precision)
+	quotient, remainder := m.MinorUnits/scale, m.MinorUnits%scale
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	if remainder*2 >= scale {
+		if m.MinorUnits < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+	return quotient
+}
+
+// add returns m + other at the finer of their precisions, keeping m's currency; callers check
+// that the currencies match
+func (m Money) add(other Money) Money {
+	precision := maxInt(m.Precision, other.Precision)
+	m.MinorUnits = m.minorAt(precision) + other.minorAt(precision)
+	m.Precision = precision
+	return m
+}
+
+// negate returns -m
+func (m Money) negate() Money {
+	m.MinorUnits = -m.MinorUnits
+	return m
+}
+
+// decimal returns the amount as an exact decimal string
+func (m Money) decimal() string {
+	return DecimalFromMoney(m).String()
+}
+
+// String returns the amount with all of its decimal places followed by its currency, such as
+// "12.50 USD", the canonical form ParseMoney reads back
+func (m Money) String() string {
+	return DecimalFromMoney(m).rat().FloatString(m.Precision) + " " + m.Currency
+}
+
+// ParseMoney parses an amount written as "12.50 USD" or "USD 12.50" at its currency's
+// standard precision. The amount may be signed and group thousands with commas; more decimal
+// places than the currency allows are rejected rather than rounded.
+func ParseMoney(s string) (Money, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Money{}, fmt.Errorf("%w: %q is not an amount and a currency code", ErrMalformedAmount, s)
+	}
+	amount, code := fields[0], fields[1]
+	if isCurrencyCode(amount) {
+		amount, code = code, amount
+	}
+
+	zero, err := ZeroMoney(code)
+	if err != nil {
+		return Money{}, err
+	}
+	return parseImported(amount, '.', zero)
+}
+
+// isCurrencyCode reports whether s has the shape of an ISO 4217 code
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// moneyJSON is the wire and signing form of Money
+type moneyJSON struct {
+	Amount    json.Number `json:"amount"`
+	Currency  string      `json:"currency"`
+	Precision *int        `json:"precision"`
+}
+
+// maxFloatDigits bounds the minor units written as floats: any decimal of up to 15 significant
+// digits survives a float64 round trip, while some of 16 digits come back changed
+const maxFloatDigits = 1_000_000_000_000_000
+
+// floatNoise is the relative distance from an amount at its precision within which a decoded
+// amount with more decimal places is taken for float drift rather than a different amount
+const floatNoise = 1e-15
+
+// MarshalJSON encodes the amount as a decimal number. Amounts of up to 15 significant digits
+// are written exactly as encoding/json writes the equivalent float64, keeping the bytes, and
+// so signatures, of events recorded when amounts were floats; longer amounts are written as
+// exact decimals, since float64 cannot carry them.
+func (m Money) MarshalJSON() ([]byte, error) {
+	var amount []byte
+	if m.MinorUnits < maxFloatDigits && m.MinorUnits > -maxFloatDigits && m.Precision <= 15 {
+		encoded, err := json.Marshal(m.Float())
+		if err != nil {
+			return nil, err
+		}
+		amount = encoded
+	} else {
+		amount = []byte(m.decimal())
+	}
+	precision := m.Precision
+	return json.Marshal(moneyJSON{Amount: json.Number(amount), Currency: m.Currency, Precision: &precision})
+}
+
+// UnmarshalJSON decodes a decimal amount into minor units. An amount with more decimal places
+// than its precision is an ErrPrecisionLoss, unless it is float drift from when amounts were
+// stored as floats, such as 0.30000000000000004, which is rounded to the amount it drifted
+// from. Without a precision, it is the currency's standard precision, raised as far as needed
+// to hold the amount exactly.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	amount := new(big.Rat)
+	if raw.Amount != "" {
+		if _, ok := amount.SetString(string(raw.Amount)); !ok {
+			return fmt.Errorf("invalid amount %q", raw.Amount)
+		}
+	}
+
+	precision := 0
+	if raw.Precision != nil {
+		precision = *raw.Precision
+	} else {
+		precision, _ = currency.Precision(raw.Currency)
+		for precision < maxPrecision && !new(big.Rat).Mul(amount, pow10(precision)).IsInt() {
+			precision++
+		}
+	}
+	if precision < 0 || precision > maxPrecision {
+		return fmt.Errorf("precision %d out of range", precision)
+	}
+
+	minor, err := (Decimal{r: amount}).minorUnits(precision, RoundHalfUp)
+	if err != nil {
+		return err
+	}
+	decoded := Money{MinorUnits: minor, Currency: raw.Currency, Precision: precision}
+	if !new(big.Rat).Mul(amount, pow10(precision)).IsInt() && !isFloatDrift(amount, decoded) {
+		return fmt.Errorf("%w: %s %s at precision %d", ErrPrecisionLoss, raw.Amount, raw.Currency, precision)
+	}
+	*m = decoded
+	return nil
+}
+
+// isFloatDrift reports whether amount is within float noise of rounded, the amount at its
+// precision it was rounded to
+func isFloatDrift(amount *big.Rat, rounded Money) bool {
+	drifted, _ := amount.Float64()
+	exact := rounded.Float()
+	return exact != 0 && math.Abs(drifted-exact) <= floatNoise*math.Abs(exact)
+}
+
+// pow10Int returns 10^n for n >= 0, and 1 otherwise
+func pow10Int(n int) int64 {
+	result := int64(1)
+	for ; n > 0; n-- {
+		result *= 10
+	}
+	return result
+}
+
+// maxInt returns the larger of a and b
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
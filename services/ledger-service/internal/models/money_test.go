@@ -0,0 +1,245 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroMoneyAccumulates(t *testing.T) {
+	total, err := ZeroMoney("USD")
+	require.NoError(t, err)
+	assert.Equal(t, Money{Currency: "USD", Precision: 2}, total)
+
+	for _, amount := range []Money{NewMoney(1025, "USD", 2), NewMoney(475, "USD", 2)} {
+		require.Equal(t, total.Currency, amount.Currency)
+		total = total.add(amount)
+	}
+	assert.Equal(t, NewMoney(1500, "USD", 2), total)
+
+	yen, err := ZeroMoney("JPY")
+	require.NoError(t, err)
+	assert.Equal(t, 0, yen.Precision)
+}
+
+func TestZeroMoneyUnknownCurrency(t *testing.T) {
+	_, err := ZeroMoney("XYZ")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestMoneyNormalizeAcrossPrecisions(t *testing.T) {
+	a := NewMoney(1000, "USD", 2)
+	b := NewMoney(10000, "USD", 3)
+
+	assert.Equal(t, a, normalized(t, b))
+	assert.True(t, a.Equal(b))
+	assert.True(t, b.Equal(a))
+
+	fine := NewMoney(10005, "USD", 3)
+	assert.Equal(t, fine, normalized(t, fine))
+	assert.False(t, a.Equal(fine))
+
+	assert.Equal(t, a, normalized(t, NewMoney(10, "USD", 0)))
+	assert.False(t, a.Equal(NewMoney(1000, "EUR", 2)))
+}
+
+func TestMoneyNormalizeOverflow(t *testing.T) {
+	_, err := NewMoney(math.MaxInt64/10, "USD", 0).Normalize()
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = NewMoney(math.MinInt64/10-1, "USD", 1).Normalize()
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func normalized(t *testing.T, m Money) Money {
+	t.Helper()
+	normal, err := m.Normalize()
+	require.NoError(t, err)
+	return normal
+}
+
+func TestMoneyAddsWithoutDrift(t *testing.T) {
+	total := NewMoney(0, "USD", 2)
+	for i := 0; i < 10000; i++ {
+		total = total.add(NewMoney(10, "USD", 2)).add(NewMoney(20, "USD", 2))
+	}
+	assert.Equal(t, NewMoney(300000, "USD", 2), total)
+	assert.Equal(t, 3000.0, total.Float())
+}
+
+func TestMoneyRescale(t *testing.T) {
+	up, err := NewMoney(1025, "USD", 2).Rescale(4)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(102500, "USD", 4), up)
+
+	down, err := NewMoney(102500, "USD", 4).Rescale(2)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1025, "USD", 2), down)
+
+	_, err = NewMoney(10005, "USD", 3).Rescale(2)
+	assert.ErrorIs(t, err, ErrPrecisionLoss)
+}
+
+func TestMoneyJSONKeepsLegacyShape(t *testing.T) {
+	encoded, err := json.Marshal(NewMoney(1025, "USD", 2))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":10.25,"currency":"USD","precision":2}`, string(encoded))
+
+	large, err := json.Marshal(NewMoney(1<<62, "XYZ", 4))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":461168601842738.7904,"currency":"XYZ","precision":4}`, string(large))
+
+	var decoded Money
+	require.NoError(t, json.Unmarshal(large, &decoded))
+	assert.Equal(t, NewMoney(1<<62, "XYZ", 4), decoded)
+}
+
+func TestMoneyJSONRoundTripsSixteenDigitAmounts(t *testing.T) {
+	amount := NewMoney(9006380268430009, "XYZ", 3)
+	encoded, err := json.Marshal(amount)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":9006380268430.009,"currency":"XYZ","precision":3}`, string(encoded))
+
+	var decoded Money
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, amount, decoded)
+
+	for minor := int64(999_999_999_999_990); minor < 1_000_000_000_000_010; minor++ {
+		amount := NewMoney(minor, "USD", 2)
+		encoded, err := json.Marshal(amount)
+		require.NoError(t, err)
+		var decoded Money
+		require.NoError(t, json.Unmarshal(encoded, &decoded))
+		require.Equal(t, amount, decoded, "%s", encoded)
+	}
+}
+
+func TestMoneyJSONRejectsDigitsBeyondPrecision(t *testing.T) {
+	var decoded Money
+	err := json.Unmarshal([]byte(`{"amount":10.25,"currency":"USD","precision":0}`), &decoded)
+	assert.ErrorIs(t, err, ErrPrecisionLoss)
+	err = json.Unmarshal([]byte(`{"amount":0.004,"currency":"USD","precision":2}`), &decoded)
+	assert.ErrorIs(t, err, ErrPrecisionLoss)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":10.199999999999999,"currency":"USD","precision":2}`), &decoded))
+	assert.Equal(t, NewMoney(1020, "USD", 2), decoded, "float drift is rounded away")
+}
+
+func TestLedgerEventFromJSONMigratesFloatAmounts(t *testing.T) {
+	event, err := LedgerEventFromJSON([]byte(`{"id":"evt_1","type":"credit",` +
+		`"amount":{"amount":0.30000000000000004,"currency":"USD","precision":2},"currency":"USD"}`))
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(30, "USD", 2), event.Amount)
+
+	event, err = LedgerEventFromJSON([]byte(`{"id":"evt_2","type":"credit",` +
+		`"amount":{"amount":10.5,"currency":"USD"},"currency":"USD"}`))
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1050, "USD", 2), event.Amount, "a missing precision comes from the currency")
+
+	event, err = LedgerEventFromJSON([]byte(`{"id":"evt_3","type":"credit",` +
+		`"amount":{"amount":10.005,"currency":"USD"},"currency":"USD"}`))
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(10005, "USD", 3), event.Amount, "digits are never dropped without a precision")
+}
+
+func TestEqualAmountsSignIdentically(t *testing.T) {
+	summed := NewMoney(10, "USD", 2).add(NewMoney(20, "USD", 2))
+	migrated, err := LedgerEventFromJSON([]byte(`{"amount":{"amount":0.30000000000000004,"currency":"USD","precision":2}}`))
+	require.NoError(t, err)
+
+	a := NewLedgerEvent(Credit, summed, "acc_1", "corr_1")
+	b := *a
+	b.Amount = migrated.Amount
+
+	require.NoError(t, a.Sign("secret"))
+	require.NoError(t, b.Sign("secret"))
+	assert.Equal(t, a.Signature, b.Signature)
+	assert.NoError(t, a.Validate())
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, b := NewMoney(1025, "USD", 2), NewMoney(475, "USD", 2)
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1500, "USD", 2), sum)
+	assert.Equal(t, NewMoney(1025, "USD", 2), a, "operands are not modified")
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(-550, "USD", 2), diff)
+
+	product, err := a.Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(3075, "USD", 2), product)
+
+	neg, err := a.Neg()
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(-1025, "USD", 2), neg)
+}
+
+func TestMoneyArithmeticRejectsMismatches(t *testing.T) {
+	usd := NewMoney(100, "USD", 2)
+
+	_, err := usd.Add(NewMoney(100, "EUR", 2))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = usd.Sub(NewMoney(1000, "USD", 3))
+	assert.ErrorIs(t, err, ErrPrecisionMismatch)
+
+	_, err = NewMoney(math.MaxInt64, "USD", 2).Add(NewMoney(1, "USD", 2))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = NewMoney(math.MinInt64, "USD", 2).Sub(NewMoney(1, "USD", 2))
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = NewMoney(math.MaxInt64/2+1, "USD", 2).Mul(2)
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = NewMoney(math.MinInt64, "USD", 2).Neg()
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestSumPromoting(t *testing.T) {
+	sum, err := SumPromoting([]Money{
+		NewMoney(125, "USD", 2),
+		NewMoney(125, "USD", 3),
+		NewMoney(-50, "USD", 2),
+		NewMoney(1, "USD", 3),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(876, "USD", 3), sum)
+
+	_, err = SumPromoting([]Money{NewMoney(1, "USD", 2), NewMoney(1, "EUR", 2)})
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = SumPromoting([]Money{NewMoney(math.MaxInt64/5, "USD", 2), NewMoney(1, "USD", 3)})
+	assert.ErrorIs(t, err, ErrAmountOverflow)
+	_, err = SumPromoting(nil)
+	assert.Error(t, err)
+}
+
+func TestParseMoneyRoundTrips(t *testing.T) {
+	for _, s := range []string{"12.50 USD", "USD 12.50", "-0.05 EUR", "1,234.50 USD", "1000 JPY", "KWD 1.250"} {
+		money, err := ParseMoney(s)
+		require.NoError(t, err, s)
+		parsed, err := ParseMoney(money.String())
+		require.NoError(t, err, s)
+		assert.Equal(t, money, parsed, s)
+	}
+
+	money, err := ParseMoney("USD 12.5")
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1250, "USD", 2), money)
+	assert.Equal(t, "12.50 USD", money.String())
+	assert.Equal(t, "1000 JPY", NewMoney(1000, "JPY", 0).String())
+	assert.Equal(t, "-0.05 EUR", NewMoney(-5, "EUR", 2).String())
+}
+
+func TestParseMoneyRejectsInvalidInput(t *testing.T) {
+	_, err := ParseMoney("12.505 USD")
+	assert.ErrorIs(t, err, ErrPrecisionLoss)
+	_, err = ParseMoney("12.50 XXY")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+	for _, s := range []string{"12.5x USD", "USD", "12.50 USD extra", "1.2.3 USD", ""} {
+		_, err = ParseMoney(s)
+		assert.ErrorIs(t, err, ErrMalformedAmount, s)
+	}
+}
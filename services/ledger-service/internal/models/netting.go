@@ -0,0 +1,88 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// AccountID identifies a ledger account
+type AccountID = string
+
+// CorrelationNet returns the net posted effect of a correlation on each account it touches:
+// credits and adjustments add, debits subtract, and holds and releases are ignored.
+// For a transfer the returned amounts net to zero across its accounts.
+func CorrelationNet(events []*LedgerEvent, correlationID string) (map[AccountID]Money, error) {
+	net := make(map[AccountID]Money)
+	for _, event := range events {
+		if event.CorrelationID != correlationID || !event.AffectsBalance() {
+			continue
+		}
+
+		total, ok := net[event.AccountID]
+		if !ok {
+			total = Money{Currency: event.Amount.Currency, Precision: event.Amount.Precision}
+		} else if total.Currency != event.Amount.Currency {
+			return nil, fmt.Errorf("%w: account %s has %s and %s events in correlation %s",
+				ErrCurrencyMismatch, event.AccountID, total.Currency, event.Amount.Currency, correlationID)
+		}
+
+		if event.IsDebit() {
+			total = total.add(event.Amount.negate())
+		} else {
+			total = total.add(event.Amount)
+		}
+		net[event.AccountID] = total
+	}
+	return net, nil
+}
+
+// NetSettlement nets the posted activity of a settlement day per counterparty and currency.
+// The day runs from midnight to midnight in loc, so it lasts 23 or 25 hours across a DST change;
+// events are placed by their effective time. counterpartyKey names each event's counterparty,
+// and events for which it returns "" are left out. The result maps counterparty to currency to
+// net amount, credits and adjustments adding and debits subtracting.
+func NetSettlement(events []*LedgerEvent, counterpartyKey func(*LedgerEvent) string, day time.Time, loc *time.Location) (map[string]map[string]Money, error) {
+	if counterpartyKey == nil {
+		return nil, fmt.Errorf("counterparty key is required")
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := day.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+
+	nets := make(map[string]map[string]Money)
+	for _, event := range events {
+		if !event.AffectsBalance() {
+			continue
+		}
+		at := event.EffectiveTime()
+		if at.Before(start) || !at.Before(end) {
+			continue
+		}
+		counterparty := counterpartyKey(event)
+		if counterparty == "" {
+			continue
+		}
+
+		if nets[counterparty] == nil {
+			nets[counterparty] = make(map[string]Money)
+		}
+		net, ok := nets[counterparty][event.Amount.Currency]
+		if !ok {
+			net = Money{Currency: event.Amount.Currency, Precision: event.Amount.Precision}
+		}
+		if event.IsDebit() {
+			net = net.add(event.Amount.negate())
+		} else {
+			net = net.add(event.Amount)
+		}
+		nets[counterparty][event.Amount.Currency] = net
+	}
+	return nets, nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationNetTransfer(t *testing.T) {
+	amount := NewMoney(7550, "USD", 2)
+	events := []*LedgerEvent{
+		NewLedgerEvent(Debit, amount, "acc_sender", "corr_transfer"),
+		NewLedgerEvent(Credit, amount, "acc_receiver", "corr_transfer"),
+		NewLedgerEvent(Hold, amount, "acc_sender", "corr_transfer"),
+		NewLedgerEvent(Credit, NewMoney(50000, "USD", 2), "acc_sender", "corr_other"),
+	}
+
+	net, err := CorrelationNet(events, "corr_transfer")
+	require.NoError(t, err)
+
+	require.Len(t, net, 2)
+	assert.Equal(t, int64(-7550), net["acc_sender"].MinorUnits)
+	assert.Equal(t, int64(7550), net["acc_receiver"].MinorUnits)
+
+	var total int64
+	for _, money := range net {
+		total += money.MinorUnits
+	}
+	assert.Zero(t, total)
+}
+
+func TestCorrelationNetRejectsMixedCurrencies(t *testing.T) {
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, NewMoney(1000, "USD", 2), "acc_1", "corr_1"),
+		NewLedgerEvent(Credit, NewMoney(1000, "EUR", 2), "acc_1", "corr_1"),
+	}
+
+	_, err := CorrelationNet(events, "corr_1")
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestNetSettlementPerCounterparty(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	at := func(e *LedgerEvent, hour int) *LedgerEvent {
+		e.Timestamp = time.Date(2024, 3, 14, hour, 0, 0, 0, est)
+		return e
+	}
+	byCounterparty := func(e *LedgerEvent) string {
+		counterparty, _ := e.Metadata["counterparty"].(string)
+		return counterparty
+	}
+	eur := func(amount float64) Money { return NewMoney(int64(math.Round(amount*100)), "EUR", 2) }
+
+	events := []*LedgerEvent{
+		at(NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1").WithMetadata("counterparty", "bank_a"), 0),
+		at(NewLedgerEvent(Debit, usdAmount(30.25), "acc_2", "corr_2").WithMetadata("counterparty", "bank_a"), 9),
+		at(NewLedgerEvent(Credit, eur(12), "acc_1", "corr_3").WithMetadata("counterparty", "bank_a"), 23),
+		at(NewLedgerEvent(Debit, usdAmount(50), "acc_1", "corr_4").WithMetadata("counterparty", "bank_b"), 12),
+		at(NewLedgerEvent(Debit, usdAmount(20), "acc_3", "corr_5").WithMetadata("counterparty", "bank_b"), 18),
+		// Holds do not settle, and nor does activity without a counterparty
+		at(NewLedgerEvent(Hold, usdAmount(500), "acc_1", "corr_6").WithMetadata("counterparty", "bank_b"), 10),
+		at(NewLedgerEvent(Credit, usdAmount(7), "acc_1", "corr_7"), 10),
+		// The previous and next day in EST, though the first is still the 14th in UTC
+		at(NewLedgerEvent(Credit, usdAmount(1000), "acc_1", "corr_8").WithMetadata("counterparty", "bank_a"), 24),
+		at(NewLedgerEvent(Credit, usdAmount(1000), "acc_1", "corr_9").WithMetadata("counterparty", "bank_b"), -1),
+	}
+
+	nets, err := NetSettlement(events, byCounterparty, time.Date(2024, 3, 14, 15, 0, 0, 0, time.UTC), est)
+	require.NoError(t, err)
+
+	require.Len(t, nets, 2)
+	assert.True(t, nets["bank_a"]["USD"].Equal(usdAmount(69.75)), nets["bank_a"]["USD"])
+	assert.True(t, nets["bank_a"]["EUR"].Equal(eur(12)))
+	require.Len(t, nets["bank_b"], 1)
+	assert.True(t, nets["bank_b"]["USD"].Equal(usdAmount(-70)), nets["bank_b"]["USD"])
+
+	_, err = NetSettlement(events, nil, time.Now(), est)
+	assert.Error(t, err)
+}
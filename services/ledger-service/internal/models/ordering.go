@@ -0,0 +1,72 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// EventOrder selects the time events are ordered by
+type EventOrder int
+
+const (
+	// Recorded orders events by when the ledger recorded them (Timestamp)
+	Recorded EventOrder = iota
+	// Effective orders events by when they take effect, which differs for backdated events
+	Effective
+)
+
+// String returns the name of the ordering
+func (o EventOrder) String() string {
+	if o == Effective {
+		return "effective"
+	}
+	return "recorded"
+}
+
+// WithEffectiveAt backdates or postdates the event to take effect at t rather than when recorded
+func (e *LedgerEvent) WithEffectiveAt(t time.Time) *LedgerEvent {
+	t = t.UTC()
+	e.EffectiveAt = &t
+	return e
+}
+
+// EffectiveTime returns when the event takes effect, which is its Timestamp unless EffectiveAt is set
+func (e *LedgerEvent) EffectiveTime() time.Time {
+	if e.EffectiveAt != nil {
+		return *e.EffectiveAt
+	}
+	return e.Timestamp
+}
+
+// TimeFor returns the event's time under the given ordering
+func (e *LedgerEvent) TimeFor(order EventOrder) time.Time {
+	if order == Effective {
+		return e.EffectiveTime()
+	}
+	return e.Timestamp
+}
+
+// SortEvents returns a copy of events sorted by the given ordering. Ties are broken by the
+// other ordering's time, then by version and finally by ID, so the result is deterministic.
+func SortEvents(events []*LedgerEvent, order EventOrder) []*LedgerEvent {
+	other := Effective
+	if order == Effective {
+		other = Recorded
+	}
+
+	sorted := append([]*LedgerEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ta, tb := a.TimeFor(order), b.TimeFor(order); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		if ta, tb := a.TimeFor(other), b.TimeFor(other); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.ID < b.ID
+	})
+	return sorted
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortEventsByRecordedAndEffectiveTime(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	first.Timestamp = base
+	second := NewLedgerEvent(Debit, usdAmount(5), "acc_1", "corr_1").WithVersion(2)
+	second.Timestamp = base.Add(2 * time.Hour)
+	backdated := NewLedgerEvent(Credit, usdAmount(3), "acc_1", "corr_1").WithVersion(3).WithEffectiveAt(base.Add(time.Hour))
+	backdated.Timestamp = base.Add(3 * time.Hour)
+	// Same effective time as backdated but recorded earlier, so it sorts first
+	tied := NewLedgerEvent(Credit, usdAmount(1), "acc_1", "corr_1").WithVersion(4).WithEffectiveAt(base.Add(time.Hour))
+	tied.Timestamp = base.Add(150 * time.Minute)
+
+	events := []*LedgerEvent{tied, backdated, second, first}
+
+	assert.Equal(t, []*LedgerEvent{first, second, tied, backdated}, SortEvents(events, Recorded))
+	assert.Equal(t, []*LedgerEvent{first, tied, backdated, second}, SortEvents(events, Effective))
+	assert.Equal(t, tied, events[0], "sorting must not reorder the input")
+}
@@ -0,0 +1,63 @@
+package models
+
+import "sort"
+
+// WithPriority sets the event's processing priority. During catch-up, events with a higher
+// priority, such as reversals and freezes, are processed ahead of routine ones recorded at the
+// same time; see PriorityOrder. The default priority is 0.
+func (e *LedgerEvent) WithPriority(priority int) *LedgerEvent {
+	e.Priority = priority
+	return e
+}
+
+// PriorityOrder returns a copy of events in the order a batch should be processed: by time
+// under order, higher priority first among events at the same time, then in batch order. An
+// account's events always stay in version order, the stronger constraint, so priority only
+// moves an event ahead of events of other accounts, or of its own account's events with the
+// same version, such as events whose versions are assigned when they are appended.
+func PriorityOrder(events []*LedgerEvent, order EventOrder) []*LedgerEvent {
+	position := make(map[*LedgerEvent]int, len(events))
+	queues := make(map[string][]*LedgerEvent)
+	var accounts []string
+	for i, event := range events {
+		position[event] = i
+		if _, ok := queues[event.AccountID]; !ok {
+			accounts = append(accounts, event.AccountID)
+		}
+		queues[event.AccountID] = append(queues[event.AccountID], event)
+	}
+
+	// before reports whether a is processed ahead of b when versions do not decide
+	before := func(a, b *LedgerEvent) bool {
+		if ta, tb := a.TimeFor(order), b.TimeFor(order); !ta.Equal(tb) {
+			return ta.Before(tb)
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return position[a] < position[b]
+	}
+	for _, accountID := range accounts {
+		queue := queues[accountID]
+		sort.SliceStable(queue, func(i, j int) bool {
+			if queue[i].Version != queue[j].Version {
+				return queue[i].Version < queue[j].Version
+			}
+			return before(queue[i], queue[j])
+		})
+	}
+
+	ordered := make([]*LedgerEvent, 0, len(events))
+	for len(ordered) < len(events) {
+		next := ""
+		for _, accountID := range accounts {
+			queue := queues[accountID]
+			if len(queue) > 0 && (next == "" || before(queue[0], queues[next][0])) {
+				next = accountID
+			}
+		}
+		ordered = append(ordered, queues[next][0])
+		queues[next] = queues[next][1:]
+	}
+	return ordered
+}
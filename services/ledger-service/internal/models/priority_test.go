@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityOrderAppliesFreezeBeforeDebits(t *testing.T) {
+	at := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	debit := func(corr string) *LedgerEvent {
+		event := NewLedgerEvent(Debit, usdAmount(10), "acc_1", corr)
+		event.Timestamp = at
+		return event
+	}
+	first, second := debit("corr_1"), debit("corr_2")
+	freeze := NewAccountFreeze("acc_1", "corr_3", "fraud").WithPriority(10)
+	freeze.Timestamp = at
+	credit := NewLedgerEvent(Credit, usdAmount(10), "acc_2", "corr_4")
+	credit.Timestamp = at.Add(-time.Second)
+
+	ordered := PriorityOrder([]*LedgerEvent{first, second, freeze, credit}, Recorded)
+	assert.Equal(t, []*LedgerEvent{credit, freeze, first, second}, ordered)
+
+	// Versions are assigned as the ordered events are appended, so the debits meet the freeze
+	var stream []*LedgerEvent
+	var rejected int
+	for _, event := range ordered {
+		if event.AccountID != "acc_1" {
+			continue
+		}
+		if err := CheckFreeze(stream, event); err != nil {
+			require.ErrorIs(t, err, ErrAccountFrozen)
+			rejected++
+			continue
+		}
+		stream = append(stream, event.WithVersion(int64(len(stream)+1)))
+	}
+	assert.Equal(t, 2, rejected)
+}
+
+func TestPriorityOrderKeepsVersionOrder(t *testing.T) {
+	at := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	first := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	second := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_2").WithVersion(2)
+	freeze := NewAccountFreeze("acc_1", "corr_3", "fraud").WithPriority(10).WithVersion(3)
+	for _, event := range []*LedgerEvent{first, second, freeze} {
+		event.Timestamp = at
+	}
+
+	ordered := PriorityOrder([]*LedgerEvent{freeze, second, first}, Recorded)
+	assert.Equal(t, []*LedgerEvent{first, second, freeze}, ordered)
+}
+
+func TestPriorityIsSigned(t *testing.T) {
+	event := NewLedgerEvent(Reversal, usdAmount(10), "acc_1", "corr_1").WithReverses("evt_1").WithPriority(5)
+	require.NoError(t, event.Sign("secret"))
+	event.Priority = 0
+	assert.False(t, event.Verify("secret"))
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ledgerv1 "fintech-platform/ledger-service/api/ledger/v1"
+)
+
+// eventTypePrefix prefixes event types in the protobuf EventType enum
+const eventTypePrefix = "EVENT_TYPE_"
+
+// ToProto converts the event to its protobuf form. Metadata is carried as a Struct holding the
+// values as the JSON form encodes them, so it fails for metadata ToJSON cannot encode.
+func (e *LedgerEvent) ToProto() (*ledgerv1.LedgerEvent, error) {
+	eventType, ok := ledgerv1.EventType_value[eventTypePrefix+string(e.Type)]
+	if !ok {
+		return nil, fmt.Errorf("event type %q has no protobuf equivalent", e.Type)
+	}
+	metadata, err := metadataToProto(e.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &ledgerv1.LedgerEvent{
+		Id:                 e.ID,
+		TenantId:           e.TenantID,
+		Type:               ledgerv1.EventType(eventType),
+		Amount:             moneyToProto(e.Amount),
+		Currency:           e.Currency,
+		AccountId:          e.AccountID,
+		PaymentId:          e.PaymentID,
+		ReferenceId:        e.ReferenceID,
+		ReversesEventId:    e.ReversesEventID,
+		HoldId:             e.HoldID,
+		Timestamp:          timestamppb.New(e.Timestamp),
+		EffectiveAt:        timeToProto(e.EffectiveAt),
+		ExpiresAt:          timeToProto(e.ExpiresAt),
+		ValidFrom:          timeToProto(e.ValidFrom),
+		ValidUntil:         timeToProto(e.ValidUntil),
+		Metadata:           metadata,
+		Signature:          e.Signature,
+		SignatureAlgorithm: e.SignatureAlgorithm,
+		KeyId:              e.KeyID,
+		PreviousHash:       e.PreviousHash,
+		HashLength:         int32(e.HashLength),
+		Version:            e.Version,
+		Priority:           int64(e.Priority),
+		CorrelationId:      e.CorrelationID,
+		IdempotencyKey:     e.IdempotencyKey,
+	}
+	for _, ref := range e.References {
+		pb.References = append(pb.References, &ledgerv1.EventRef{Kind: string(ref.Kind), EventId: ref.EventID})
+	}
+	if e.Fees != nil {
+		pb.Fees = &ledgerv1.FeeBreakdown{}
+		for _, component := range e.Fees.Components {
+			pb.Fees.Components = append(pb.Fees.Components, &ledgerv1.FeeComponent{
+				Type:   string(component.Type),
+				Amount: moneyToProto(component.Amount),
+			})
+		}
+	}
+	if e.Taxes != nil {
+		pb.Taxes = &ledgerv1.TaxBreakdown{}
+		for _, component := range e.Taxes.Components {
+			pb.Taxes.Components = append(pb.Taxes.Components, &ledgerv1.TaxComponent{
+				Type:         string(component.Type),
+				Jurisdiction: component.Jurisdiction,
+				Rate:         component.Rate.String(),
+				TaxableBase:  moneyToProto(component.TaxableBase),
+				TaxAmount:    moneyToProto(component.TaxAmount),
+			})
+		}
+	}
+	for _, prior := range e.PriorSignatures {
+		pb.PriorSignatures = append(pb.PriorSignatures, &ledgerv1.PriorSignature{
+			KeyId:     prior.KeyID,
+			Algorithm: prior.Algorithm,
+			Signature: prior.Signature,
+		})
+	}
+	for _, attestation := range e.Attestations {
+		pb.Attestations = append(pb.Attestations, &ledgerv1.Attestation{
+			AttesterId: attestation.AttesterID,
+			KeyId:      attestation.KeyID,
+			Algorithm:  attestation.Algorithm,
+			Signature:  attestation.Signature,
+			Timestamp:  timestamppb.New(attestation.Timestamp),
+		})
+	}
+	return pb, nil
+}
+
+// LedgerEventFromProto converts an event from its protobuf form. Converting an event with
+// ToProto and back gives the event LedgerEventFromJSON would decode from its ToJSON form:
+// timestamps come back in UTC and metadata numbers as float64.
+func LedgerEventFromProto(pb *ledgerv1.LedgerEvent) (*LedgerEvent, error) {
+	name, ok := ledgerv1.EventType_name[int32(pb.GetType())]
+	if !ok || pb.GetType() == ledgerv1.EventType_EVENT_TYPE_UNSPECIFIED {
+		return nil, fmt.Errorf("unknown protobuf event type %d", pb.GetType())
+	}
+
+	event := &LedgerEvent{
+		ID:                 pb.GetId(),
+		TenantID:           pb.GetTenantId(),
+		Type:               EventType(name[len(eventTypePrefix):]),
+		Amount:             moneyFromProto(pb.GetAmount()),
+		Currency:           pb.GetCurrency(),
+		AccountID:          pb.GetAccountId(),
+		PaymentID:          pb.PaymentId,
+		ReferenceID:        pb.ReferenceId,
+		ReversesEventID:    pb.ReversesEventId,
+		HoldID:             pb.HoldId,
+		Timestamp:          pb.GetTimestamp().AsTime(),
+		EffectiveAt:        timeFromProto(pb.GetEffectiveAt()),
+		ExpiresAt:          timeFromProto(pb.GetExpiresAt()),
+		ValidFrom:          timeFromProto(pb.GetValidFrom()),
+		ValidUntil:         timeFromProto(pb.GetValidUntil()),
+		Signature:          pb.GetSignature(),
+		SignatureAlgorithm: pb.GetSignatureAlgorithm(),
+		KeyID:              pb.GetKeyId(),
+		PreviousHash:       pb.GetPreviousHash(),
+		HashLength:         int(pb.GetHashLength()),
+		Version:            pb.GetVersion(),
+		Priority:           int(pb.GetPriority()),
+		CorrelationID:      pb.GetCorrelationId(),
+		IdempotencyKey:     pb.GetIdempotencyKey(),
+	}
+	if pb.GetMetadata() != nil {
+		event.Metadata = pb.GetMetadata().AsMap()
+	}
+	for _, ref := range pb.GetReferences() {
+		event.References = append(event.References, EventRef{Kind: RefKind(ref.GetKind()), EventID: ref.GetEventId()})
+	}
+	if pb.GetFees() != nil {
+		event.Fees = &FeeBreakdown{}
+		for _, component := range pb.GetFees().GetComponents() {
+			event.Fees.Components = append(event.Fees.Components, FeeComponent{
+				Type:   FeeType(component.GetType()),
+				Amount: moneyFromProto(component.GetAmount()),
+			})
+		}
+	}
+	if pb.GetTaxes() != nil {
+		event.Taxes = &TaxBreakdown{}
+		for _, component := range pb.GetTaxes().GetComponents() {
+			rate, err := ParseDecimal(component.GetRate())
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s tax rate: %w", component.GetJurisdiction(), err)
+			}
+			event.Taxes.Components = append(event.Taxes.Components, TaxComponent{
+				Type:         TaxType(component.GetType()),
+				Jurisdiction: component.GetJurisdiction(),
+				Rate:         rate,
+				TaxableBase:  moneyFromProto(component.GetTaxableBase()),
+				TaxAmount:    moneyFromProto(component.GetTaxAmount()),
+			})
+		}
+	}
+	for _, prior := range pb.GetPriorSignatures() {
+		event.PriorSignatures = append(event.PriorSignatures, PriorSignature{
+			KeyID:     prior.GetKeyId(),
+			Algorithm: prior.GetAlgorithm(),
+			Signature: prior.GetSignature(),
+		})
+	}
+	for _, attestation := range pb.GetAttestations() {
+		event.Attestations = append(event.Attestations, Attestation{
+			AttesterID: attestation.GetAttesterId(),
+			KeyID:      attestation.GetKeyId(),
+			Algorithm:  attestation.GetAlgorithm(),
+			Signature:  attestation.GetSignature(),
+			Timestamp:  attestation.GetTimestamp().AsTime(),
+		})
+	}
+	return event, nil
+}
+
+// moneyToProto converts an amount to its protobuf form
+func moneyToProto(m Money) *ledgerv1.Money {
+	return &ledgerv1.Money{MinorUnits: m.MinorUnits, Currency: m.Currency, Precision: int32(m.Precision)}
+}
+
+// moneyFromProto converts an amount from its protobuf form
+func moneyFromProto(pb *ledgerv1.Money) Money {
+	return NewMoney(pb.GetMinorUnits(), pb.GetCurrency(), int(pb.GetPrecision()))
+}
+
+// timeToProto converts an optional time, keeping nil as an unset field
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// timeFromProto converts an optional time, keeping an unset field as nil
+func timeFromProto(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// metadataToProto converts metadata to a Struct through its JSON form; nil metadata stays unset
+func metadataToProto(metadata map[string]interface{}) (*structpb.Struct, error) {
+	decoded, err := jsonMetadata(metadata)
+	if err != nil || decoded == nil {
+		return nil, err
+	}
+	return structpb.NewStruct(decoded)
+}
+
+// jsonMetadata returns metadata as LedgerEventFromJSON would decode it from its ToJSON form, so
+// values of any type ToJSON accepts convert as they would encode; nil metadata stays nil
+func jsonMetadata(metadata map[string]interface{}) (map[string]interface{}, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	return decoded, nil
+}
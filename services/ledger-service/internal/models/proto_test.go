@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	ledgerv1 "fintech-platform/ledger-service/api/ledger/v1"
+)
+
+// protoRoundTrip converts the event to protobuf, through the wire format, and back
+func protoRoundTrip(t *testing.T, event *LedgerEvent) *LedgerEvent {
+	t.Helper()
+	pb, err := event.ToProto()
+	require.NoError(t, err)
+	wire, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	var decoded ledgerv1.LedgerEvent
+	require.NoError(t, proto.Unmarshal(wire, &decoded))
+	converted, err := LedgerEventFromProto(&decoded)
+	require.NoError(t, err)
+	return converted
+}
+
+// jsonRoundTrip converts the event to JSON and back
+func jsonRoundTrip(t *testing.T, event *LedgerEvent) *LedgerEvent {
+	t.Helper()
+	encoded, err := event.ToJSON()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(encoded)
+	require.NoError(t, err)
+	return decoded
+}
+
+func TestProtoRoundTripsEveryField(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 30, 0, 123456789, time.UTC)
+	until := at.Add(24 * time.Hour)
+	event := NewLedgerEvent(Debit, usdAmount(12.5), "acc_1", "corr_1").
+		WithPaymentID("pay_1").
+		WithHoldID("hold_1").
+		WithReverses("evt_0").
+		AddReference(RefCaptures, "evt_hold").
+		WithEffectiveAt(at).
+		WithValidity(&at, &until).
+		WithExpiry(time.Hour).
+		WithVersion(7).
+		WithPriority(3).
+		WithIdempotencyKey("idem_1").
+		WithFeeBreakdown(FeeBreakdown{Components: []FeeComponent{
+			{Type: InterchangeFee, Amount: usdAmount(10)},
+			{Type: SchemeFee, Amount: usdAmount(2.5)},
+		}}).
+		WithTaxBreakdown(TaxBreakdown{Components: []TaxComponent{
+			{Type: VAT, Jurisdiction: "DE", Rate: NewDecimal(19, 2), TaxableBase: usdAmount(10.5), TaxAmount: usdAmount(2)},
+		}}).
+		WithMetadata("merchant", "m_1").
+		WithMetadata("occurrence", 2).
+		WithMetadata("flags", []string{"a", "b"}).
+		WithMetadata("nested", map[string]interface{}{"ok": true, "none": nil})
+	event.TenantID = "tenant_1"
+	event.PreviousHash = "abc"
+	event.HashLength = 16
+	event.PriorSignatures = []PriorSignature{{KeyID: "k0", Algorithm: AlgorithmHMACSHA256, Signature: "00"}}
+	event.Attestations = []Attestation{{AttesterID: "auditor", KeyID: "k2", Signature: "ff", Timestamp: at}}
+	require.NoError(t, event.SignWith(NewHMACKey("k1", []byte("secret"))))
+
+	converted := protoRoundTrip(t, event)
+	assert.Equal(t, jsonRoundTrip(t, event), converted)
+	assert.Equal(t, at, *converted.EffectiveAt)
+	assert.Equal(t, float64(2), converted.Metadata["occurrence"])
+	assert.NoError(t, converted.VerifyWith(NewHMACKey("k1", []byte("secret"))))
+}
+
+func TestProtoKeepsUnsetOptionalFields(t *testing.T) {
+	event := NewLedgerEvent(AccountFreeze, Money{}, "acc_1", "corr_1")
+	event.Metadata = nil
+
+	converted := protoRoundTrip(t, event)
+	assert.Equal(t, jsonRoundTrip(t, event), converted)
+	assert.Nil(t, converted.PaymentID)
+	assert.Nil(t, converted.ExpiresAt)
+	assert.Nil(t, converted.Fees)
+	assert.Nil(t, converted.Metadata)
+
+	event.Metadata = map[string]interface{}{}
+	assert.Equal(t, map[string]interface{}{}, protoRoundTrip(t, event).Metadata)
+}
+
+func TestProtoRejectsUnknownEventTypes(t *testing.T) {
+	_, err := NewLedgerEvent("MYSTERY", usdAmount(1), "acc_1", "corr_1").ToProto()
+	assert.Error(t, err)
+
+	_, err = LedgerEventFromProto(&ledgerv1.LedgerEvent{Type: ledgerv1.EventType_EVENT_TYPE_UNSPECIFIED})
+	assert.Error(t, err)
+}
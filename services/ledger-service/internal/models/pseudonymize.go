@@ -0,0 +1,57 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// PIIMetadataKeys lists the metadata keys Pseudonymize strips, matched case-insensitively
+var PIIMetadataKeys = map[string]struct{}{
+	"name":          {},
+	"customer_name": {},
+	"email":         {},
+	"phone":         {},
+	"address":       {},
+	"ip_address":    {},
+	"date_of_birth": {},
+	"card_last4":    {},
+	"iban":          {},
+}
+
+// Pseudonymize returns a copy of the event for analytics exports. Account and payment IDs are
+// replaced by tokens derived with HMAC-SHA256 under salt, so the same ID always maps to the same
+// token for a given salt and exports can still group by account; use one salt per export.
+// Metadata keys in PIIMetadataKeys are dropped. Amounts, types and timestamps are preserved.
+// The copy no longer matches its signature or chain, so those fields are cleared.
+func (e *LedgerEvent) Pseudonymize(salt []byte) *LedgerEvent {
+	copied := *e
+	copied.AccountID = pseudonym(salt, "acct", e.AccountID)
+	if e.PaymentID != nil {
+		paymentID := pseudonym(salt, "pay", *e.PaymentID)
+		copied.PaymentID = &paymentID
+	}
+
+	copied.Metadata = make(map[string]interface{}, len(e.Metadata))
+	for key, value := range e.Metadata {
+		if _, pii := PIIMetadataKeys[strings.ToLower(key)]; !pii {
+			copied.Metadata[key] = value
+		}
+	}
+
+	copied.Signature = ""
+	copied.KeyID = ""
+	copied.PriorSignatures = nil
+	copied.Attestations = nil
+	copied.PreviousHash = ""
+	copied.HashLength = 0
+	return &copied
+}
+
+// pseudonym derives a stable token for value; the prefix keeps tokens of different ID kinds apart
+func pseudonym(salt []byte, prefix, value string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(prefix + ":" + value))
+	return prefix + "_" + hex.EncodeToString(mac.Sum(nil)[:16])
+}
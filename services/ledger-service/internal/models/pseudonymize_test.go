@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudonymizeIsStablePerAccount(t *testing.T) {
+	salt := []byte("export-2024-05")
+	first := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").
+		WithPaymentID("pay_1").
+		WithMetadata("email", "jane@example.com").
+		WithMetadata("channel", "card")
+	second := NewLedgerEvent(Debit, usdAmount(4), "acc_1", "corr_2")
+	other := NewLedgerEvent(Credit, usdAmount(10), "acc_2", "corr_3")
+	require.NoError(t, first.Sign("secret"))
+
+	a, b, c := first.Pseudonymize(salt), second.Pseudonymize(salt), other.Pseudonymize(salt)
+
+	assert.Equal(t, a.AccountID, b.AccountID)
+	assert.NotEqual(t, a.AccountID, c.AccountID)
+	assert.NotContains(t, a.AccountID, "acc_1")
+	assert.NotEqual(t, a.AccountID, first.Pseudonymize([]byte("other-export")).AccountID)
+
+	require.NotNil(t, a.PaymentID)
+	assert.NotEqual(t, "pay_1", *a.PaymentID)
+	assert.Equal(t, map[string]interface{}{"channel": "card"}, a.Metadata)
+	assert.Equal(t, first.Amount, a.Amount)
+	assert.Equal(t, first.Type, a.Type)
+	assert.Empty(t, a.Signature)
+
+	assert.Equal(t, "acc_1", first.AccountID)
+	assert.Contains(t, first.Metadata, "email")
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MetaOccurrence records the position of a recurring event in its schedule, counting the
+// anchor as occurrence 1
+const MetaOccurrence = "occurrence"
+
+// Frequency is the unit a RecurrenceRule repeats in
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+// RecurrenceRule describes a schedule such as a subscription's monthly charge. Occurrences are
+// computed from the anchor rather than from each other, so a charge anchored on the 31st falls
+// on the last day of shorter months and returns to the 31st afterwards, and one anchored on
+// Feb 29 falls on Feb 28 outside leap years.
+type RecurrenceRule struct {
+	Frequency Frequency
+	// Interval repeats every Interval units; zero means 1
+	Interval int
+	// Anchor is the first occurrence; its day of month and time of day carry over to the rest,
+	// in its location
+	Anchor time.Time
+	// Count limits the schedule to Count occurrences from the anchor; zero means no limit
+	Count int
+	// Until, if set, ends the schedule at its last occurrence at or before Until
+	Until *time.Time
+	// StickToMonthEnd keeps a schedule anchored on the last day of a month on the last day of
+	// every month, so one anchored on Apr 30 continues on May 31 rather than May 30
+	StickToMonthEnd bool
+}
+
+// occurrence returns the n-th occurrence after the anchor, the anchor itself being the 0th
+func (r RecurrenceRule) occurrence(n int) time.Time {
+	interval := r.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	switch r.Frequency {
+	case Daily:
+		return r.Anchor.AddDate(0, 0, n*interval)
+	case Weekly:
+		return r.Anchor.AddDate(0, 0, 7*n*interval)
+	case Monthly:
+		return r.addMonths(n * interval)
+	}
+	return r.addMonths(12 * n * interval)
+}
+
+// addMonths moves the anchor by months, clamping its day to the length of the target month
+func (r RecurrenceRule) addMonths(months int) time.Time {
+	year, month, day := r.Anchor.Date()
+	hour, minute, sec := r.Anchor.Clock()
+	loc := r.Anchor.Location()
+
+	target := time.Date(year, month+time.Month(months), 1, hour, minute, sec, r.Anchor.Nanosecond(), loc)
+	last := daysIn(target.Year(), target.Month(), loc)
+	if day > last || (r.StickToMonthEnd && day == daysIn(year, month, loc)) {
+		day = last
+	}
+	return target.AddDate(0, 0, day-1)
+}
+
+// daysIn returns the number of days in the month
+func daysIn(year int, month time.Month, loc *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+}
+
+// validate rejects rules that cannot produce a schedule
+func (r RecurrenceRule) validate() error {
+	if r.Frequency < Daily || r.Frequency > Yearly {
+		return fmt.Errorf("invalid recurrence frequency %d", r.Frequency)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("recurrence interval must not be negative")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count must not be negative")
+	}
+	if r.Anchor.IsZero() {
+		return fmt.Errorf("recurrence anchor is required")
+	}
+	return nil
+}
+
+// GenerateOccurrences returns the rule's occurrences in [from, to), in order
+func GenerateOccurrences(rule RecurrenceRule, from, to time.Time) ([]time.Time, error) {
+	indexes, err := rule.occurrencesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := make([]time.Time, len(indexes))
+	for i, n := range indexes {
+		occurrences[i] = rule.occurrence(n)
+	}
+	return occurrences, nil
+}
+
+// GenerateRecurringEvents builds an event of eventType for amount on the account at every
+// occurrence of the rule in [from, to). The events share subscriptionID as their correlation
+// and record their occurrence number; like installments, each takes effect at its occurrence
+// and is only valid from then on.
+func GenerateRecurringEvents(rule RecurrenceRule, eventType EventType, amount Money, accountID, subscriptionID string, from, to time.Time) ([]*LedgerEvent, error) {
+	indexes, err := rule.occurrencesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*LedgerEvent, 0, len(indexes))
+	for _, n := range indexes {
+		at := rule.occurrence(n).UTC()
+		event := NewLedgerEvent(eventType, amount, accountID, subscriptionID).
+			WithEffectiveAt(at).
+			WithValidity(&at, nil).
+			WithMetadata(MetaOccurrence, n+1)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// occurrencesBetween returns the indexes of the occurrences in [from, to), counting the anchor as 0
+func (r RecurrenceRule) occurrencesBetween(from, to time.Time) ([]int, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("occurrence window ends at %s before it starts at %s",
+			to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+
+	var indexes []int
+	for n := 0; r.Count == 0 || n < r.Count; n++ {
+		at := r.occurrence(n)
+		if !at.Before(to) || (r.Until != nil && at.After(*r.Until)) {
+			break
+		}
+		if !at.Before(from) {
+			indexes = append(indexes, n)
+		}
+	}
+	return indexes, nil
+}
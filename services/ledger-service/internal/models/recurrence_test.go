@@ -0,0 +1,81 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+}
+
+func rule31st() RecurrenceRule {
+	return RecurrenceRule{Frequency: Monthly, Anchor: day(2024, time.January, 31)}
+}
+
+func TestMonthlyRecurrenceAnchoredOn31st(t *testing.T) {
+	rule := rule31st()
+
+	occurrences, err := GenerateOccurrences(rule, day(2024, time.January, 1), day(2024, time.July, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		day(2024, time.January, 31),
+		day(2024, time.February, 29),
+		day(2024, time.March, 31),
+		day(2024, time.April, 30),
+		day(2024, time.May, 31),
+		day(2024, time.June, 30),
+	}, occurrences)
+
+	occurrences, err = GenerateOccurrences(rule, day(2025, time.February, 1), day(2025, time.March, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{day(2025, time.February, 28)}, occurrences)
+}
+
+func TestRecurrenceEdgeCases(t *testing.T) {
+	leap := RecurrenceRule{Frequency: Yearly, Anchor: day(2024, time.February, 29)}
+	occurrences, err := GenerateOccurrences(leap, day(2024, time.January, 1), day(2029, time.January, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		day(2024, time.February, 29),
+		day(2025, time.February, 28),
+		day(2026, time.February, 28),
+		day(2027, time.February, 28),
+		day(2028, time.February, 29),
+	}, occurrences)
+
+	monthEnd := RecurrenceRule{Frequency: Monthly, Anchor: day(2024, time.April, 30), StickToMonthEnd: true, Count: 3}
+	occurrences, err = GenerateOccurrences(monthEnd, day(2024, time.January, 1), day(2025, time.January, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{day(2024, time.April, 30), day(2024, time.May, 31), day(2024, time.June, 30)}, occurrences)
+
+	until := day(2024, time.March, 15)
+	biweekly := RecurrenceRule{Frequency: Weekly, Interval: 2, Anchor: day(2024, time.February, 2), Until: &until}
+	occurrences, err = GenerateOccurrences(biweekly, day(2024, time.February, 10), day(2025, time.January, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{day(2024, time.February, 16), day(2024, time.March, 1), day(2024, time.March, 15)}, occurrences)
+
+	_, err = GenerateOccurrences(RecurrenceRule{Frequency: Monthly}, until, until.AddDate(0, 1, 0))
+	assert.Error(t, err)
+	_, err = GenerateOccurrences(rule31st(), until, until)
+	assert.Error(t, err)
+}
+
+func TestGenerateRecurringEventsShareSubscription(t *testing.T) {
+	events, err := GenerateRecurringEvents(rule31st(), Debit, usdAmount(15), "acc_1", "sub_1",
+		day(2024, time.February, 1), day(2024, time.May, 1))
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	for i, event := range events {
+		require.NoError(t, event.Validate())
+		assert.Equal(t, "sub_1", event.CorrelationID)
+		assert.Equal(t, i+2, event.Metadata[MetaOccurrence])
+		assert.Equal(t, event.EffectiveTime(), *event.ValidFrom)
+	}
+	assert.Equal(t, day(2024, time.February, 29), events[0].EffectiveTime())
+	assert.Equal(t, day(2024, time.April, 30), events[2].EffectiveTime())
+}
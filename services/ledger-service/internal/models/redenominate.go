@@ -0,0 +1,95 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Metadata keys recorded on redenomination events
+const (
+	MetaRedenominationRate     = "redenominationRate"
+	MetaRedenominationResidual = "redenominationResidual"
+)
+
+// Redenominate converts every account's posted balance in the old currency into the new one at
+// a fixed rate. Each account with a non-zero balance gets a closing event that takes its
+// balance in from to zero and an opening event that posts the converted balance in to, rounded
+// to the new currency's precision with round. Both events record the rate and the rounding
+// residual, the exact converted amount minus the posted one in the new currency, so the value
+// lost or gained to rounding is documented per account. An account whose converted balance
+// rounds to zero gets only a closing event. Events are returned ordered by account and share
+// correlationID; they should be appended atomically.
+func Redenominate(events []*LedgerEvent, from, to string, rate Decimal, round RoundingMode, correlationID string) ([]*LedgerEvent, error) {
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("redenomination rate must be positive, got %s", rate)
+	}
+	if _, err := ZeroMoney(from); err != nil {
+		return nil, err
+	}
+	opening, err := ZeroMoney(to)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[AccountID]Decimal)
+	precisions := make(map[AccountID]int)
+	originals := ReversibleTypes(events)
+	for _, event := range events {
+		if !event.PostsBalance() || event.Amount.Currency != from {
+			continue
+		}
+		posted, err := PostedAmount(event, originals)
+		if err != nil {
+			return nil, err
+		}
+		balances[event.AccountID] = balances[event.AccountID].Add(DecimalFromMoney(posted))
+		if event.Amount.Precision > precisions[event.AccountID] {
+			precisions[event.AccountID] = event.Amount.Precision
+		}
+	}
+
+	accounts := make([]AccountID, 0, len(balances))
+	for account, balance := range balances {
+		if balance.Sign() != 0 {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Strings(accounts)
+
+	var migrated []*LedgerEvent
+	for _, account := range accounts {
+		balance := balances[account]
+		exact := balance.Mul(rate)
+		converted := exact.Round(opening.Precision, round)
+		residual := exact.Sub(converted)
+
+		// The balance is a sum of amounts at most precisions[account] places, so it is exact there
+		closingAmount, err := MoneyFromDecimal(balance.Abs(), from, precisions[account], RoundHalfEven)
+		if err != nil {
+			return nil, err
+		}
+		closing := NewLedgerEvent(Debit, closingAmount, account, correlationID)
+		if balance.Sign() < 0 {
+			closing.Type = Credit
+		}
+		migrated = append(migrated, closing.
+			WithMetadata(MetaRedenominationRate, rate.String()).
+			WithMetadata(MetaRedenominationResidual, residual.String()))
+
+		if converted.Sign() == 0 {
+			continue
+		}
+		openingAmount, err := MoneyFromDecimal(converted.Abs(), to, opening.Precision, round)
+		if err != nil {
+			return nil, err
+		}
+		open := NewLedgerEvent(Credit, openingAmount, account, correlationID)
+		if converted.Sign() < 0 {
+			open.Type = Debit
+		}
+		migrated = append(migrated, open.
+			WithMetadata(MetaRedenominationRate, rate.String()).
+			WithMetadata(MetaRedenominationResidual, residual.String()))
+	}
+	return migrated, nil
+}
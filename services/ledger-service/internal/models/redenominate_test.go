@@ -0,0 +1,88 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedenominateConvertsBalancesAtRate(t *testing.T) {
+	zwl := func(amount float64) Money { return NewMoney(int64(math.Round(amount*100)), "ZWL", 2) }
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, zwl(1500), "acc_1", "corr_1"),
+		NewLedgerEvent(Debit, zwl(265.44), "acc_1", "corr_2"),
+		NewLedgerEvent(Credit, zwl(7), "acc_2", "corr_3"),
+		NewLedgerEvent(Debit, zwl(2000), "acc_3", "corr_4"),
+		NewLedgerEvent(Credit, zwl(50), "acc_4", "corr_5"),
+		NewLedgerEvent(Debit, zwl(50), "acc_4", "corr_6"),
+		NewLedgerEvent(Credit, usdAmount(10), "acc_5", "corr_7"),
+	}
+	rate := NewDecimal(1, 3)
+
+	migrated, err := Redenominate(events, "ZWL", "USD", rate, RoundHalfEven, "redenom_1")
+	require.NoError(t, err)
+
+	all := append(append([]*LedgerEvent(nil), events...), migrated...)
+	balances := map[string]map[string]Decimal{}
+	for _, event := range all {
+		if balances[event.AccountID] == nil {
+			balances[event.AccountID] = map[string]Decimal{}
+		}
+		amount := DecimalFromMoney(event.Amount)
+		if event.IsDebit() {
+			amount = Decimal{}.Sub(amount)
+		}
+		balances[event.AccountID][event.Amount.Currency] = balances[event.AccountID][event.Amount.Currency].Add(amount)
+	}
+
+	// Old balances are closed, and new ones equal old × rate within half a cent
+	for account, pre := range map[string]string{"acc_1": "1234.56", "acc_2": "7", "acc_3": "-2000"} {
+		before, err := ParseDecimal(pre)
+		require.NoError(t, err)
+		assert.Zero(t, balances[account]["ZWL"].Sign(), account)
+
+		drift := balances[account]["USD"].Sub(before.Mul(rate)).Abs()
+		assert.LessOrEqual(t, drift.Cmp(NewDecimal(5, 3)), 0, account)
+	}
+	assert.Equal(t, "1.23", balances["acc_1"]["USD"].String())
+	assert.Equal(t, "0.01", balances["acc_2"]["USD"].String())
+	assert.Equal(t, "-2", balances["acc_3"]["USD"].String())
+
+	// acc_1 closing and opening, acc_2 closing and opening, acc_3 closing and opening; acc_4 is empty and acc_5 holds no ZWL
+	require.Len(t, migrated, 6)
+	assert.Equal(t, Debit, migrated[0].Type)
+	assert.Equal(t, Credit, migrated[1].Type)
+	assert.Equal(t, "0.00456", migrated[1].Metadata[MetaRedenominationResidual])
+	assert.Equal(t, "-0.003", migrated[3].Metadata[MetaRedenominationResidual])
+	assert.Equal(t, Credit, migrated[4].Type)
+	assert.Equal(t, Debit, migrated[5].Type)
+	for _, event := range migrated {
+		assert.Equal(t, "0.001", event.Metadata[MetaRedenominationRate])
+		assert.NoError(t, event.Validate())
+	}
+}
+
+func TestRedenominateAppliesReversals(t *testing.T) {
+	zwl := func(amount float64) Money { return NewMoney(int64(math.Round(amount*100)), "ZWL", 2) }
+	debit := NewLedgerEvent(Debit, zwl(400), "acc_1", "corr_2")
+	events := []*LedgerEvent{
+		NewLedgerEvent(Credit, zwl(1000), "acc_1", "corr_1"),
+		debit,
+		NewReversal(debit, "corr_3"),
+	}
+
+	migrated, err := Redenominate(events, "ZWL", "USD", NewDecimal(1, 3), RoundHalfEven, "redenom_1")
+	require.NoError(t, err)
+	require.Len(t, migrated, 2)
+	assert.Equal(t, zwl(1000), migrated[0].Amount, "the reversed debit is returned before closing")
+	assert.Equal(t, usdAmount(1), migrated[1].Amount)
+}
+
+func TestRedenominateRejectsInvalidInput(t *testing.T) {
+	_, err := Redenominate(nil, "ZWL", "USD", Decimal{}, RoundHalfEven, "redenom_1")
+	assert.Error(t, err)
+	_, err = Redenominate(nil, "ZWL", "XYZ", NewDecimal(1, 3), RoundHalfEven, "redenom_1")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
@@ -0,0 +1,81 @@
+package models
+
+// RefKind names how an event relates to the event it references
+type RefKind string
+
+const (
+	RefReverses RefKind = "REVERSES"
+	RefAmends   RefKind = "AMENDS"
+	RefCaptures RefKind = "CAPTURES"
+	RefDisputes RefKind = "DISPUTES"
+)
+
+// EventRef is a typed pointer from one event to another
+type EventRef struct {
+	Kind    RefKind `json:"kind"`
+	EventID string  `json:"eventId"`
+}
+
+// AddReference records that the event relates to eventID in the given way. The first reference
+// is the primary one: if ReferenceID is unset it is set to eventID, so readers that only know
+// ReferenceID keep seeing the primary target.
+func (e *LedgerEvent) AddReference(kind RefKind, eventID string) *LedgerEvent {
+	e.References = append(e.References, EventRef{Kind: kind, EventID: eventID})
+	if e.ReferenceID == nil {
+		e.ReferenceID = &eventID
+	}
+	return e
+}
+
+// ReferencesOfKind returns the IDs of the events referenced with the given kind, in the order added
+func (e *LedgerEvent) ReferencesOfKind(kind RefKind) []string {
+	var ids []string
+	for _, ref := range e.References {
+		if ref.Kind == kind {
+			ids = append(ids, ref.EventID)
+		}
+	}
+	return ids
+}
+
+// OrphanRef is a reference to an event that is not in the checked set
+type OrphanRef struct {
+	// EventID is the referencing event
+	EventID string `json:"eventId"`
+	// TargetID is the missing event
+	TargetID string `json:"targetId"`
+	// Kind is the typed reference kind, or empty for a plain ReferenceID
+	Kind RefKind `json:"kind,omitempty"`
+}
+
+// FindOrphanReferences reports the references, plain and typed, that point at events absent
+// from events, in event order. A ReferenceID that repeats a typed reference's target is
+// reported once, under its kind.
+func FindOrphanReferences(events []*LedgerEvent) []OrphanRef {
+	known := make(map[string]struct{}, len(events))
+	for _, event := range events {
+		known[event.ID] = struct{}{}
+	}
+
+	var orphans []OrphanRef
+	for _, event := range events {
+		typed := make(map[string]struct{}, len(event.References))
+		for _, ref := range event.References {
+			typed[ref.EventID] = struct{}{}
+		}
+
+		if event.ReferenceID != nil && *event.ReferenceID != "" {
+			target := *event.ReferenceID
+			_, isTyped := typed[target]
+			if _, ok := known[target]; !ok && !isTyped {
+				orphans = append(orphans, OrphanRef{EventID: event.ID, TargetID: target})
+			}
+		}
+		for _, ref := range event.References {
+			if _, ok := known[ref.EventID]; !ok {
+				orphans = append(orphans, OrphanRef{EventID: event.ID, TargetID: ref.EventID, Kind: ref.Kind})
+			}
+		}
+	}
+	return orphans
+}
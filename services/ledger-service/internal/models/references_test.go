@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventReferencesByKind(t *testing.T) {
+	event := NewLedgerEvent(Adjustment, usdAmount(5), "acc_1", "corr_1").
+		AddReference(RefReverses, "evt_a").
+		AddReference(RefDisputes, "evt_b").
+		AddReference(RefReverses, "evt_c")
+
+	assert.Equal(t, []string{"evt_a", "evt_c"}, event.ReferencesOfKind(RefReverses))
+	assert.Equal(t, []string{"evt_b"}, event.ReferencesOfKind(RefDisputes))
+	assert.Empty(t, event.ReferencesOfKind(RefCaptures))
+
+	require.NotNil(t, event.ReferenceID)
+	assert.Equal(t, "evt_a", *event.ReferenceID)
+}
+
+func TestEventReferencesAreSigned(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(5), "acc_1", "corr_1").AddReference(RefCaptures, "evt_hold")
+	require.NoError(t, event.Sign("secret"))
+
+	event.References[0].Kind = RefAmends
+	assert.False(t, event.Verify("secret"))
+}
+
+func TestFindOrphanReferences(t *testing.T) {
+	original := NewLedgerEvent(Debit, usdAmount(40), "acc_1", "corr_1")
+	capture := NewLedgerEvent(Credit, usdAmount(40), "acc_1", "corr_1").AddReference(RefCaptures, original.ID)
+	reversal := NewLedgerEvent(Reversal, usdAmount(25), "acc_1", "corr_2").AddReference(RefReverses, "evt_purged")
+	amendment := NewLedgerEvent(Adjustment, usdAmount(1), "acc_1", "corr_3").
+		WithReferenceID("evt_legacy").
+		AddReference(RefAmends, original.ID).
+		AddReference(RefDisputes, "evt_missing")
+
+	orphans := FindOrphanReferences([]*LedgerEvent{original, capture, reversal, amendment})
+	assert.Equal(t, []OrphanRef{
+		{EventID: reversal.ID, TargetID: "evt_purged", Kind: RefReverses},
+		{EventID: amendment.ID, TargetID: "evt_legacy"},
+		{EventID: amendment.ID, TargetID: "evt_missing", Kind: RefDisputes},
+	}, orphans)
+
+	assert.Empty(t, FindOrphanReferences([]*LedgerEvent{original, capture}))
+}
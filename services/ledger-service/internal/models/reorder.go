@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNonCommuting is returned when swapping two events could change the state they produce
+var ErrNonCommuting = errors.New("events do not commute")
+
+// ReorderAndRechain returns a corrected copy of a chained stream with the adjacent events at i
+// and j swapped. The swapped events exchange versions and are re-signed with signer, and every
+// event from the swap onwards is re-linked by PreviousHash; later events keep their signatures,
+// since PreviousHash is not signed. The input stream is not modified and stays available for
+// audit. Amounts are untouched, so the posted balance is unchanged. Control events such as
+// freezes change how later events are treated and are never swapped.
+func ReorderAndRechain(events []*LedgerEvent, i, j int, signer Signer) ([]*LedgerEvent, error) {
+	if i > j {
+		i, j = j, i
+	}
+	if i < 0 || j >= len(events) || j != i+1 {
+		return nil, fmt.Errorf("can only swap adjacent events within the stream, got %d and %d of %d", i, j, len(events))
+	}
+	if events[i].IsControl() || events[j].IsControl() {
+		return nil, fmt.Errorf("%w: %s and %s", ErrNonCommuting, events[i].Type, events[j].Type)
+	}
+
+	reordered := make([]*LedgerEvent, len(events))
+	copy(reordered, events[:i])
+
+	first, second := *events[j], *events[i]
+	first.Version, second.Version = events[i].Version, events[j].Version
+	for _, event := range []*LedgerEvent{&first, &second} {
+		if err := event.SignWith(signer); err != nil {
+			return nil, fmt.Errorf("failed to re-sign event %s: %w", event.ID, err)
+		}
+	}
+	reordered[i], reordered[j] = &first, &second
+
+	for k := j + 1; k < len(events); k++ {
+		copied := *events[k]
+		reordered[k] = &copied
+	}
+
+	previous := events[i].PreviousHash
+	for k := i; k < len(reordered); k++ {
+		reordered[k].PreviousHash = previous
+		previous = reordered[k].ComputeHash()
+	}
+	return reordered, nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainedStream(t *testing.T, key Signer, events ...*LedgerEvent) []*LedgerEvent {
+	t.Helper()
+	previous := ""
+	for i, event := range events {
+		event.WithVersion(int64(i + 1))
+		require.NoError(t, event.SignWith(key))
+		event.PreviousHash = previous
+		previous = event.ComputeHash()
+	}
+	return events
+}
+
+func TestReorderAndRechainSwapsCredits(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	stream := chainedStream(t, key,
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1"),
+		NewLedgerEvent(Credit, usdAmount(20), "acc_1", "corr_2"),
+		NewLedgerEvent(Credit, usdAmount(5), "acc_1", "corr_3"),
+		NewLedgerEvent(Debit, usdAmount(50), "acc_1", "corr_4"),
+	)
+	originalIDs := []string{stream[0].ID, stream[1].ID, stream[2].ID, stream[3].ID}
+	originalHead := stream[3].ComputeHash()
+
+	reordered, err := ReorderAndRechain(stream, 1, 2, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{originalIDs[0], originalIDs[2], originalIDs[1], originalIDs[3]},
+		[]string{reordered[0].ID, reordered[1].ID, reordered[2].ID, reordered[3].ID})
+	for i, event := range reordered {
+		assert.Equal(t, int64(i+1), event.Version)
+		assert.NoError(t, event.VerifyWith(key), event.ID)
+	}
+	assert.NoError(t, VerifyChainFrom("", reordered))
+	assert.NotEqual(t, originalHead, reordered[3].ComputeHash())
+
+	var before, after int64
+	for _, event := range stream {
+		before += signedAmount(event)
+	}
+	for _, event := range reordered {
+		after += signedAmount(event)
+	}
+	assert.Equal(t, before, after)
+
+	// The original stream is untouched and still verifies
+	assert.Equal(t, originalIDs[1], stream[1].ID)
+	assert.Equal(t, int64(2), stream[1].Version)
+	assert.NoError(t, VerifyChainFrom("", stream))
+}
+
+func TestReorderAndRechainRejections(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	stream := chainedStream(t, key,
+		NewLedgerEvent(Credit, usdAmount(100), "acc_1", "corr_1"),
+		NewAccountFreeze("acc_1", "corr_2", "review"),
+		NewLedgerEvent(Credit, usdAmount(5), "acc_1", "corr_3"),
+	)
+
+	_, err := ReorderAndRechain(stream, 0, 2, key)
+	assert.Error(t, err)
+	_, err = ReorderAndRechain(stream, 1, 2, key)
+	assert.ErrorIs(t, err, ErrNonCommuting)
+}
+
+func signedAmount(event *LedgerEvent) int64 {
+	if event.IsDebit() {
+		return -event.Amount.MinorUnits
+	}
+	return event.Amount.MinorUnits
+}
@@ -0,0 +1,40 @@
+package models
+
+import "fmt"
+
+// PriorSignature is a signature an event carried before it was re-signed under a new key
+type PriorSignature struct {
+	KeyID     string `json:"keyId"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Signature string `json:"signature"`
+}
+
+// Resign signs the event afresh with newSigner, keeping the current signature in the prior
+// signatures so verifiers still holding the old key keep working during a key migration.
+// Prior signatures are not part of the canonical form and are verified against the algorithm
+// they were issued under, so re-signing never invalidates them.
+func (e *LedgerEvent) Resign(newSigner Signer) error {
+	if e.Signature == "" {
+		return fmt.Errorf("cannot re-sign event %s: it is not signed", e.ID)
+	}
+	if e.KeyID == newSigner.KeyID() {
+		return fmt.Errorf("cannot re-sign event %s: already signed with key %q", e.ID, e.KeyID)
+	}
+
+	prior := PriorSignature{KeyID: e.KeyID, Algorithm: e.SignatureAlgorithm, Signature: e.Signature}
+	if err := e.SignWith(newSigner); err != nil {
+		return err
+	}
+	e.PriorSignatures = append(e.PriorSignatures, prior)
+	return nil
+}
+
+// priorSignature returns the prior signature recorded for keyID
+func (e *LedgerEvent) priorSignature(keyID string) (PriorSignature, bool) {
+	for _, prior := range e.PriorSignatures {
+		if prior.KeyID == keyID {
+			return prior, true
+		}
+	}
+	return PriorSignature{}, false
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResignVerifiesUnderOldAndNewKeys(t *testing.T) {
+	oldKey := NewHMACKey("ledger-2025", []byte("old-secret"))
+	newKey := NewHMACKey("ledger-2026", []byte("new-secret"))
+
+	event := NewLedgerEvent(Credit, NewMoney(1250, "USD", 2), "acc_1", "corr_1").
+		WithMetadata("channel", "ach")
+	require.NoError(t, event.SignWith(oldKey))
+	original := event.Signature
+
+	require.NoError(t, event.Resign(newKey))
+
+	assert.Equal(t, "ledger-2026", event.KeyID)
+	require.Len(t, event.PriorSignatures, 1)
+	assert.Equal(t, PriorSignature{KeyID: "ledger-2025", Algorithm: withTimestampNanos(AlgorithmHMACSHA256), Signature: original}, event.PriorSignatures[0])
+	assert.NoError(t, event.VerifyWith(newKey))
+	assert.NoError(t, event.VerifyWith(oldKey))
+
+	reloaded, err := event.ToJSON()
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(reloaded)
+	require.NoError(t, err)
+	assert.NoError(t, decoded.VerifyWith(oldKey))
+	assert.NoError(t, decoded.VerifyWith(newKey))
+}
+
+func TestResignRejectsTamperingUnderEitherKey(t *testing.T) {
+	oldKey := NewHMACKey("ledger-2025", []byte("old-secret"))
+	newKey := NewHMACKey("ledger-2026", []byte("new-secret"))
+
+	event := NewLedgerEvent(Credit, NewMoney(1250, "USD", 2), "acc_1", "corr_1")
+	require.NoError(t, event.SignWith(oldKey))
+	require.NoError(t, event.Resign(newKey))
+
+	event.Amount.MinorUnits = 125000
+	assert.ErrorIs(t, event.VerifyWith(oldKey), ErrInvalidSignature)
+	assert.ErrorIs(t, event.VerifyWith(newKey), ErrInvalidSignature)
+	assert.ErrorIs(t, event.VerifyWith(NewHMACKey("other", []byte("x"))), ErrInvalidSignature)
+}
+
+func TestResignRequiresSignedEvent(t *testing.T) {
+	event := NewLedgerEvent(Credit, NewMoney(100, "USD", 2), "acc_1", "corr_1")
+	assert.Error(t, event.Resign(NewHMACKey("ledger-2026", []byte("new-secret"))))
+}
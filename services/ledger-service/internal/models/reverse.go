@@ -0,0 +1,237 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var (
+	// ErrInvalidFXMetadata is returned when an FX event's recorded source amount is missing or malformed
+	ErrInvalidFXMetadata = errors.New("invalid FX metadata")
+	// ErrReversalMismatch is returned when a reversal does not undo its original exactly
+	ErrReversalMismatch = errors.New("reversal does not match original")
+	// ErrAlreadyReversed is returned when an event that has already been reversed is reversed again
+	ErrAlreadyReversed = errors.New("event already reversed")
+	// ErrUnknownReversedType is returned when a reversal's effect is needed but the type of the
+	// event it reverses is neither recorded on it nor known from its original
+	ErrUnknownReversedType = errors.New("unknown reversed event type")
+)
+
+// MetaReversedType records the type of the event a reversal undoes, so the reversal's effect is
+// known to be the opposite of that type's without reading the original
+const MetaReversedType = "reversedType"
+
+// Metadata keys recording the pre-conversion side of an FX event
+const (
+	MetaFXSourceAmount   = "fxSourceAmount"
+	MetaFXSourceCurrency = "fxSourceCurrency"
+	MetaFXRate           = "fxRate"
+)
+
+// WithFXSource records that the event's amount was converted from source at rate. The source
+// amount is recorded as an exact decimal string.
+func (e *LedgerEvent) WithFXSource(source Money, rate float64) *LedgerEvent {
+	return e.WithMetadata(MetaFXSourceAmount, source.decimal()).
+		WithMetadata(MetaFXSourceCurrency, source.Currency).
+		WithMetadata(MetaFXRate, rate)
+}
+
+// IsFX returns true if the event records an FX source amount
+func (e *LedgerEvent) IsFX() bool {
+	_, hasAmount := e.Metadata[MetaFXSourceAmount]
+	_, hasCurrency := e.Metadata[MetaFXSourceCurrency]
+	return hasAmount || hasCurrency
+}
+
+// FXSource returns the pre-conversion amount recorded on an FX event
+func (e *LedgerEvent) FXSource() (Money, error) {
+	code, ok := e.Metadata[MetaFXSourceCurrency].(string)
+	if !ok || code == "" {
+		return Money{}, fmt.Errorf("%w: event %s has no source currency", ErrInvalidFXMetadata, e.ID)
+	}
+	source, err := ZeroMoney(code)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: event %s: %v", ErrInvalidFXMetadata, e.ID, err)
+	}
+
+	var raw string
+	switch amount := e.Metadata[MetaFXSourceAmount].(type) {
+	case float64:
+		raw = strconv.FormatFloat(amount, 'f', -1, 64)
+	case string:
+		raw = amount
+	default:
+		return Money{}, fmt.Errorf("%w: event %s has no source amount", ErrInvalidFXMetadata, e.ID)
+	}
+	value, err := ParseDecimal(raw)
+	if err == nil {
+		source, err = MoneyFromDecimal(value, code, source.Precision, RoundHalfUp)
+	}
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: event %s source amount %q", ErrInvalidFXMetadata, e.ID, raw)
+	}
+	if source.Sign() <= 0 {
+		return Money{}, fmt.Errorf("%w: event %s source amount must be positive", ErrInvalidFXMetadata, e.ID)
+	}
+	return source, nil
+}
+
+// Reverse creates a reversal of the event on the same account, referencing it. An FX event is
+// reversed in its original currency and amount, read from its FX metadata, so the reversal
+// undoes what the customer paid rather than the converted amount.
+func (e *LedgerEvent) Reverse(correlationID string) (*LedgerEvent, error) {
+	amount := e.Amount
+	if e.IsFX() {
+		source, err := e.FXSource()
+		if err != nil {
+			return nil, err
+		}
+		amount = source
+	}
+
+	reversal := NewReversal(e, correlationID)
+	reversal.Amount, reversal.Currency = amount, amount.Currency
+	return reversal, nil
+}
+
+// NewReversal creates a reversal undoing original: a Reversal event on the same account for
+// the same amount and currency, linked to it with WithReverses and recording its type, so a
+// reversed debit is a credit back and a reversed credit a debit back. The reversal amount must
+// equal the original's, see ValidateReversal; use Reverse for FX events, which are reversed in
+// their source currency.
+func NewReversal(original *LedgerEvent, correlationID string) *LedgerEvent {
+	reversal := NewLedgerEvent(Reversal, original.Amount, original.AccountID, correlationID).
+		WithReverses(original.ID).
+		WithMetadata(MetaReversedType, string(original.Type))
+	reversal.PaymentID = original.PaymentID
+	reversal.TenantID = original.TenantID
+	return reversal
+}
+
+// WithReverses links a reversal to the event it reverses, also recording the link as a typed
+// RefReverses reference
+func (e *LedgerEvent) WithReverses(id string) *LedgerEvent {
+	e.ReversesEventID = &id
+	return e.AddReference(RefReverses, id)
+}
+
+// ReversedEventID returns the ID of the event a reversal reverses: its ReversesEventID, or
+// for reversals recorded before that field, their RefReverses reference. It is empty for an
+// unlinked event.
+func (e *LedgerEvent) ReversedEventID() string {
+	if e.ReversesEventID != nil {
+		return *e.ReversesEventID
+	}
+	if ids := e.ReferencesOfKind(RefReverses); len(ids) > 0 && e.IsReversal() {
+		return ids[0]
+	}
+	return ""
+}
+
+// ReversedType returns the type of the event a reversal undoes as recorded by NewReversal, or
+// an empty type if it was not recorded
+func (e *LedgerEvent) ReversedType() EventType {
+	reversedType, _ := e.Metadata[MetaReversedType].(string)
+	return EventType(reversedType)
+}
+
+// reversedTypeIn returns the type of the event a reversal undoes from originals, which maps
+// event IDs to types, falling back to the type recorded on the reversal
+func (e *LedgerEvent) reversedTypeIn(originals map[string]EventType) EventType {
+	if reversedType, ok := originals[e.ReversedEventID()]; ok {
+		return reversedType
+	}
+	return e.ReversedType()
+}
+
+// ReversibleTypes maps the ID of each event in events that a reversal can undo to its type
+func ReversibleTypes(events []*LedgerEvent) map[string]EventType {
+	types := make(map[string]EventType)
+	for _, event := range events {
+		if event.AffectsBalance() || event.AffectsHolds() {
+			types[event.ID] = event.Type
+		}
+	}
+	return types
+}
+
+// PostedAmount returns the signed amount event moves the posted balance by: credits and
+// adjustments add their amount and debits subtract it. A reversal does the opposite of the
+// event it reverses, whose type is looked up in originals, see ReversibleTypes, before the
+// type recorded on the reversal; it returns ErrUnknownReversedType when neither has it.
+// Events that post nothing, including reversals of holds and releases, return zero in the
+// event's currency.
+func PostedAmount(event *LedgerEvent, originals map[string]EventType) (Money, error) {
+	postedType, amount := event.Type, event.Amount
+	if event.IsReversal() {
+		postedType = event.reversedTypeIn(originals)
+		if postedType == "" {
+			return Money{}, fmt.Errorf("%w: reversal %s of %s", ErrUnknownReversedType, event.ID, event.ReversedEventID())
+		}
+		negated, err := amount.Neg()
+		if err != nil {
+			return Money{}, err
+		}
+		amount = negated
+	}
+
+	switch postedType {
+	case Credit, Adjustment:
+		return amount, nil
+	case Debit:
+		return amount.Neg()
+	}
+	amount.MinorUnits = 0
+	return amount, nil
+}
+
+// checkReversalLink requires reversals to name the event they reverse and other events not to
+func (e *LedgerEvent) checkReversalLink() error {
+	if !e.IsReversal() {
+		if e.ReversesEventID != nil {
+			return fmt.Errorf("%s event must not reverse another event", e.Type)
+		}
+		return nil
+	}
+	if e.ReversedEventID() == "" {
+		return fmt.Errorf("reversal must name the event it reverses")
+	}
+	return nil
+}
+
+// ValidateReversal checks that reversal undoes original exactly: it is linked to original, on
+// its account, and for the same amount, or for an FX original its source amount. It returns
+// ErrAlreadyReversed if stream, the events recorded so far, already holds a reversal of
+// original, and rejects reversing a reversal.
+func ValidateReversal(original, reversal *LedgerEvent, stream []*LedgerEvent) error {
+	if !reversal.IsReversal() || reversal.ReversedEventID() != original.ID {
+		return fmt.Errorf("%w: %s does not reverse %s", ErrReversalMismatch, reversal.ID, original.ID)
+	}
+	if original.IsReversal() || original.IsControl() {
+		return fmt.Errorf("%w: %s event %s cannot be reversed", ErrReversalMismatch, original.Type, original.ID)
+	}
+	if reversal.AccountID != original.AccountID {
+		return fmt.Errorf("%w: reversal on account %s, original on %s", ErrReversalMismatch, reversal.AccountID, original.AccountID)
+	}
+
+	expected := original.Amount
+	if original.IsFX() {
+		source, err := original.FXSource()
+		if err != nil {
+			return err
+		}
+		expected = source
+	}
+	if !reversal.Amount.Equal(expected) {
+		return fmt.Errorf("%w: reversal of %s %s, original %s %s", ErrReversalMismatch,
+			reversal.Amount.decimal(), reversal.Amount.Currency, expected.decimal(), expected.Currency)
+	}
+
+	for _, event := range stream {
+		if event.ID != reversal.ID && event.IsReversal() && event.ReversedEventID() == original.ID {
+			return fmt.Errorf("%w: %s by %s", ErrAlreadyReversed, original.ID, event.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseFXReturnsOriginalCurrency(t *testing.T) {
+	converted := NewLedgerEvent(Debit, NewMoney(9250, "EUR", 2), "acc_1", "corr_fx").
+		WithFXSource(usdAmount(100), 0.925)
+
+	// Metadata survives a round trip through JSON as float64
+	payload, err := converted.ToJSON()
+	require.NoError(t, err)
+	stored, err := LedgerEventFromJSON(payload)
+	require.NoError(t, err)
+
+	reversal, err := stored.Reverse("corr_rev")
+	require.NoError(t, err)
+	assert.Equal(t, Reversal, reversal.Type)
+	assert.Equal(t, usdAmount(100), reversal.Amount)
+	assert.Equal(t, "USD", reversal.Currency)
+	assert.Equal(t, []string{converted.ID}, reversal.ReferencesOfKind(RefReverses))
+	assert.NoError(t, reversal.Validate())
+
+	plain, err := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").Reverse("corr_rev")
+	require.NoError(t, err)
+	assert.Equal(t, usdAmount(10), plain.Amount)
+}
+
+func TestReverseFXRequiresSourceMetadata(t *testing.T) {
+	event := NewLedgerEvent(Debit, NewMoney(9250, "EUR", 2), "acc_1", "corr_fx").
+		WithMetadata(MetaFXSourceCurrency, "USD")
+
+	_, err := event.Reverse("corr_rev")
+	assert.ErrorIs(t, err, ErrInvalidFXMetadata)
+
+	event.WithMetadata(MetaFXSourceAmount, "100.00").WithMetadata(MetaFXSourceCurrency, "XYZ")
+	_, err = event.Reverse("corr_rev")
+	assert.ErrorIs(t, err, ErrInvalidFXMetadata)
+}
+
+func TestNewReversalLinksOriginal(t *testing.T) {
+	original := NewLedgerEvent(Debit, usdAmount(40), "acc_1", "corr_1").WithPaymentID("pay_1")
+	reversal := NewReversal(original, "corr_rev")
+
+	require.NoError(t, reversal.Validate())
+	require.NotNil(t, reversal.ReversesEventID)
+	assert.Equal(t, original.ID, *reversal.ReversesEventID)
+	assert.Equal(t, []string{original.ID}, reversal.ReferencesOfKind(RefReverses))
+	assert.Equal(t, string(Debit), reversal.Metadata[MetaReversedType])
+	assert.Equal(t, original.Amount, reversal.Amount)
+	assert.Equal(t, "pay_1", *reversal.PaymentID)
+	assert.NoError(t, ValidateReversal(original, reversal, []*LedgerEvent{original}))
+
+	canonical, err := reversal.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Contains(t, string(canonical), `"reversesEventId":"`+original.ID+`"`)
+}
+
+func TestValidateRequiresReversalLink(t *testing.T) {
+	unlinked := NewLedgerEvent(Reversal, usdAmount(40), "acc_1", "corr_1")
+	assert.Error(t, unlinked.Validate())
+
+	legacy := NewLedgerEvent(Reversal, usdAmount(40), "acc_1", "corr_1").AddReference(RefReverses, "evt_1")
+	assert.NoError(t, legacy.Validate(), "reversals linked by reference before ReversesEventID still validate")
+	assert.Equal(t, "evt_1", legacy.ReversedEventID())
+
+	credit := NewLedgerEvent(Credit, usdAmount(40), "acc_1", "corr_1").WithReverses("evt_1")
+	assert.Error(t, credit.Validate())
+}
+
+func TestValidateReversalRejectsMismatchAndDoubleReversal(t *testing.T) {
+	original := NewLedgerEvent(Credit, usdAmount(40), "acc_1", "corr_1")
+	first := NewReversal(original, "corr_rev")
+
+	partial := NewReversal(original, "corr_rev")
+	partial.Amount = usdAmount(30)
+	assert.ErrorIs(t, ValidateReversal(original, partial, nil), ErrReversalMismatch)
+
+	assert.ErrorIs(t, ValidateReversal(first, NewReversal(first, "corr_rev"), nil), ErrReversalMismatch,
+		"a reversal cannot itself be reversed")
+
+	second := NewReversal(original, "corr_rev_2")
+	assert.ErrorIs(t, ValidateReversal(original, second, []*LedgerEvent{original, first}), ErrAlreadyReversed)
+}
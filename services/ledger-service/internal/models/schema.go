@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CurrentSchemaVersion is the shape of the JSON form ToJSON writes, recorded in its top-level
+// schemaVersion field. Payloads without the field are version 1.
+const CurrentSchemaVersion = 2
+
+// ErrUnsupportedSchemaVersion is returned when decoding a payload of a schema version this
+// build has no migration path from, such as one written by a newer release
+var ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+
+// schemaVersionKey is the top-level JSON field holding the schema version
+const schemaVersionKey = "schemaVersion"
+
+// eventFields is a JSON event payload by top-level field, as the migrations rewrite it
+type eventFields map[string]json.RawMessage
+
+// schemaMigration upgrades a payload from one schema version to the next
+type schemaMigration func(fields eventFields) error
+
+// schemaMigrations maps each version below CurrentSchemaVersion to the step upgrading it
+var schemaMigrations = map[int]schemaMigration{
+	1: migrateSchemaV1,
+}
+
+// versionedEvent is the JSON form of an event, led by its schema version
+type versionedEvent struct {
+	SchemaVersion int `json:"schemaVersion"`
+	*LedgerEvent
+}
+
+// migrateSchema upgrades a payload in place from its recorded schema version to
+// CurrentSchemaVersion, one step at a time
+func migrateSchema(fields eventFields) error {
+	version := 1
+	if raw, ok := fields[schemaVersionKey]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return fmt.Errorf("%w: %s", ErrUnsupportedSchemaVersion, raw)
+		}
+	}
+	if version < 1 || version > CurrentSchemaVersion {
+		return fmt.Errorf("%w: %d, expected 1 to %d", ErrUnsupportedSchemaVersion, version, CurrentSchemaVersion)
+	}
+
+	for ; version < CurrentSchemaVersion; version++ {
+		if err := schemaMigrations[version](fields); err != nil {
+			return fmt.Errorf("failed to migrate event from schema version %d: %w", version, err)
+		}
+	}
+	delete(fields, schemaVersionKey)
+	return nil
+}
+
+// migrateSchemaV1 upgrades a version 1 payload. Version 1 recorded the amount as a bare float
+// beside the currency, and signed with the legacy shared-secret scheme without naming it.
+// Unversioned payloads in the current shape were written before the version was recorded, so
+// fields already in the version 2 shape are left as they are.
+func migrateSchemaV1(fields eventFields) error {
+	if amount, ok := fields["amount"]; ok && isJSONNumber(amount) {
+		legacy, err := json.Marshal(moneyJSON{Amount: json.Number(amount), Currency: stringField(fields, "currency")})
+		if err != nil {
+			return err
+		}
+		var money Money
+		if err := json.Unmarshal(legacy, &money); err != nil {
+			return fmt.Errorf("invalid legacy amount %s: %w", amount, err)
+		}
+		if fields["amount"], err = json.Marshal(money); err != nil {
+			return err
+		}
+	}
+
+	if stringField(fields, "signature") != "" && stringField(fields, "signatureAlgorithm") == "" {
+		fields["signatureAlgorithm"] = json.RawMessage(`"` + AlgorithmSHA256Shared + `"`)
+	}
+	return nil
+}
+
+// isJSONNumber reports whether raw is a JSON number rather than an object, string or literal
+func isJSONNumber(raw json.RawMessage) bool {
+	var number json.Number
+	return json.Unmarshal(raw, &number) == nil && len(raw) > 0 && raw[0] != '"'
+}
+
+// stringField returns the string field key, or "" if it is absent or not a string; a field of
+// the wrong type is left for the final decode to report
+func stringField(fields eventFields, key string) string {
+	var value string
+	if raw, ok := fields[key]; ok && json.Unmarshal(raw, &value) != nil {
+		return ""
+	}
+	return value
+}
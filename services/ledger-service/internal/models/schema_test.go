@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyPayload rewrites the event's JSON into the version 1 shape: no schema version or
+// signature algorithm, and the amount as a bare float
+func legacyPayload(t *testing.T, event *LedgerEvent) []byte {
+	t.Helper()
+	encoded, err := event.ToJSON()
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	delete(fields, "schemaVersion")
+	delete(fields, "signatureAlgorithm")
+	fields["amount"] = event.Amount.Float()
+	legacy, err := json.Marshal(fields)
+	require.NoError(t, err)
+	return legacy
+}
+
+func TestToJSONRecordsSchemaVersion(t *testing.T) {
+	encoded, err := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").ToJSON()
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	assert.Equal(t, float64(CurrentSchemaVersion), fields["schemaVersion"])
+}
+
+func TestLedgerEventFromJSONMigratesVersion1(t *testing.T) {
+	event := NewLedgerEvent(Credit, NewMoney(1250, "USD", 2), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, event.Sign("secret", WithTimestampPrecision(SecondTimestamps)))
+
+	decoded, err := LedgerEventFromJSON(legacyPayload(t, event))
+	require.NoError(t, err)
+	assert.Equal(t, NewMoney(1250, "USD", 2), decoded.Amount)
+	assert.Equal(t, AlgorithmSHA256Shared, decoded.SignatureAlgorithm)
+	assert.True(t, decoded.Verify("secret"))
+	assert.Equal(t, jsonRoundTrip(t, event), decoded)
+}
+
+func TestMigrateSchemaV1(t *testing.T) {
+	fields := eventFields{
+		"amount":    json.RawMessage(`12.5`),
+		"currency":  json.RawMessage(`"JPY"`),
+		"signature": json.RawMessage(`"abc"`),
+	}
+	require.NoError(t, migrateSchemaV1(fields))
+	var amount Money
+	require.NoError(t, json.Unmarshal(fields["amount"], &amount))
+	assert.Equal(t, NewMoney(125, "JPY", 1), amount)
+	assert.JSONEq(t, `"sha256-shared"`, string(fields["signatureAlgorithm"]))
+
+	// Unversioned payloads already in the current shape pass through unchanged
+	current := eventFields{
+		"amount":             json.RawMessage(`{"amount":12.5,"currency":"USD","precision":2}`),
+		"signature":          json.RawMessage(`"abc"`),
+		"signatureAlgorithm": json.RawMessage(`"ed25519"`),
+	}
+	require.NoError(t, migrateSchemaV1(current))
+	assert.JSONEq(t, `{"amount":12.5,"currency":"USD","precision":2}`, string(current["amount"]))
+	assert.JSONEq(t, `"ed25519"`, string(current["signatureAlgorithm"]))
+
+	unsigned := eventFields{"amount": json.RawMessage(`3`), "currency": json.RawMessage(`"USD"`)}
+	require.NoError(t, migrateSchemaV1(unsigned))
+	assert.NotContains(t, unsigned, "signatureAlgorithm")
+
+	assert.Error(t, migrateSchemaV1(eventFields{"amount": json.RawMessage(`1e400`)}))
+}
+
+func TestMigrationsCoverEveryOlderVersion(t *testing.T) {
+	for version := 1; version < CurrentSchemaVersion; version++ {
+		assert.Contains(t, schemaMigrations, version)
+	}
+}
+
+func TestLedgerEventFromJSONRejectsUnknownSchemaVersions(t *testing.T) {
+	for _, payload := range []string{
+		`{"schemaVersion":3,"id":"evt_1","amount":{"amount":1,"currency":"USD"}}`,
+		`{"schemaVersion":0,"id":"evt_1"}`,
+		`{"schemaVersion":"2","id":"evt_1"}`,
+	} {
+		event, err := LedgerEventFromJSON([]byte(payload))
+		assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion, payload)
+		assert.Nil(t, event)
+	}
+}
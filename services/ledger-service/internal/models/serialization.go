@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NilMode controls how nil optional pointer fields are serialized
+type NilMode int
+
+const (
+	// OmitNil leaves nil optional fields out of the JSON, matching ToJSON
+	OmitNil NilMode = iota
+	// EmitNull writes nil optional fields as null, for consumers that expect every key
+	EmitNull
+)
+
+// ToJSONWith serializes the event like ToJSON, writing nil optional pointer fields (paymentId,
+// referenceId, expiresAt, fees and so on) according to mode. With EmitNull the keys are
+// written in sorted order.
+func (e *LedgerEvent) ToJSONWith(mode NilMode) ([]byte, error) {
+	encoded, err := e.ToJSON()
+	if err != nil || mode == OmitNil {
+		return encoded, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to re-encode ledger event: %w", err)
+	}
+	for _, key := range nilPointerKeys(reflect.ValueOf(e).Elem()) {
+		fields[key] = json.RawMessage("null")
+	}
+	return json.Marshal(fields)
+}
+
+// nilPointerKeys returns the JSON keys of the struct's nil pointer fields
+func nilPointerKeys(v reflect.Value) []string {
+	var keys []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() != reflect.Pointer || !v.Field(i).IsNil() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// ToJSONWithMetadataFilter serializes the event like ToJSON with only the allowed metadata keys;
+// the other keys are dropped and every core field is left intact. The signature still covers
+// the full event and does not verify over the filtered view; publish FilteredCommitment
+// alongside it for consumers that need to check the view they received.
+func (e *LedgerEvent) ToJSONWithMetadataFilter(allowed []string) ([]byte, error) {
+	return e.withMetadataFilter(allowed).ToJSON()
+}
+
+// FilteredCommitment returns the hex SHA-256 of the canonical bytes of the event as
+// ToJSONWithMetadataFilter publishes it. A consumer decoding the filtered JSON gets the same
+// commitment from the decoded event with the same allowed keys.
+func (e *LedgerEvent) FilteredCommitment(allowed []string) (string, error) {
+	hash, err := e.withMetadataFilter(allowed).hash()
+	if err != nil {
+		return "", fmt.Errorf("failed to hash filtered event: %w", err)
+	}
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// withMetadataFilter returns a copy of the event keeping only the allowed metadata keys
+func (e *LedgerEvent) withMetadataFilter(allowed []string) *LedgerEvent {
+	filtered := *e
+	filtered.Metadata = make(map[string]interface{}, len(allowed))
+	for _, key := range allowed {
+		if value, ok := e.Metadata[key]; ok {
+			filtered.Metadata[key] = value
+		}
+	}
+	return &filtered
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJSONWithNilModes(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").WithReferenceID("ref_1")
+
+	omitted, err := event.ToJSONWith(OmitNil)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(omitted, &fields))
+	assert.NotContains(t, fields, "paymentId")
+	assert.Equal(t, "ref_1", fields["referenceId"])
+
+	emitted, err := event.ToJSONWith(EmitNull)
+	require.NoError(t, err)
+	fields = nil
+	require.NoError(t, json.Unmarshal(emitted, &fields))
+	require.Contains(t, fields, "paymentId")
+	assert.Nil(t, fields["paymentId"])
+	for _, key := range []string{"expiresAt", "effectiveAt", "validFrom", "validUntil", "fees"} {
+		assert.Contains(t, fields, key)
+		assert.Nil(t, fields[key], key)
+	}
+	assert.Equal(t, "ref_1", fields["referenceId"])
+
+	decoded, err := LedgerEventFromJSON(emitted)
+	require.NoError(t, err)
+	assert.Nil(t, decoded.PaymentID)
+	assert.Equal(t, event.ID, decoded.ID)
+}
+
+func TestToJSONWithMetadataFilter(t *testing.T) {
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1").
+		WithMetadata("merchant", "m_1").
+		WithMetadata("email", "jane@example.com").
+		WithMetadata("channel", "web")
+	require.NoError(t, event.Sign("secret"))
+	allowed := []string{"merchant", "channel", "absent"}
+
+	encoded, err := event.ToJSONWithMetadataFilter(allowed)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	assert.Equal(t, map[string]interface{}{"merchant": "m_1", "channel": "web"}, fields["metadata"])
+	assert.Equal(t, "acc_1", fields["accountId"])
+	assert.Equal(t, event.Signature, fields["signature"])
+	assert.Len(t, event.Metadata, 3, "the event itself must be left alone")
+
+	commitment, err := event.FilteredCommitment(allowed)
+	require.NoError(t, err)
+	decoded, err := LedgerEventFromJSON(encoded)
+	require.NoError(t, err)
+	recomputed, err := decoded.FilteredCommitment(allowed)
+	require.NoError(t, err)
+	assert.Equal(t, commitment, recomputed)
+
+	decoded.Metadata["merchant"] = "m_2"
+	tampered, err := decoded.FilteredCommitment(allowed)
+	require.NoError(t, err)
+	assert.NotEqual(t, commitment, tampered)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Golden signature vectors: events as stored in production, with the signatures they were
+// issued under fixed keys. If this test fails, a change to the canonical form has made
+// existing events unverifiable. Do not regenerate the fixture to make it pass; keep the old
+// canonical form reachable for events signed under it instead.
+const (
+	goldenLegacyKey = "golden-key"
+	goldenKeyID     = "golden-2024"
+	goldenSecret    = "golden-secret"
+)
+
+type signatureVector struct {
+	Name            string          `json:"name"`
+	Event           json.RawMessage `json:"event"`
+	LegacySignature string          `json:"legacySignature"`
+	HMACSignature   string          `json:"hmacSignature"`
+}
+
+func TestGoldenSignatureVectors(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/signature_vectors.json")
+	require.NoError(t, err)
+	var vectors []signatureVector
+	require.NoError(t, json.Unmarshal(fixture, &vectors))
+	require.NotEmpty(t, vectors)
+
+	key := NewHMACKey(goldenKeyID, []byte(goldenSecret))
+	for _, vector := range vectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			event, err := LedgerEventFromJSON(vector.Event)
+			require.NoError(t, err)
+
+			event.Signature = vector.LegacySignature
+			assert.True(t, event.Verify(goldenLegacyKey), "legacy signature no longer verifies")
+
+			event.Signature, event.KeyID = vector.HMACSignature, goldenKeyID
+			assert.NoError(t, event.VerifyWith(key), "HMAC signature no longer verifies")
+
+			require.NoError(t, event.Sign(goldenLegacyKey, WithTimestampPrecision(SecondTimestamps)))
+			assert.Equal(t, vector.LegacySignature, event.Signature, "re-signing produced a different signature")
+		})
+	}
+}
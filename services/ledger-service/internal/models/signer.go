@@ -0,0 +1,153 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidSignature is returned when a signature does not verify
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrUnknownKey is returned by a KeyProvider for a key ID it has no verifier for
+	ErrUnknownKey = errors.New("unknown key")
+	// ErrCrossTenant is returned when an event is verified on behalf of a tenant other than its own
+	ErrCrossTenant = errors.New("cross-tenant verification")
+)
+
+// Signing algorithm identifiers
+const (
+	AlgorithmHMACSHA256   = "hmac-sha256"
+	AlgorithmEd25519      = "ed25519"
+	AlgorithmSHA256Shared = "sha256-shared"
+)
+
+// timestampNanosSuffix marks a recorded signature algorithm whose canonical bytes carry the
+// event timestamp at nanosecond precision, so sub-second ordering is covered by the signature.
+// Signing methods record it unless WithTimestampPrecision selects seconds; algorithms recorded
+// without it cover the timestamp in Unix seconds and keep verifying as they were signed.
+const timestampNanosSuffix = "+ts-nano"
+
+// withTimestampNanos returns the recorded form of algorithm for nanosecond timestamps
+func withTimestampNanos(algorithm string) string {
+	return algorithm + timestampNanosSuffix
+}
+
+// splitAlgorithm returns the signing algorithm of a recorded algorithm and whether its
+// canonical bytes carry nanosecond timestamps
+func splitAlgorithm(recorded string) (algorithm string, nanos bool) {
+	algorithm = strings.TrimSuffix(recorded, timestampNanosSuffix)
+	return algorithm, algorithm != recorded
+}
+
+// Signer signs payloads with a key identified by KeyID
+type Signer interface {
+	KeyID() string
+	Algorithm() string
+	Sign(payload []byte) ([]byte, error)
+}
+
+// Verifier verifies signatures produced by the Signer with the same KeyID
+type Verifier interface {
+	KeyID() string
+	Verify(payload, signature []byte) error
+}
+
+// KeyProvider resolves the verifier for a key ID within a tenant. Single-tenant deployments
+// use the empty tenant ID.
+type KeyProvider interface {
+	Verifier(tenantID, keyID string) (Verifier, error)
+}
+
+// StaticKeys is a KeyProvider over a fixed set of verifiers for the default, empty, tenant
+type StaticKeys map[string]Verifier
+
+// NewStaticKeys indexes verifiers by their key ID
+func NewStaticKeys(verifiers ...Verifier) StaticKeys {
+	keys := make(StaticKeys, len(verifiers))
+	for _, verifier := range verifiers {
+		keys[verifier.KeyID()] = verifier
+	}
+	return keys
+}
+
+// Verifier returns the verifier for keyID, or ErrUnknownKey for an unknown key or any tenant
+// but the default one
+func (k StaticKeys) Verifier(tenantID, keyID string) (Verifier, error) {
+	if tenantID != "" {
+		return nil, fmt.Errorf("%w: %q has no keys for tenant %q", ErrUnknownKey, keyID, tenantID)
+	}
+	verifier, ok := k[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return verifier, nil
+}
+
+// TenantKeys is a KeyProvider holding a separate set of verifiers per tenant. Key IDs are
+// scoped to their tenant, so two tenants may use the same key ID for different keys.
+type TenantKeys map[string]StaticKeys
+
+// NewTenantKeys creates an empty per-tenant key set
+func NewTenantKeys() TenantKeys {
+	return make(TenantKeys)
+}
+
+// Add registers verifiers for the tenant
+func (k TenantKeys) Add(tenantID string, verifiers ...Verifier) TenantKeys {
+	if k[tenantID] == nil {
+		k[tenantID] = make(StaticKeys, len(verifiers))
+	}
+	for _, verifier := range verifiers {
+		k[tenantID][verifier.KeyID()] = verifier
+	}
+	return k
+}
+
+// Verifier returns the tenant's verifier for keyID, or ErrUnknownKey
+func (k TenantKeys) Verifier(tenantID, keyID string) (Verifier, error) {
+	verifier, ok := k[tenantID][keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q for tenant %q", ErrUnknownKey, keyID, tenantID)
+	}
+	return verifier, nil
+}
+
+// HMACKey is a shared-secret key that both signs and verifies with HMAC-SHA256
+type HMACKey struct {
+	id     string
+	secret []byte
+}
+
+// NewHMACKey creates an HMAC-SHA256 key
+func NewHMACKey(keyID string, secret []byte) *HMACKey {
+	return &HMACKey{id: keyID, secret: secret}
+}
+
+// KeyID returns the key identifier
+func (k *HMACKey) KeyID() string {
+	return k.id
+}
+
+// Algorithm returns the signing algorithm identifier
+func (k *HMACKey) Algorithm() string {
+	return AlgorithmHMACSHA256
+}
+
+// Sign returns the HMAC-SHA256 of the payload
+func (k *HMACKey) Sign(payload []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write(payload)
+	return mac.Sum(nil), nil
+}
+
+// Verify checks the payload's HMAC-SHA256 in constant time
+func (k *HMACKey) Verify(payload, signature []byte) error {
+	expected, _ := k.Sign(payload)
+	if !hmac.Equal(expected, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SplitCredit allocates a credit across sub-accounts in proportion to ratios, returning one
+// credit per account that receives a non-zero share, ordered by account ID. Shares are computed
+// in minor units with the largest-remainder method: each account gets the floor of its exact
+// share and the leftover units go one each to the largest fractional remainders, ties broken by
+// account ID, so the parts always sum exactly to the original amount. The parts share
+// correlationID and reference the original event.
+func (e *LedgerEvent) SplitCredit(ratios map[AccountID]int, correlationID string) ([]*LedgerEvent, error) {
+	if !e.IsCredit() {
+		return nil, fmt.Errorf("only credits can be split, got %s", e.Type)
+	}
+	if e.Amount.Currency != e.Currency {
+		return nil, fmt.Errorf("%w: amount in %s on a %s event", ErrCurrencyMismatch, e.Amount.Currency, e.Currency)
+	}
+
+	accounts := make([]AccountID, 0, len(ratios))
+	var totalRatio int64
+	for accountID, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("ratio for account %s must not be negative", accountID)
+		}
+		totalRatio += int64(ratio)
+		accounts = append(accounts, accountID)
+	}
+	if totalRatio == 0 {
+		return nil, fmt.Errorf("split ratios must not sum to zero")
+	}
+	sort.Strings(accounts)
+
+	weights := make([]int64, len(accounts))
+	for i, accountID := range accounts {
+		weights[i] = int64(ratios[accountID])
+	}
+	shares := allocate(e.Amount.MinorUnits, weights)
+
+	parts := make([]*LedgerEvent, 0, len(accounts))
+	for i, accountID := range accounts {
+		if shares[i] == 0 {
+			continue
+		}
+		amount := e.Amount
+		amount.MinorUnits = shares[i]
+		part := NewLedgerEvent(Credit, amount, accountID, correlationID).WithReferenceID(e.ID)
+		part.PaymentID = e.PaymentID
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// allocate splits total minor units in proportion to weights with the largest-remainder method:
+// each weight gets the floor of its exact share and the leftover units go one each to the
+// largest fractional remainders, ties broken by position, so the shares sum exactly to total.
+// The weights must not be negative and must not sum to zero.
+func allocate(total int64, weights []int64) []int64 {
+	var totalWeight int64
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+
+	shares := make([]int64, len(weights))
+	remainders := make([]int64, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		exact := total * weight
+		shares[i] = exact / totalWeight
+		remainders[i] = exact % totalWeight
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+	for i := int64(0); i < total-allocated; i++ {
+		shares[order[i]]++
+	}
+	return shares
+}
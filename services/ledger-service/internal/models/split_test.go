@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCreditLargestRemainder(t *testing.T) {
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_pool", "corr_in")
+
+	parts, err := credit.SplitCredit(map[AccountID]int{"acc_a": 1, "acc_b": 1, "acc_c": 1}, "corr_split")
+	require.NoError(t, err)
+	require.Len(t, parts, 3)
+
+	// 10000 cents split three ways leaves one cent, which goes to the first account by ID
+	amounts := map[AccountID]int64{}
+	var sum int64
+	for _, part := range parts {
+		amounts[part.AccountID] = part.Amount.MinorUnits
+		sum += part.Amount.MinorUnits
+		assert.Equal(t, "corr_split", part.CorrelationID)
+		assert.Equal(t, credit.ID, *part.ReferenceID)
+		assert.NoError(t, part.Validate())
+	}
+	assert.Equal(t, map[AccountID]int64{"acc_a": 3334, "acc_b": 3333, "acc_c": 3333}, amounts)
+	assert.Equal(t, int64(10000), sum)
+
+	// Remainders 0.2, 0.6 and 0.2 cents of 0.01 USD split 1:3:1 give the leftover to acc_b
+	small := NewLedgerEvent(Credit, usdAmount(0.01), "acc_pool", "corr_in")
+	parts, err = small.SplitCredit(map[AccountID]int{"acc_a": 1, "acc_b": 3, "acc_c": 1}, "corr_split")
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	assert.Equal(t, "acc_b", parts[0].AccountID)
+}
+
+func TestSplitCreditValidation(t *testing.T) {
+	credit := NewLedgerEvent(Credit, usdAmount(100), "acc_pool", "corr_in")
+
+	_, err := credit.SplitCredit(map[AccountID]int{"acc_a": 0}, "corr_split")
+	assert.Error(t, err)
+
+	credit.Currency = "EUR"
+	_, err = credit.SplitCredit(map[AccountID]int{"acc_a": 1}, "corr_split")
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewLedgerEvent(Debit, usdAmount(5), "acc_pool", "corr_in").SplitCredit(map[AccountID]int{"acc_a": 1}, "corr_split")
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTaxComputationMismatch is returned when a tax component's amount is not its taxable base
+// times its rate
+var ErrTaxComputationMismatch = errors.New("tax amount does not match base times rate")
+
+// TaxType identifies the kind of tax a component levies
+type TaxType string
+
+const (
+	VAT      TaxType = "VAT"
+	GST      TaxType = "GST"
+	SalesTax TaxType = "SALES_TAX"
+)
+
+// TaxComponent is a single itemized tax: TaxAmount is TaxableBase × Rate, levied by Jurisdiction
+// (such as "DE" or "AU-NSW")
+type TaxComponent struct {
+	Type         TaxType `json:"type"`
+	Jurisdiction string  `json:"jurisdiction"`
+	Rate         Decimal `json:"rate"`
+	TaxableBase  Money   `json:"taxableBase"`
+	TaxAmount    Money   `json:"taxAmount"`
+}
+
+// TaxBreakdown itemizes the taxes included in an event's amount
+type TaxBreakdown struct {
+	Components []TaxComponent `json:"components"`
+}
+
+// Validate checks the component's type, jurisdiction and rate, and that TaxAmount is
+// TaxableBase × Rate within rounding: less than one minor unit of TaxAmount's precision away,
+// whichever rounding mode the jurisdiction uses
+func (c TaxComponent) Validate() error {
+	switch c.Type {
+	case VAT, GST, SalesTax:
+	default:
+		return fmt.Errorf("invalid tax type: %s", c.Type)
+	}
+	if c.Jurisdiction == "" {
+		return fmt.Errorf("%s component has no jurisdiction", c.Type)
+	}
+	if c.Rate.Sign() < 0 {
+		return fmt.Errorf("%s rate %s must not be negative", c.Type, c.Rate)
+	}
+	if c.TaxAmount.Currency != c.TaxableBase.Currency {
+		return fmt.Errorf("%w: %s tax in %s on a base in %s",
+			ErrCurrencyMismatch, c.Type, c.TaxAmount.Currency, c.TaxableBase.Currency)
+	}
+
+	expected := DecimalFromMoney(c.TaxableBase).Mul(c.Rate)
+	difference := DecimalFromMoney(c.TaxAmount).Sub(expected).Abs()
+	if difference.Cmp(NewDecimal(1, c.TaxAmount.Precision)) >= 0 {
+		return fmt.Errorf("%w: %s %s at %s on %s is %s, got %s", ErrTaxComputationMismatch, c.Jurisdiction, c.Type,
+			c.Rate, c.TaxableBase, expected, c.TaxAmount)
+	}
+	return nil
+}
+
+// Total sums the tax amounts, which must all be in the given currency
+func (b TaxBreakdown) Total(currency string) (Money, error) {
+	total := Money{Currency: currency}
+	for _, component := range b.Components {
+		if component.TaxAmount.Currency != currency {
+			return Money{}, fmt.Errorf("%w: %s %s in %s, expected %s",
+				ErrCurrencyMismatch, component.Jurisdiction, component.Type, component.TaxAmount.Currency, currency)
+		}
+		total = total.add(component.TaxAmount)
+	}
+	return total, nil
+}
+
+// Validate checks every component and that they are all in the currency of amount
+func (b TaxBreakdown) Validate(amount Money) error {
+	if len(b.Components) == 0 {
+		return fmt.Errorf("tax breakdown has no components")
+	}
+	for _, component := range b.Components {
+		if err := component.Validate(); err != nil {
+			return err
+		}
+	}
+	_, err := b.Total(amount.Currency)
+	return err
+}
+
+// WithTaxBreakdown attaches an itemized tax breakdown to the event
+func (e *LedgerEvent) WithTaxBreakdown(breakdown TaxBreakdown) *LedgerEvent {
+	e.Taxes = &breakdown
+	return e
+}
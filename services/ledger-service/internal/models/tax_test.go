@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eurAmount(minor int64) Money {
+	return NewMoney(minor, "EUR", 2)
+}
+
+func vat(jurisdiction string, rate Decimal, base, tax int64) TaxComponent {
+	return TaxComponent{Type: VAT, Jurisdiction: jurisdiction, Rate: rate, TaxableBase: eurAmount(base), TaxAmount: eurAmount(tax)}
+}
+
+func TestTaxComponentMatchingBaseTimesRate(t *testing.T) {
+	// 19% of 84.03 is 15.9657, which rounds to 15.97
+	event := NewLedgerEvent(Debit, eurAmount(10000), "acc_1", "corr_1").
+		WithTaxBreakdown(TaxBreakdown{Components: []TaxComponent{vat("DE", NewDecimal(19, 2), 8403, 1597)}})
+	require.NoError(t, event.Validate())
+
+	// Truncating jurisdictions are within rounding too
+	assert.NoError(t, vat("DE", NewDecimal(19, 2), 8403, 1596).Validate())
+}
+
+func TestTaxComponentMismatchFailsValidation(t *testing.T) {
+	event := NewLedgerEvent(Debit, eurAmount(10000), "acc_1", "corr_1").
+		WithTaxBreakdown(TaxBreakdown{Components: []TaxComponent{vat("DE", NewDecimal(19, 2), 8403, 1600)}})
+	assert.ErrorIs(t, event.Validate(), ErrTaxComputationMismatch)
+}
+
+func TestTaxComponentRejections(t *testing.T) {
+	component := vat("DE", NewDecimal(19, 2), 10000, 1900)
+	component.Type = "CUSTOMS"
+	assert.Error(t, component.Validate())
+
+	assert.Error(t, vat("", NewDecimal(19, 2), 10000, 1900).Validate())
+	assert.Error(t, vat("DE", NewDecimal(-19, 2), 10000, -1900).Validate())
+
+	foreign := vat("DE", NewDecimal(19, 2), 10000, 1900)
+	foreign.TaxAmount = NewMoney(1900, "USD", 2)
+	assert.ErrorIs(t, foreign.Validate(), ErrCurrencyMismatch)
+
+	empty := NewLedgerEvent(Debit, eurAmount(10000), "acc_1", "corr_1").WithTaxBreakdown(TaxBreakdown{})
+	assert.Error(t, empty.Validate())
+}
+
+func TestTaxBreakdownTotal(t *testing.T) {
+	breakdown := TaxBreakdown{Components: []TaxComponent{
+		vat("DE", NewDecimal(19, 2), 5000, 950),
+		vat("DE", NewDecimal(7, 2), 3000, 210),
+		{Type: GST, Jurisdiction: "AU", Rate: NewDecimal(1, 1), TaxableBase: eurAmount(1000), TaxAmount: eurAmount(100)},
+	}}
+	total, err := breakdown.Total("EUR")
+	require.NoError(t, err)
+	assert.Equal(t, eurAmount(1260), total)
+
+	_, err = breakdown.Total("USD")
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestTaxBreakdownIsSignedAndSerialized(t *testing.T) {
+	event := NewLedgerEvent(Debit, eurAmount(11900), "acc_1", "corr_1").
+		WithTaxBreakdown(TaxBreakdown{Components: []TaxComponent{vat("DE", NewDecimal(19, 2), 10000, 1900)}})
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	require.NoError(t, event.SignWith(key))
+
+	decoded := jsonRoundTrip(t, event)
+	assert.Equal(t, "0.19", decoded.Taxes.Components[0].Rate.String())
+	assert.NoError(t, decoded.VerifyWith(key))
+
+	decoded.Taxes.Components[0].TaxAmount = eurAmount(1901)
+	assert.Error(t, decoded.VerifyWith(key))
+}
@@ -0,0 +1,24 @@
+package models
+
+import "fmt"
+
+// SignForTenant assigns the event to the tenant and signs it with the tenant's signer. The
+// tenant ID is covered by the signature, so the event cannot be moved to another tenant.
+func (e *LedgerEvent) SignForTenant(tenantID string, signer Signer) error {
+	e.TenantID = tenantID
+	return e.SignWith(signer)
+}
+
+// VerifyForTenant verifies the event on behalf of tenantID with the key its KeyID resolves to
+// among that tenant's keys. An event belonging to another tenant is rejected with
+// ErrCrossTenant before any key is looked up.
+func (e *LedgerEvent) VerifyForTenant(tenantID string, keys KeyProvider) error {
+	if e.TenantID != tenantID {
+		return fmt.Errorf("%w: event %s belongs to tenant %q, not %q", ErrCrossTenant, e.ID, e.TenantID, tenantID)
+	}
+	verifier, err := keys.Verifier(tenantID, e.KeyID)
+	if err != nil {
+		return err
+	}
+	return e.VerifyWith(verifier)
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantSigningKeys(t *testing.T) {
+	// Both tenants use the key ID "signing-1", with different secrets
+	tenantA := NewHMACKey("signing-1", []byte("tenant-a-secret"))
+	tenantB := NewHMACKey("signing-1", []byte("tenant-b-secret"))
+	keys := NewTenantKeys().Add("tenant_a", tenantA).Add("tenant_b", tenantB)
+
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignForTenant("tenant_a", tenantA))
+	assert.NoError(t, event.VerifyForTenant("tenant_a", keys))
+
+	assert.ErrorIs(t, event.VerifyForTenant("tenant_b", keys), ErrCrossTenant)
+	assert.ErrorIs(t, event.VerifyWith(tenantB), ErrInvalidSignature)
+
+	// Reassigning the event to tenant B breaks its signature
+	moved := *event
+	moved.TenantID = "tenant_b"
+	assert.ErrorIs(t, moved.VerifyForTenant("tenant_b", keys), ErrInvalidSignature)
+
+	_, err := keys.Verifier("tenant_c", "signing-1")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = NewStaticKeys(tenantA).Verifier("tenant_a", "signing-1")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestVerificationReportResolvesKeysByTenant(t *testing.T) {
+	tenantA := NewHMACKey("signing-1", []byte("tenant-a-secret"))
+	tenantB := NewHMACKey("signing-1", []byte("tenant-b-secret"))
+	keys := NewTenantKeys().Add("tenant_a", tenantA).Add("tenant_b", tenantB)
+
+	a := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, a.SignForTenant("tenant_a", tenantA))
+	b := NewLedgerEvent(Credit, usdAmount(10), "acc_2", "corr_2")
+	require.NoError(t, b.SignForTenant("tenant_b", tenantB))
+
+	report := VerificationReport([]*LedgerEvent{a, b}, keys)
+	assert.True(t, report.Verified(), report.Failures)
+}
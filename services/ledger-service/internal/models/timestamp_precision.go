@@ -0,0 +1,55 @@
+package models
+
+// TimestampPrecision selects the precision event signatures cover the timestamp at. Covering
+// seconds leaves sub-second ordering out of the signature: two events in the same second that
+// otherwise agree canonicalize identically, and either timestamp can be moved within its
+// second without invalidating the signature. Covering nanoseconds closes that gap, but only
+// verifiers that understand the recorded +ts-nano algorithm suffix accept such signatures.
+type TimestampPrecision int
+
+const (
+	// NanosecondTimestamps covers the timestamp at nanosecond precision (RFC3339Nano); every
+	// signing method uses it unless told otherwise
+	NanosecondTimestamps TimestampPrecision = iota
+	// SecondTimestamps is the compatibility mode: the signature covers the timestamp in Unix
+	// seconds, as verifiers that predate nanosecond signatures expect, and leaves out a hold's
+	// ExpiresAt as they did
+	SecondTimestamps
+)
+
+// SignOption configures a single signing call
+type SignOption func(*signOptions)
+
+// signOptions holds the settings of a signing call
+type signOptions struct {
+	precision TimestampPrecision
+}
+
+// WithTimestampPrecision makes the signature cover the event timestamp at precision.
+// Signatures record the precision they were issued at, so verification never needs it.
+func WithTimestampPrecision(precision TimestampPrecision) SignOption {
+	return func(o *signOptions) {
+		o.precision = precision
+	}
+}
+
+// SignedTimestampPrecision returns the precision the event's signature covers its timestamp
+// at: NanosecondTimestamps or SecondTimestamps
+func (e *LedgerEvent) SignedTimestampPrecision() TimestampPrecision {
+	if _, nanos := splitAlgorithm(e.SignatureAlgorithm); nanos {
+		return NanosecondTimestamps
+	}
+	return SecondTimestamps
+}
+
+// recordedAlgorithm returns the algorithm to record when signing with algorithm under opts
+func recordedAlgorithm(algorithm string, opts []SignOption) string {
+	var o signOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.precision == SecondTimestamps {
+		return algorithm
+	}
+	return withTimestampNanos(algorithm)
+}
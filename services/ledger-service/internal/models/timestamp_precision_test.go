@@ -0,0 +1,95 @@
+package models
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sameSecondEvents returns two events that differ only in their timestamps, 250ms apart in
+// the same second
+func sameSecondEvents() (*LedgerEvent, *LedgerEvent) {
+	first := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_1").WithVersion(1)
+	first.ID = "evt_1"
+	first.Timestamp = time.Date(2024, 3, 1, 9, 30, 15, 100_000_000, time.UTC)
+	second := first.Clone()
+	second.Timestamp = first.Timestamp.Add(250 * time.Millisecond)
+	return first, second
+}
+
+func TestNanosecondTimestampsDistinguishSameSecondEvents(t *testing.T) {
+	first, second := sameSecondEvents()
+	require.NoError(t, first.Sign("secret", WithTimestampPrecision(NanosecondTimestamps)))
+	require.NoError(t, second.Sign("secret", WithTimestampPrecision(NanosecondTimestamps)))
+
+	firstBytes, err := first.CanonicalBytes()
+	require.NoError(t, err)
+	secondBytes, err := second.CanonicalBytes()
+	require.NoError(t, err)
+	assert.NotEqual(t, firstBytes, secondBytes)
+	assert.NotEqual(t, first.Signature, second.Signature)
+	assert.Equal(t, NanosecondTimestamps, first.SignedTimestampPrecision())
+	assert.True(t, first.Verify("secret"))
+
+	// Moving the timestamp within its second now breaks the signature
+	first.Timestamp = second.Timestamp
+	assert.False(t, first.Verify("secret"))
+}
+
+func TestSecondTimestampsCompatibilityMode(t *testing.T) {
+	first, second := sameSecondEvents()
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	require.NoError(t, first.SignWith(key, WithTimestampPrecision(SecondTimestamps)))
+	require.NoError(t, second.SignWith(key, WithTimestampPrecision(SecondTimestamps)))
+
+	assert.Equal(t, AlgorithmHMACSHA256, first.SignatureAlgorithm)
+	assert.Equal(t, SecondTimestamps, first.SignedTimestampPrecision())
+	assert.Equal(t, first.Signature, second.Signature)
+	assert.NoError(t, second.VerifyWith(key))
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	require.NoError(t, first.SignEd25519(priv, WithTimestampPrecision(SecondTimestamps)))
+	assert.Equal(t, AlgorithmEd25519, first.SignatureAlgorithm)
+}
+
+func TestSigningDefaultsToNanosecondTimestamps(t *testing.T) {
+	event, _ := sameSecondEvents()
+
+	require.NoError(t, event.Sign("secret"))
+	assert.Equal(t, AlgorithmSHA256Shared+"+ts-nano", event.SignatureAlgorithm)
+	assert.Equal(t, NanosecondTimestamps, event.SignedTimestampPrecision())
+
+	require.NoError(t, event.SignWith(NewHMACKey("ledger-1", []byte("secret"))))
+	assert.Equal(t, NanosecondTimestamps, event.SignedTimestampPrecision())
+
+	_, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	require.NoError(t, event.SignEd25519(priv))
+	assert.Equal(t, NanosecondTimestamps, event.SignedTimestampPrecision())
+}
+
+func TestSignDistinguishesSubSecondTimestampsByDefault(t *testing.T) {
+	first, second := sameSecondEvents()
+	require.NoError(t, first.Sign("secret"))
+	require.NoError(t, second.Sign("secret"))
+
+	assert.NotEqual(t, first.Signature, second.Signature)
+	assert.True(t, first.Verify("secret"))
+	assert.True(t, second.Verify("secret"))
+}
+
+func TestSignaturesOfEitherPrecisionVerify(t *testing.T) {
+	nanos, _ := sameSecondEvents()
+	require.NoError(t, nanos.Sign("secret"))
+	seconds, _ := sameSecondEvents()
+	require.NoError(t, seconds.Sign("secret", WithTimestampPrecision(SecondTimestamps)))
+
+	assert.True(t, nanos.Verify("secret"))
+	assert.True(t, seconds.Verify("secret"))
+	assert.Equal(t, NanosecondTimestamps, jsonRoundTrip(t, nanos).SignedTimestampPrecision())
+	assert.Equal(t, SecondTimestamps, jsonRoundTrip(t, seconds).SignedTimestampPrecision())
+}
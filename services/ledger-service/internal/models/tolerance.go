@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimestampTolerance is how far a signed timestamp may be from the current time unless
+// SetTimestampTolerance changes it
+const DefaultTimestampTolerance = 5 * time.Minute
+
+// ErrTimestampOutOfTolerance is returned when a signed timestamp is too far from the current
+// time, which is how replayed webhooks and events are rejected
+var ErrTimestampOutOfTolerance = errors.New("timestamp out of tolerance")
+
+var timestampTolerance atomic.Int64
+
+func init() {
+	timestampTolerance.Store(int64(DefaultTimestampTolerance))
+}
+
+// SetTimestampTolerance sets the tolerance every timestamped-signature check uses and returns
+// the previous one. A tolerance <= 0 disables the check.
+func SetTimestampTolerance(tolerance time.Duration) time.Duration {
+	return time.Duration(timestampTolerance.Swap(int64(tolerance)))
+}
+
+// TimestampTolerance returns the current timestamp tolerance
+func TimestampTolerance() time.Duration {
+	return time.Duration(timestampTolerance.Load())
+}
+
+// CheckTimestamp returns ErrTimestampOutOfTolerance if ts is more than the tolerance before or
+// after the clock's current time
+func CheckTimestamp(ts time.Time, clock Clock) error {
+	tolerance := TimestampTolerance()
+	if tolerance <= 0 {
+		return nil
+	}
+	skew := clock.Now().Sub(ts)
+	if skew > tolerance || skew < -tolerance {
+		return fmt.Errorf("%w: %s is %s from now, tolerance is %s", ErrTimestampOutOfTolerance,
+			ts.UTC().Format(time.RFC3339), skew.Round(time.Second), tolerance)
+	}
+	return nil
+}
+
+// timestampedPayload prefixes the payload with the Unix timestamp it was signed at, so the
+// timestamp is covered by the signature
+func timestampedPayload(payload []byte, signedAt time.Time) []byte {
+	prefixed := strconv.AppendInt(nil, signedAt.Unix(), 10)
+	prefixed = append(prefixed, '.')
+	return append(prefixed, payload...)
+}
+
+// SignTimestamped signs "<unix seconds>.<payload>", the form webhook deliveries are signed in
+func SignTimestamped(payload []byte, signedAt time.Time, signer Signer) ([]byte, error) {
+	return signer.Sign(timestampedPayload(payload, signedAt))
+}
+
+// VerifyTimestamped verifies a signature produced by SignTimestamped and that signedAt is
+// within the timestamp tolerance of the clock, so a captured delivery cannot be replayed later
+func VerifyTimestamped(payload []byte, signedAt time.Time, signature []byte, verifier Verifier, clock Clock) error {
+	if err := verifier.Verify(timestampedPayload(payload, signedAt), signature); err != nil {
+		return err
+	}
+	return CheckTimestamp(signedAt, clock)
+}
+
+// VerifyFresh verifies the event signature like VerifyWith and that the event timestamp, which
+// the signature covers, is within the timestamp tolerance of the clock
+func (e *LedgerEvent) VerifyFresh(verifier Verifier, clock Clock) error {
+	if err := e.VerifyWith(verifier); err != nil {
+		return err
+	}
+	return CheckTimestamp(e.Timestamp, clock)
+}
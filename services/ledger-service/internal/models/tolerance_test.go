@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTimestampedTolerance(t *testing.T) {
+	key := NewHMACKey("webhook-1", []byte("secret"))
+	signedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"event":"settled"}`)
+
+	signature, err := SignTimestamped(payload, signedAt, key)
+	require.NoError(t, err)
+
+	assert.NoError(t, VerifyTimestamped(payload, signedAt, signature, key, FixedClock(signedAt.Add(4*time.Minute))))
+	assert.NoError(t, VerifyTimestamped(payload, signedAt, signature, key, FixedClock(signedAt.Add(-4*time.Minute))))
+	assert.ErrorIs(t, VerifyTimestamped(payload, signedAt, signature, key, FixedClock(signedAt.Add(6*time.Minute))),
+		ErrTimestampOutOfTolerance)
+	assert.ErrorIs(t, VerifyTimestamped(payload, signedAt.Add(time.Minute), signature, key, FixedClock(signedAt)),
+		ErrInvalidSignature, "the timestamp is covered by the signature")
+
+	previous := SetTimestampTolerance(10 * time.Minute)
+	t.Cleanup(func() { SetTimestampTolerance(previous) })
+	assert.Equal(t, DefaultTimestampTolerance, previous)
+	assert.NoError(t, VerifyTimestamped(payload, signedAt, signature, key, FixedClock(signedAt.Add(6*time.Minute))))
+}
+
+func TestVerifyFreshRejectsStaleEvents(t *testing.T) {
+	key := NewHMACKey("ledger-1", []byte("secret"))
+	event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+	require.NoError(t, event.SignWith(key))
+
+	assert.NoError(t, event.VerifyFresh(key, FixedClock(event.Timestamp.Add(time.Minute))))
+	assert.ErrorIs(t, event.VerifyFresh(key, FixedClock(event.Timestamp.Add(time.Hour))), ErrTimestampOutOfTolerance)
+}
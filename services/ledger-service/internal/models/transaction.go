@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnbalancedTransaction is returned when a transaction's debits and credits differ
+var ErrUnbalancedTransaction = errors.New("transaction debits and credits differ")
+
+// Transaction groups the events of one monetary movement, those sharing a CorrelationID
+type Transaction struct {
+	CorrelationID string
+	Events        []*LedgerEvent
+}
+
+// NewTransaction groups events into a transaction; they must all share a correlation ID
+func NewTransaction(events []*LedgerEvent) (*Transaction, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("transaction has no events")
+	}
+	correlationID := events[0].CorrelationID
+	for _, event := range events[1:] {
+		if event.CorrelationID != correlationID {
+			return nil, fmt.Errorf("event %s has correlation %s, transaction has %s", event.ID, event.CorrelationID, correlationID)
+		}
+	}
+	return &Transaction{CorrelationID: correlationID, Events: events}, nil
+}
+
+// GroupTransactions splits events into transactions by correlation ID, in order of each
+// correlation's first event; events keep their order within a transaction
+func GroupTransactions(events []*LedgerEvent) []*Transaction {
+	var transactions []*Transaction
+	byCorrelation := make(map[string]*Transaction)
+	for _, event := range events {
+		tx, ok := byCorrelation[event.CorrelationID]
+		if !ok {
+			tx = &Transaction{CorrelationID: event.CorrelationID}
+			byCorrelation[event.CorrelationID] = tx
+			transactions = append(transactions, tx)
+		}
+		tx.Events = append(tx.Events, event)
+	}
+	return transactions
+}
+
+// Balanced checks double entry: in every currency the transaction's debits total its credits.
+// Only events that post to a balance count, so holds, releases, reversals, control and
+// chargeback events are ignored. An adjustment counts by its sign: a positive one as a credit,
+// a negative one as a debit of its magnitude, matching how it moves the posted balance.
+// Amounts are compared exactly across precisions.
+func (t *Transaction) Balanced() error {
+	debits := make(map[string][]Money)
+	credits := make(map[string][]Money)
+	for _, event := range t.Events {
+		if !event.AffectsBalance() {
+			continue
+		}
+		currency := event.Amount.Currency
+		switch {
+		case event.IsDebit():
+			debits[currency] = append(debits[currency], event.Amount)
+		case event.IsAdjustment() && event.Amount.Sign() < 0:
+			debits[currency] = append(debits[currency], event.Amount.negate())
+		default:
+			credits[currency] = append(credits[currency], event.Amount)
+		}
+	}
+
+	currencies := make([]string, 0, len(debits)+len(credits))
+	for currency := range debits {
+		currencies = append(currencies, currency)
+	}
+	for currency := range credits {
+		if _, ok := debits[currency]; !ok {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		debited, err := sumSide(debits[currency], currency)
+		if err != nil {
+			return err
+		}
+		credited, err := sumSide(credits[currency], currency)
+		if err != nil {
+			return err
+		}
+		if !debited.Equal(credited) {
+			return fmt.Errorf("%w: correlation %s debits %s %s, credits %s %s", ErrUnbalancedTransaction,
+				t.CorrelationID, debited.decimal(), currency, credited.decimal(), currency)
+		}
+	}
+	return nil
+}
+
+// sumSide totals one side of a transaction in currency, which may have no entries
+func sumSide(amounts []Money, currency string) (Money, error) {
+	if len(amounts) == 0 {
+		return Money{Currency: currency}, nil
+	}
+	return SumPromoting(amounts)
+}
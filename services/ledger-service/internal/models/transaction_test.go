@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionBalanced(t *testing.T) {
+	legs, err := TransferWithFee("acc_1", "acc_2", "acc_fees", usdAmount(100), usdAmount(2.5), "corr_1")
+	require.NoError(t, err)
+	hold := NewLedgerEvent(Hold, usdAmount(500), "acc_1", "corr_1")
+	euros := NewLedgerEvent(Debit, NewMoney(1000, "EUR", 2), "acc_3", "corr_1")
+	eurosBack := NewLedgerEvent(Credit, NewMoney(10000, "EUR", 3), "acc_4", "corr_1")
+
+	tx, err := NewTransaction(append(legs, hold, euros, eurosBack))
+	require.NoError(t, err)
+	assert.NoError(t, tx.Balanced(), "holds are ignored and amounts compare across precisions")
+
+	tx.Events = append(tx.Events, NewLedgerEvent(Adjustment, usdAmount(1), "acc_2", "corr_1"))
+	err = tx.Balanced()
+	require.ErrorIs(t, err, ErrUnbalancedTransaction)
+	assert.Contains(t, err.Error(), "debits 102.5 USD, credits 103.5 USD")
+
+	correction := NewLedgerEvent(Adjustment, usdAmount(1), "acc_fees", "corr_1")
+	correction.Amount = correction.Amount.negate()
+	tx.Events = append(tx.Events, correction)
+	assert.NoError(t, tx.Balanced(), "a negative adjustment counts as a debit")
+}
+
+func TestGroupTransactions(t *testing.T) {
+	a1 := NewLedgerEvent(Debit, usdAmount(10), "acc_1", "corr_a")
+	b1 := NewLedgerEvent(Debit, usdAmount(5), "acc_1", "corr_b")
+	a2 := NewLedgerEvent(Credit, usdAmount(10), "acc_2", "corr_a")
+
+	transactions := GroupTransactions([]*LedgerEvent{a1, b1, a2})
+	require.Len(t, transactions, 2)
+	assert.Equal(t, "corr_a", transactions[0].CorrelationID)
+	assert.Equal(t, []*LedgerEvent{a1, a2}, transactions[0].Events)
+	assert.NoError(t, transactions[0].Balanced())
+	assert.ErrorIs(t, transactions[1].Balanced(), ErrUnbalancedTransaction)
+
+	_, err := NewTransaction([]*LedgerEvent{a1, b1})
+	assert.Error(t, err)
+	_, err = NewTransaction(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInsufficientFunds is returned when a debit or hold exceeds the available balance
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrUnbalancedTransfer is returned when the legs of a transfer do not net to zero
+	ErrUnbalancedTransfer = errors.New("transfer legs do not net to zero")
+)
+
+// Transfer leg roles, recorded in each leg's "transferLeg" metadata
+const (
+	LegSender   = "sender"
+	LegReceiver = "receiver"
+	LegFee      = "fee"
+)
+
+// TransferOption configures TransferWithFee
+type TransferOption func(*transferConfig)
+
+type transferConfig struct {
+	available *Money
+}
+
+// WithAvailableBalance rejects the transfer with ErrInsufficientFunds when the sender's
+// available balance does not cover the amount plus the fee. Without it funds are left to be
+// checked when the legs are applied.
+func WithAvailableBalance(available Money) TransferOption {
+	return func(c *transferConfig) {
+		c.available = &available
+	}
+}
+
+// TransferWithFee builds the three legs of a transfer that charges a fee: a debit of amount+fee
+// from the sender, a credit of amount to the receiver and a credit of fee to the fee account.
+// The legs share the correlation ID, net to zero and should be appended atomically.
+func TransferWithFee(from, to, feeAccount AccountID, amount, fee Money, correlationID string, opts ...TransferOption) ([]*LedgerEvent, error) {
+	var config transferConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if amount.Currency != fee.Currency {
+		return nil, fmt.Errorf("%w: %s transfer with %s fee", ErrCurrencyMismatch, amount.Currency, fee.Currency)
+	}
+	if amount.Sign() <= 0 || fee.Sign() < 0 {
+		return nil, fmt.Errorf("transfer amount must be positive and fee non-negative")
+	}
+	if from == to || from == feeAccount {
+		return nil, fmt.Errorf("sender %s must differ from receiver and fee account", from)
+	}
+
+	total := amount.add(fee)
+	if config.available != nil {
+		if config.available.Currency != total.Currency {
+			return nil, fmt.Errorf("%w: %s balance for %s transfer", ErrCurrencyMismatch, config.available.Currency, total.Currency)
+		}
+		if total.MinorUnits > config.available.minorAt(total.Precision) {
+			return nil, fmt.Errorf("%w: transfer of %.*f needs %.*f, %.*f available", ErrInsufficientFunds,
+				total.Precision, amount.Float(), total.Precision, total.Float(), total.Precision, config.available.Float())
+		}
+	}
+
+	legs := []*LedgerEvent{
+		NewLedgerEvent(Debit, total, from, correlationID).WithMetadata("transferLeg", LegSender),
+		NewLedgerEvent(Credit, amount, to, correlationID).WithMetadata("transferLeg", LegReceiver),
+	}
+	// A waived fee has no leg, since events must carry a positive amount
+	if fee.Sign() > 0 {
+		legs = append(legs, NewLedgerEvent(Credit, fee, feeAccount, correlationID).WithMetadata("transferLeg", LegFee))
+	}
+
+	if err := checkNetZero(legs, correlationID); err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+// checkNetZero verifies that the legs of a correlation net to zero across their accounts
+func checkNetZero(legs []*LedgerEvent, correlationID string) error {
+	net, err := CorrelationNet(legs, correlationID)
+	if err != nil {
+		return err
+	}
+
+	var sum Money
+	for _, amount := range net {
+		sum = sum.add(amount)
+	}
+	if sum.Sign() != 0 {
+		return fmt.Errorf("%w: correlation %s nets to %.*f", ErrUnbalancedTransfer, correlationID, sum.Precision, sum.Float())
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferWithFeeLegsNetToZero(t *testing.T) {
+	legs, err := TransferWithFee("acc_sender", "acc_receiver", "acc_fees", usdAmount(100), usdAmount(2.5), "xfer_1",
+		WithAvailableBalance(usdAmount(102.5)))
+	require.NoError(t, err)
+	require.Len(t, legs, 3)
+
+	net, err := CorrelationNet(legs, "xfer_1")
+	require.NoError(t, err)
+	assert.Equal(t, usdAmount(-102.5), net["acc_sender"])
+	assert.Equal(t, usdAmount(100), net["acc_receiver"])
+	assert.Equal(t, usdAmount(2.5), net["acc_fees"])
+
+	var sum int64
+	for _, amount := range net {
+		sum += amount.MinorUnits
+	}
+	assert.Zero(t, sum)
+
+	assert.True(t, legs[0].IsDebit())
+	assert.Equal(t, LegSender, legs[0].Metadata["transferLeg"])
+	for _, leg := range legs {
+		assert.NoError(t, leg.Validate())
+	}
+}
+
+func TestTransferWithFeeRejections(t *testing.T) {
+	_, err := TransferWithFee("a", "b", "fees", usdAmount(100), NewMoney(100, "EUR", 2), "xfer_1")
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = TransferWithFee("a", "b", "fees", usdAmount(100), usdAmount(2.5), "xfer_1", WithAvailableBalance(usdAmount(102.49)))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+// VerificationOutcome classifies the result of verifying one event
+type VerificationOutcome string
+
+const (
+	OutcomeVerified   VerificationOutcome = "VERIFIED"
+	OutcomeUnsigned   VerificationOutcome = "UNSIGNED"
+	OutcomeUnknownKey VerificationOutcome = "UNKNOWN_KEY"
+	OutcomeInvalid    VerificationOutcome = "INVALID"
+)
+
+// VerificationFailure describes an event that did not verify
+type VerificationFailure struct {
+	EventID string              `json:"eventId"`
+	KeyID   string              `json:"keyId,omitempty"`
+	Outcome VerificationOutcome `json:"outcome"`
+	Reason  string              `json:"reason"`
+}
+
+// Report summarizes the verification of a batch of events for auditors
+type Report struct {
+	Total    int                         `json:"total"`
+	Counts   map[VerificationOutcome]int `json:"counts"`
+	Failures []VerificationFailure       `json:"failures"`
+	// KeyIDs lists the distinct key IDs the events were signed with, sorted
+	KeyIDs []string `json:"keyIds"`
+}
+
+// Verified reports whether every event in the batch verified
+func (r Report) Verified() bool {
+	return r.Counts[OutcomeVerified] == r.Total
+}
+
+// VerificationReport verifies each event with the verifier its TenantID and KeyID resolve to and reports
+// the outcome counts, every failing event with its reason, and the key IDs encountered.
+// Failures are listed in batch order.
+func VerificationReport(events []*LedgerEvent, keys KeyProvider) Report {
+	report := newReport()
+	for _, event := range events {
+		report.record(event, keys)
+	}
+	return *report
+}
+
+// newReport returns an empty report ready to record events
+func newReport() *Report {
+	return &Report{
+		Counts:   make(map[VerificationOutcome]int),
+		Failures: []VerificationFailure{},
+		KeyIDs:   []string{},
+	}
+}
+
+// record verifies one event and counts it in the report, keeping KeyIDs sorted
+func (r *Report) record(event *LedgerEvent, keys KeyProvider) {
+	r.Total++
+	if event.KeyID != "" {
+		if i := sort.SearchStrings(r.KeyIDs, event.KeyID); i == len(r.KeyIDs) || r.KeyIDs[i] != event.KeyID {
+			r.KeyIDs = append(r.KeyIDs[:i], append([]string{event.KeyID}, r.KeyIDs[i:]...)...)
+		}
+	}
+
+	outcome, reason := verifyOutcome(event, keys)
+	r.Counts[outcome]++
+	if outcome != OutcomeVerified {
+		r.Failures = append(r.Failures, VerificationFailure{
+			EventID: event.ID,
+			KeyID:   event.KeyID,
+			Outcome: outcome,
+			Reason:  reason,
+		})
+	}
+}
+
+// verifyOutcome verifies a single event and classifies the result
+func verifyOutcome(event *LedgerEvent, keys KeyProvider) (VerificationOutcome, string) {
+	if event.Signature == "" {
+		return OutcomeUnsigned, "event is not signed"
+	}
+	if event.KeyID == "" {
+		return OutcomeUnknownKey, "event does not name its signing key"
+	}
+
+	verifier, err := keys.Verifier(event.TenantID, event.KeyID)
+	if err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return OutcomeUnknownKey, err.Error()
+		}
+		return OutcomeInvalid, err.Error()
+	}
+	if err := event.VerifyWith(verifier); err != nil {
+		return OutcomeInvalid, err.Error()
+	}
+	return OutcomeVerified, ""
+}
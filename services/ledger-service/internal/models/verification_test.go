@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationReportMixedBatch(t *testing.T) {
+	current := NewHMACKey("ledger-2", []byte("current"))
+	retired := NewHMACKey("ledger-1", []byte("retired"))
+	unknown := NewHMACKey("ledger-x", []byte("unknown"))
+
+	signed := func(key *HMACKey) *LedgerEvent {
+		event := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+		require.NoError(t, event.SignWith(key))
+		return event
+	}
+	good, old := signed(current), signed(retired)
+	tampered := signed(current)
+	tampered.Amount.MinorUnits = 100000
+	foreign := signed(unknown)
+	unsigned := NewLedgerEvent(Credit, usdAmount(10), "acc_1", "corr_1")
+
+	report := VerificationReport([]*LedgerEvent{good, tampered, old, foreign, unsigned}, NewStaticKeys(current, retired))
+
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, map[VerificationOutcome]int{
+		OutcomeVerified:   2,
+		OutcomeInvalid:    1,
+		OutcomeUnknownKey: 1,
+		OutcomeUnsigned:   1,
+	}, report.Counts)
+	assert.Equal(t, []string{"ledger-1", "ledger-2", "ledger-x"}, report.KeyIDs)
+	assert.False(t, report.Verified())
+
+	require.Len(t, report.Failures, 3)
+	assert.Equal(t, tampered.ID, report.Failures[0].EventID)
+	assert.Equal(t, OutcomeInvalid, report.Failures[0].Outcome)
+	assert.Contains(t, report.Failures[0].Reason, ErrInvalidSignature.Error())
+	assert.Equal(t, foreign.ID, report.Failures[1].EventID)
+	assert.Contains(t, report.Failures[1].Reason, "ledger-x")
+	assert.Equal(t, unsigned.ID, report.Failures[2].EventID)
+	assert.Equal(t, OutcomeUnsigned, report.Failures[2].Outcome)
+}
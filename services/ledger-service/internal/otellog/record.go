@@ -0,0 +1,97 @@
+// Package otellog mirrors ledger events as OpenTelemetry log records. It lives apart from
+// models so that only services shipping events to an OTel pipeline depend on the collector pdata.
+package otellog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Attribute keys set on every record
+const (
+	AttrEventID       = "ledger.event.id"
+	AttrEventType     = "ledger.event.type"
+	AttrEventVersion  = "ledger.event.version"
+	AttrAccountID     = "ledger.account.id"
+	AttrAmount        = "ledger.amount"
+	AttrCurrency      = "ledger.currency"
+	AttrCorrelationID = "ledger.correlation.id"
+	AttrPaymentID     = "ledger.payment.id"
+	AttrReferenceID   = "ledger.reference.id"
+	AttrKeyID         = "ledger.signature.key_id"
+)
+
+// metadataPrefix namespaces event metadata keys among the record attributes
+const metadataPrefix = "ledger.metadata."
+
+// ToOTelLogRecord maps an event to a log record. Event fields become attributes, the correlation
+// ID becomes the trace ID and the event ID the span ID, so records of one payment flow group
+// into a single trace. Control events are logged at WARN, everything else at INFO.
+func ToOTelLogRecord(e *models.LedgerEvent) plog.LogRecord {
+	record := plog.NewLogRecord()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(e.Timestamp))
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	record.Body().SetStr(e.String())
+
+	if e.IsControl() {
+		record.SetSeverityNumber(plog.SeverityNumberWarn)
+		record.SetSeverityText("WARN")
+	} else {
+		record.SetSeverityNumber(plog.SeverityNumberInfo)
+		record.SetSeverityText("INFO")
+	}
+
+	record.SetTraceID(pcommon.TraceID(traceID(e.CorrelationID)))
+	record.SetSpanID(pcommon.SpanID(spanID(e.ID)))
+
+	attrs := record.Attributes()
+	attrs.PutStr(AttrEventID, e.ID)
+	attrs.PutStr(AttrEventType, string(e.Type))
+	attrs.PutInt(AttrEventVersion, e.Version)
+	attrs.PutStr(AttrAccountID, e.AccountID)
+	attrs.PutDouble(AttrAmount, e.Amount.Float())
+	attrs.PutStr(AttrCurrency, e.Currency)
+	attrs.PutStr(AttrCorrelationID, e.CorrelationID)
+	if e.PaymentID != nil {
+		attrs.PutStr(AttrPaymentID, *e.PaymentID)
+	}
+	if e.ReferenceID != nil {
+		attrs.PutStr(AttrReferenceID, *e.ReferenceID)
+	}
+	if e.KeyID != "" {
+		attrs.PutStr(AttrKeyID, e.KeyID)
+	}
+	for key, value := range e.Metadata {
+		// FromRaw only fails for unsupported value types, which are dropped rather than logged half-converted
+		_ = attrs.PutEmpty(metadataPrefix + key).FromRaw(value)
+	}
+
+	return record
+}
+
+// traceID uses a correlation ID that is already a W3C trace ID as is, and otherwise derives one
+// from its SHA-256 so the mapping is stable across services
+func traceID(correlationID string) [16]byte {
+	var id [16]byte
+	if decoded, err := hex.DecodeString(correlationID); err == nil && len(decoded) == len(id) {
+		copy(id[:], decoded)
+		return id
+	}
+	hash := sha256.Sum256([]byte(correlationID))
+	copy(id[:], hash[:])
+	return id
+}
+
+// spanID derives a stable span ID from the event ID
+func spanID(eventID string) [8]byte {
+	var id [8]byte
+	hash := sha256.Sum256([]byte(eventID))
+	copy(id[:], hash[:])
+	return id
+}
@@ -0,0 +1,47 @@
+package otellog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestToOTelLogRecordCarriesEventAttributes(t *testing.T) {
+	event := models.NewLedgerEvent(models.Debit, models.NewMoney(1250, "USD", 2), "acc_1", "4bf92f3577b34da6a3ce929d0e0e4736").
+		WithPaymentID("pay_1").
+		WithMetadata("channel", "card").
+		WithVersion(3)
+
+	record := ToOTelLogRecord(event)
+
+	attrs := record.Attributes().AsRaw()
+	assert.Equal(t, event.ID, attrs[AttrEventID])
+	assert.Equal(t, "DEBIT", attrs[AttrEventType])
+	assert.Equal(t, int64(3), attrs[AttrEventVersion])
+	assert.Equal(t, "acc_1", attrs[AttrAccountID])
+	assert.Equal(t, 12.5, attrs[AttrAmount])
+	assert.Equal(t, "USD", attrs[AttrCurrency])
+	assert.Equal(t, "pay_1", attrs[AttrPaymentID])
+	assert.Equal(t, "card", attrs["ledger.metadata.channel"])
+	assert.NotContains(t, attrs, AttrReferenceID)
+
+	assert.Equal(t, plog.SeverityNumberInfo, record.SeverityNumber())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID().String())
+	assert.False(t, record.SpanID().IsEmpty())
+	assert.Equal(t, event.Timestamp.UnixNano(), int64(record.Timestamp()))
+}
+
+func TestToOTelLogRecordDerivesTraceID(t *testing.T) {
+	freeze := models.NewAccountFreeze("acc_1", "corr_1", "fraud review")
+
+	first := ToOTelLogRecord(freeze)
+	second := ToOTelLogRecord(freeze)
+
+	require.False(t, first.TraceID().IsEmpty())
+	assert.Equal(t, first.TraceID(), second.TraceID())
+	assert.Equal(t, plog.SeverityNumberWarn, first.SeverityNumber())
+}
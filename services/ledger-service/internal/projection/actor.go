@@ -0,0 +1,279 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+var (
+	// ErrActorStopped is returned for commands sent to an actor that has shut down or failed
+	ErrActorStopped = errors.New("account actor stopped")
+	// ErrActorFailed is returned for the command an actor was processing when it failed
+	ErrActorFailed = errors.New("account actor failed")
+)
+
+// EventLog reads and appends account streams; every store.EventStore satisfies it
+type EventLog interface {
+	EventReader
+	Append(ctx context.Context, event *models.LedgerEvent) error
+}
+
+// AccountActor serializes an account's appends and balance reads on a single goroutine, so
+// the account's balance is kept in memory without locks. Each append is checked against the
+// in-memory balance, appended to the log at the next version, and only then applied.
+type AccountActor struct {
+	accountID  string
+	log        EventLog
+	projection *BalanceProjection
+	commands   chan actorCommand
+	stop       chan struct{}
+	done       chan struct{}
+	stopOnce   sync.Once
+	err        error
+}
+
+// actorCommand is an append when event is set and a balance query otherwise
+type actorCommand struct {
+	ctx   context.Context
+	event *models.LedgerEvent
+	reply chan actorReply
+}
+
+type actorReply struct {
+	balance Balance
+	err     error
+}
+
+// NewAccountActor replays the account's stream from log into a balance projection and starts
+// the actor's goroutine. Stop it with Stop.
+func NewAccountActor(ctx context.Context, accountID, currency string, log EventLog, opts ...Option) (*AccountActor, error) {
+	p, err := NewBalanceProjection(accountID, currency, opts...)
+	if err != nil {
+		return nil, err
+	}
+	a := &AccountActor{
+		accountID:  accountID,
+		log:        log,
+		projection: p,
+		commands:   make(chan actorCommand),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if err := a.catchUp(ctx); err != nil {
+		return nil, err
+	}
+	go a.run()
+	return a, nil
+}
+
+// Append appends the event at the account's next version, assigning its version, and returns
+// the balance after it. The event is rejected without being appended if the balance cannot
+// take it.
+func (a *AccountActor) Append(ctx context.Context, event *models.LedgerEvent) (Balance, error) {
+	if event.AccountID != a.accountID {
+		return Balance{}, fmt.Errorf("%w: event for %s sent to actor of %s", ErrAccountMismatch, event.AccountID, a.accountID)
+	}
+	return a.send(ctx, event)
+}
+
+// Balance returns the account's balance after every append the actor accepted before it
+func (a *AccountActor) Balance(ctx context.Context) (Balance, error) {
+	return a.send(ctx, nil)
+}
+
+// Stop stops the actor once the command it is processing completes; later commands fail with
+// ErrActorStopped. It is safe to call more than once.
+func (a *AccountActor) Stop() {
+	a.stopOnce.Do(func() { close(a.stop) })
+	<-a.done
+}
+
+// Done is closed when the actor has stopped
+func (a *AccountActor) Done() <-chan struct{} {
+	return a.done
+}
+
+// Err returns why the actor failed, or nil if it is running or was stopped
+func (a *AccountActor) Err() error {
+	select {
+	case <-a.done:
+		return a.err
+	default:
+		return nil
+	}
+}
+
+// send delivers a command and waits for its reply
+func (a *AccountActor) send(ctx context.Context, event *models.LedgerEvent) (Balance, error) {
+	cmd := actorCommand{ctx: ctx, event: event, reply: make(chan actorReply, 1)}
+	select {
+	case a.commands <- cmd:
+	case <-a.done:
+		return Balance{}, fmt.Errorf("%w: account %s", ErrActorStopped, a.accountID)
+	case <-ctx.Done():
+		return Balance{}, ctx.Err()
+	}
+	reply := <-cmd.reply
+	return reply.balance, reply.err
+}
+
+// run processes commands until the actor is stopped or a command fails it
+func (a *AccountActor) run() {
+	defer close(a.done)
+	for {
+		select {
+		case <-a.stop:
+			return
+		case cmd := <-a.commands:
+			if !a.handle(cmd) {
+				return
+			}
+		}
+	}
+}
+
+// handle processes one command, failing the actor if it panics
+func (a *AccountActor) handle(cmd actorCommand) (ok bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			a.err = fmt.Errorf("%w: account %s: %v", ErrActorFailed, a.accountID, recovered)
+			cmd.reply <- actorReply{err: a.err}
+			ok = false
+		}
+	}()
+
+	if cmd.event == nil {
+		cmd.reply <- actorReply{balance: a.projection.Balance()}
+		return true
+	}
+	balance, err := a.append(cmd.ctx, cmd.event)
+	cmd.reply <- actorReply{balance: balance, err: err}
+	return true
+}
+
+// append checks the event against a copy of the projection, appends it and keeps the copy
+func (a *AccountActor) append(ctx context.Context, event *models.LedgerEvent) (Balance, error) {
+	event.Version = a.projection.state.Version + 1
+	next := a.projection.clone()
+	if err := next.Apply(event); err != nil {
+		return Balance{}, err
+	}
+	if err := a.log.Append(ctx, event); err != nil {
+		// Another writer may have appended to the stream; catch up so the next command sees it
+		if catchUpErr := a.catchUp(ctx); catchUpErr != nil {
+			return Balance{}, fmt.Errorf("%w (and failed to catch up: %v)", err, catchUpErr)
+		}
+		return Balance{}, err
+	}
+	a.projection = next
+	return next.Balance(), nil
+}
+
+// catchUp applies the events appended to the log after the projection's version
+func (a *AccountActor) catchUp(ctx context.Context) error {
+	events, err := a.log.Read(ctx, a.accountID, a.projection.state.Version+1)
+	if err != nil {
+		return fmt.Errorf("failed to read account %s: %w", a.accountID, err)
+	}
+	for _, event := range events {
+		if err := a.projection.Apply(event); err != nil {
+			return fmt.Errorf("failed to apply event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// ActorPool supervises one AccountActor per account, starting actors on first use and
+// replacing actors that failed with fresh ones rebuilt from the log
+type ActorPool struct {
+	log    EventLog
+	opts   []Option
+	mu     sync.Mutex
+	actors map[string]*AccountActor
+	closed bool
+}
+
+// NewActorPool creates a pool whose actors read and append through log and build their
+// projections with opts
+func NewActorPool(log EventLog, opts ...Option) *ActorPool {
+	return &ActorPool{log: log, opts: opts, actors: make(map[string]*AccountActor)}
+}
+
+// Actor returns the running actor for the account, starting one if there is none or the
+// previous one failed
+func (p *ActorPool) Actor(ctx context.Context, accountID, currency string) (*AccountActor, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, fmt.Errorf("%w: pool is shut down", ErrActorStopped)
+	}
+	if actor, ok := p.actors[accountID]; ok {
+		select {
+		case <-actor.Done():
+		default:
+			return actor, nil
+		}
+	}
+	actor, err := NewAccountActor(ctx, accountID, currency, p.log, p.opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.actors[accountID] = actor
+	return actor, nil
+}
+
+// Append appends the event through its account's actor
+func (p *ActorPool) Append(ctx context.Context, event *models.LedgerEvent) (Balance, error) {
+	actor, err := p.Actor(ctx, event.AccountID, event.Amount.Currency)
+	if err != nil {
+		return Balance{}, err
+	}
+	return actor.Append(ctx, event)
+}
+
+// Balance returns the account's balance through its actor
+func (p *ActorPool) Balance(ctx context.Context, accountID, currency string) (Balance, error) {
+	actor, err := p.Actor(ctx, accountID, currency)
+	if err != nil {
+		return Balance{}, err
+	}
+	return actor.Balance(ctx)
+}
+
+// Shutdown stops every actor, letting each finish the command it is processing, and waits
+// for them or for ctx to be done
+func (p *ActorPool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	actors := make([]*AccountActor, 0, len(p.actors))
+	for _, actor := range p.actors {
+		actors = append(actors, actor)
+	}
+	p.mu.Unlock()
+
+	stopped := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for _, actor := range actors {
+			wg.Add(1)
+			go func(actor *AccountActor) {
+				defer wg.Done()
+				actor.Stop()
+			}(actor)
+		}
+		wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+package projection
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+func TestAccountActorSerializesConcurrentCommands(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+
+	actor, err := NewAccountActor(ctx, "acc_1", "USD", s)
+	require.NoError(t, err)
+	defer actor.Stop()
+
+	const workers = 50
+	var (
+		wg       sync.WaitGroup
+		rejected atomic.Int32
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			eventType := models.Credit
+			if i%2 == 0 {
+				eventType = models.Debit
+			}
+			_, err := actor.Append(ctx, models.NewLedgerEvent(eventType, usd(3), "acc_1", "corr_1"))
+			if err != nil {
+				assert.ErrorIs(t, err, ErrInsufficientFunds)
+				rejected.Add(1)
+			}
+			_, err = actor.Balance(ctx)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	require.Zero(t, rejected.Load(), "25 debits of 3.00 never exceed the 100.00 credit")
+	balance, err := actor.Balance(ctx)
+	require.NoError(t, err)
+	assert.True(t, balance.Posted.Equal(usd(100)))
+	assert.Equal(t, int64(workers+1), balance.Version)
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, events, workers+1)
+	for i, event := range events {
+		assert.Equal(t, int64(i+1), event.Version)
+	}
+}
+
+func TestAccountActorRejectsOverdraftWithoutAppending(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemoryStore()
+	actor, err := NewAccountActor(ctx, "acc_1", "USD", s)
+	require.NoError(t, err)
+	defer actor.Stop()
+
+	_, err = actor.Append(ctx, models.NewLedgerEvent(models.Debit, usd(5), "acc_1", "corr_1"))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+// panickingLog panics on the append of one event ID, standing in for a crashing dependency
+type panickingLog struct {
+	EventLog
+	eventID string
+}
+
+func (l *panickingLog) Append(ctx context.Context, event *models.LedgerEvent) error {
+	if event.ID == l.eventID {
+		panic("log unavailable")
+	}
+	return l.EventLog.Append(ctx, event)
+}
+
+func TestActorPoolRestartsFailedActors(t *testing.T) {
+	ctx := context.Background()
+	poison := models.NewLedgerEvent(models.Credit, usd(1), "acc_1", "corr_2")
+	pool := NewActorPool(&panickingLog{EventLog: store.NewMemoryStore(), eventID: poison.ID})
+
+	_, err := pool.Append(ctx, models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1"))
+	require.NoError(t, err)
+	first, err := pool.Actor(ctx, "acc_1", "USD")
+	require.NoError(t, err)
+
+	_, err = pool.Append(ctx, poison)
+	require.ErrorIs(t, err, ErrActorFailed)
+	<-first.Done()
+	assert.ErrorIs(t, first.Err(), ErrActorFailed)
+	_, err = first.Balance(ctx)
+	assert.ErrorIs(t, err, ErrActorStopped)
+
+	balance, err := pool.Balance(ctx, "acc_1", "USD")
+	require.NoError(t, err, "the pool replaces the failed actor")
+	assert.True(t, balance.Posted.Equal(usd(10)))
+
+	require.NoError(t, pool.Shutdown(ctx))
+	_, err = pool.Balance(ctx, "acc_1", "USD")
+	assert.ErrorIs(t, err, ErrActorStopped)
+}
@@ -0,0 +1,136 @@
+package projection
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// BalancePoint is an account's posted balance right after an event
+type BalancePoint struct {
+	At      time.Time    `json:"at"`
+	EventID string       `json:"eventId"`
+	Posted  models.Money `json:"posted"`
+}
+
+// Anomaly is a balance movement a detector flagged
+type Anomaly struct {
+	At      time.Time `json:"at"`
+	EventID string    `json:"eventId"`
+	// Movement is the change from the previous point's balance
+	Movement models.Money `json:"movement"`
+	// Score measures how abnormal the movement is; its scale depends on the detector
+	Score float64 `json:"score"`
+}
+
+// AnomalyDetector is fed an account's balance series one point at a time, in order, and
+// reports whether the movement into each point is anomalous, so it can run over a live stream
+type AnomalyDetector interface {
+	Observe(point BalancePoint) (Anomaly, bool)
+}
+
+// BalanceSeries replays the account's events, in order, and returns the posted balance after
+// each event that moves it
+func BalanceSeries(accountID, currency string, events []*models.LedgerEvent) ([]BalancePoint, error) {
+	p, err := NewBalanceProjection(accountID, currency, WithOverdraftPolicy(AllowOverdraft))
+	if err != nil {
+		return nil, err
+	}
+
+	var series []BalancePoint
+	for _, event := range events {
+		if err := p.Apply(event); err != nil {
+			return nil, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		if event.AffectsBalance() {
+			series = append(series, BalancePoint{At: event.EffectiveTime(), EventID: event.ID, Posted: p.Balance().Posted})
+		}
+	}
+	return series, nil
+}
+
+// DetectAnomalies feeds the series to detector and returns the anomalies it flags, in order
+func DetectAnomalies(detector AnomalyDetector, series []BalancePoint) []Anomaly {
+	var anomalies []Anomaly
+	for _, point := range series {
+		if anomaly, ok := detector.Observe(point); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+// EWMADetector flags movements whose z-score against an exponentially weighted moving mean and
+// variance of past movements exceeds a threshold. Recent movements weigh more, so the detector
+// adapts to an account's changing activity; every movement, flagged or not, updates the averages.
+type EWMADetector struct {
+	alpha     float64
+	threshold float64
+	warmup    int
+
+	previous *models.Money
+	seen     int
+	mean     float64
+	variance float64
+}
+
+// NewEWMADetector creates a detector weighting each new movement by alpha, in (0, 1], that
+// flags movements more than threshold standard deviations from the mean once it has seen
+// warmup movements
+func NewEWMADetector(alpha, threshold float64, warmup int) (*EWMADetector, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, fmt.Errorf("EWMA weight must be in (0, 1], got %v", alpha)
+	}
+	if threshold <= 0 {
+		return nil, fmt.Errorf("anomaly threshold must be positive, got %v", threshold)
+	}
+	if warmup < 1 {
+		return nil, fmt.Errorf("warmup must be at least one movement, got %d", warmup)
+	}
+	return &EWMADetector{alpha: alpha, threshold: threshold, warmup: warmup}, nil
+}
+
+// Observe scores the movement from the previous point into point. The first point only sets
+// the baseline; movements are scored once warmup of them have been seen. A movement away from
+// a history of identical movements scores math.MaxFloat64.
+func (d *EWMADetector) Observe(point BalancePoint) (Anomaly, bool) {
+	previous := d.previous
+	posted := point.Posted
+	d.previous = &posted
+	if previous == nil {
+		return Anomaly{}, false
+	}
+
+	movement, err := posted.Sub(*previous)
+	if err != nil {
+		// A currency or precision change starts a new series rather than moving the balance
+		return Anomaly{}, false
+	}
+	value := movement.Float()
+
+	var score float64
+	deviation := value - d.mean
+	if d.seen >= d.warmup {
+		switch {
+		case d.variance > 0:
+			score = math.Abs(deviation) / math.Sqrt(d.variance)
+		case deviation != 0:
+			score = math.MaxFloat64
+		}
+	}
+
+	if d.seen == 0 {
+		d.mean = value
+	} else {
+		d.mean += d.alpha * deviation
+		d.variance = (1 - d.alpha) * (d.variance + d.alpha*deviation*deviation)
+	}
+	d.seen++
+
+	if score <= d.threshold {
+		return Anomaly{}, false
+	}
+	return Anomaly{At: point.At, EventID: point.EventID, Movement: movement, Score: score}, true
+}
@@ -0,0 +1,51 @@
+package projection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestEWMADetectorFlagsSuddenLargeDebit(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	events := []*models.LedgerEvent{event(models.Credit, 1000, 1)}
+	for i := 0; i < 30; i++ {
+		eventType, amount := models.Credit, 20+float64(i%5)
+		if i%2 == 1 {
+			eventType = models.Debit
+		}
+		events = append(events, event(eventType, amount, int64(len(events)+1)))
+	}
+	spike := event(models.Debit, 900, int64(len(events)+1))
+	events = append(events, spike, event(models.Credit, 21, int64(len(events)+2)))
+	for i, e := range events {
+		e.Timestamp = start.Add(time.Duration(i) * time.Hour)
+	}
+
+	series, err := BalanceSeries("acc_1", "USD", events)
+	require.NoError(t, err)
+	require.Len(t, series, len(events))
+
+	detector, err := NewEWMADetector(0.2, 4, 10)
+	require.NoError(t, err)
+	anomalies := DetectAnomalies(detector, series)
+
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, spike.ID, anomalies[0].EventID)
+	assert.Equal(t, spike.Timestamp, anomalies[0].At)
+	assert.Equal(t, usd(-900), anomalies[0].Movement)
+	assert.Greater(t, anomalies[0].Score, 4.0)
+}
+
+func TestNewEWMADetectorRejectsBadParameters(t *testing.T) {
+	_, err := NewEWMADetector(0, 3, 5)
+	assert.Error(t, err)
+	_, err = NewEWMADetector(0.5, 0, 5)
+	assert.Error(t, err)
+	_, err = NewEWMADetector(0.5, 3, 0)
+	assert.Error(t, err)
+}
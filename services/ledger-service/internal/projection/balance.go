@@ -0,0 +1,280 @@
+package projection
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+var (
+	// ErrAccountMismatch is returned when an event belongs to a different account than the projection
+	ErrAccountMismatch = models.ErrAccountMismatch
+	// ErrInsufficientFunds is returned when a debit or hold exceeds the available balance and overdraft is not allowed
+	ErrInsufficientFunds = models.ErrInsufficientFunds
+	// ErrReleaseExceedsHold is returned when a release is larger than the amount currently held
+	ErrReleaseExceedsHold = models.ErrReleaseExceedsHold
+	// ErrUnknownHold is returned when a release or capture names a hold that is not active
+	ErrUnknownHold = models.ErrUnknownHold
+)
+
+// OverdraftPolicy controls whether debits and holds may take the available balance below zero
+type OverdraftPolicy int
+
+const (
+	// RejectOverdraft rejects debits and holds larger than the available balance
+	RejectOverdraft OverdraftPolicy = iota
+	// AllowOverdraft lets the available balance go negative
+	AllowOverdraft
+)
+
+// ErrorMode controls whether a batch keeps applying events after one fails
+type ErrorMode int
+
+const (
+	// StopOnError stops a batch at the first failing event
+	StopOnError ErrorMode = iota
+	// ContinueOnError skips failing events and applies the rest
+	ContinueOnError
+)
+
+// Balance is the state of an account after applying its events
+type Balance struct {
+	AccountID string       `json:"accountId"`
+	Posted    models.Money `json:"posted"`
+	Held      models.Money `json:"held"`
+	Version   int64        `json:"version"`
+}
+
+// Available returns the posted balance minus the held amount
+func (b Balance) Available() models.Money {
+	available := b.Posted
+	available.MinorUnits -= b.Held.MinorUnits
+	return available
+}
+
+// BalanceDelta is the net change a set of events makes to a balance
+type BalanceDelta struct {
+	Posted  models.Money `json:"posted"`
+	Held    models.Money `json:"held"`
+	Applied int          `json:"applied"`
+}
+
+// EventError reports an event of a batch that could not be applied
+type EventError struct {
+	Index   int
+	EventID string
+	Err     error
+}
+
+// Error describes the failing event and the reason
+func (e *EventError) Error() string {
+	return fmt.Sprintf("event %d (%s): %v", e.Index, e.EventID, e.Err)
+}
+
+// Unwrap returns the underlying failure
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+// Option configures a BalanceProjection
+type Option func(*BalanceProjection)
+
+// WithOverdraftPolicy sets the overdraft policy; the default is RejectOverdraft
+func WithOverdraftPolicy(policy OverdraftPolicy) Option {
+	return func(p *BalanceProjection) {
+		p.fold.overdraft = policy
+	}
+}
+
+// WithClock sets the clock that hold expiry is evaluated against; the default is the system clock
+func WithClock(clock models.Clock) Option {
+	return func(p *BalanceProjection) {
+		p.fold.clock = clock
+	}
+}
+
+// WithErrorMode sets how batches handle failing events; the default is StopOnError
+func WithErrorMode(mode ErrorMode) Option {
+	return func(p *BalanceProjection) {
+		p.errorMode = mode
+	}
+}
+
+// WithOrderBy sets the ordering BalanceAsOf replays events in and cuts them off by; the default is models.Recorded
+func WithOrderBy(order models.EventOrder) Option {
+	return func(p *BalanceProjection) {
+		p.orderBy = order
+	}
+}
+
+// WithPriorityOrder makes batches apply events in models.PriorityOrder under the projection's
+// ordering, so higher-priority events go first where versions allow; failures still report
+// the event's index in the batch as given
+func WithPriorityOrder() Option {
+	return func(p *BalanceProjection) {
+		p.priority = true
+	}
+}
+
+// BalanceProjection folds an account's events into its posted and held balance through its
+// BalanceFold, evaluating hold expiry and validity windows at its clock's time when each event
+// is applied; use BalanceAsOf to evaluate windowed events at a different instant.
+type BalanceProjection struct {
+	fold      *BalanceFold
+	state     models.BalanceState
+	errorMode ErrorMode
+	orderBy   models.EventOrder
+	priority  bool
+}
+
+// NewBalanceProjection creates an empty projection for an account in the given currency
+func NewBalanceProjection(accountID, currency string, opts ...Option) (*BalanceProjection, error) {
+	zero, err := models.ZeroMoney(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &BalanceProjection{
+		fold: &BalanceFold{accountID: accountID, zero: zero, clock: models.SystemClock{}},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.state = p.fold.Initial()
+	return p, nil
+}
+
+// Balance returns the current balance, counting only holds that have not expired
+func (p *BalanceProjection) Balance() Balance {
+	return p.fold.Balance(p.state)
+}
+
+// BalanceAsOf replays the events up to asOf and returns the balance at that instant,
+// treating holds as expired relative to asOf rather than the current time. By default events
+// are replayed in recorded order and cut off by recording time; WithOrderBy(models.Effective)
+// uses effective time instead, so a backdated event counts from the date it takes effect.
+// Events with a validity window count only if asOf falls inside it.
+func BalanceAsOf(accountID, currency string, events []*models.LedgerEvent, asOf time.Time, opts ...Option) (Balance, error) {
+	opts = append(opts, WithClock(models.FixedClock(asOf)), WithErrorMode(StopOnError))
+	p, err := NewBalanceProjection(accountID, currency, opts...)
+	if err != nil {
+		return Balance{}, err
+	}
+
+	for _, event := range models.SortEvents(events, p.orderBy) {
+		if event.TimeFor(p.orderBy).After(asOf) {
+			continue
+		}
+		if err := p.Apply(event); err != nil {
+			return Balance{}, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+	}
+	return p.Balance(), nil
+}
+
+// ProjectBalance folds an account's events, taken in Version order whatever order they are
+// given in, into its balance: debits, credits and adjustments move the posted balance, holds
+// and releases the held amount, and reversals undo them. Events are replayed as ReplayBalance
+// does, at their own timestamps with overdrafts allowed. It fails on the first event for
+// another account, in another currency than the first monetary event's, or that the balance
+// cannot take, such as a release of more than is held, returning the
+// balance reached before it. The balance's Version is that of the last event applied, so
+// callers can compare it with the stream's head to detect gaps.
+func ProjectBalance(accountID string, events []*models.LedgerEvent) (Balance, error) {
+	ordered := append([]*models.LedgerEvent(nil), events...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Version < ordered[j].Version
+	})
+	return ReplayBalance(accountID, ordered)
+}
+
+// Apply applies a single event, leaving the balance unchanged if it fails
+func (p *BalanceProjection) Apply(event *models.LedgerEvent) error {
+	state, err := p.fold.Apply(p.state, event)
+	if err != nil {
+		return err
+	}
+	p.state = state
+	return nil
+}
+
+// ApplyBatch applies events in order and returns the failures. In StopOnError mode
+// the first failure ends the batch; events before it stay applied.
+func (p *BalanceProjection) ApplyBatch(events []*models.LedgerEvent) []error {
+	_, errs := p.applyBatch(events)
+	return errs
+}
+
+// applyBatch applies events per the error mode, returning how many were applied and the failures
+func (p *BalanceProjection) applyBatch(events []*models.LedgerEvent) (int, []error) {
+	var (
+		applied int
+		errs    []error
+	)
+	ordered, positions := events, []int(nil)
+	if p.priority {
+		ordered = models.PriorityOrder(events, p.orderBy)
+		positions = batchPositions(events, ordered)
+	}
+	for i, event := range ordered {
+		if err := p.Apply(event); err != nil {
+			if positions != nil {
+				i = positions[i]
+			}
+			errs = append(errs, &EventError{Index: i, EventID: event.ID, Err: err})
+			if p.errorMode == StopOnError {
+				break
+			}
+			continue
+		}
+		applied++
+	}
+	return applied, errs
+}
+
+// batchPositions returns the index in batch of each event of its reordering
+func batchPositions(batch, reordered []*models.LedgerEvent) []int {
+	index := make(map[*models.LedgerEvent]int, len(batch))
+	for i, event := range batch {
+		index[event] = i
+	}
+	positions := make([]int, len(reordered))
+	for i, event := range reordered {
+		positions[i] = index[event]
+	}
+	return positions
+}
+
+// PreviewBatch reports the net change ApplyBatch would make, and the failures it would hit,
+// by applying the events to a copy of the projection
+func (p *BalanceProjection) PreviewBatch(events []*models.LedgerEvent) (BalanceDelta, []error) {
+	preview := p.clone()
+	applied, errs := preview.applyBatch(events)
+
+	before, after := p.Balance(), preview.Balance()
+	delta := BalanceDelta{
+		Posted:  after.Posted,
+		Held:    after.Held,
+		Applied: applied,
+	}
+	delta.Posted.MinorUnits -= before.Posted.MinorUnits
+	delta.Held.MinorUnits -= before.Held.MinorUnits
+	return delta, errs
+}
+
+// clone returns an independent copy of the projection
+func (p *BalanceProjection) clone() *BalanceProjection {
+	copied := *p
+	copied.state = p.fold.Copy(p.state)
+	return &copied
+}
+
+// maxVersion keeps the balance version at the highest applied event when events are replayed out of version order
+func maxVersion(current, applied int64) int64 {
+	if applied > current {
+		return applied
+	}
+	return current
+}
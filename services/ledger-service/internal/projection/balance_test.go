@@ -0,0 +1,275 @@
+package projection
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func usd(amount float64) models.Money {
+	return models.NewMoney(int64(math.Round(amount*100)), "USD", 2)
+}
+
+func event(eventType models.EventType, amount float64, version int64) *models.LedgerEvent {
+	return models.NewLedgerEvent(eventType, usd(amount), "acc_1", "corr_1").WithVersion(version)
+}
+
+func mixedBatch() []*models.LedgerEvent {
+	return []*models.LedgerEvent{
+		event(models.Credit, 100, 1),
+		event(models.Debit, 30, 2),
+		event(models.Hold, 20, 3),
+		event(models.Debit, 200, 4),
+		event(models.Release, 10, 5),
+	}
+}
+
+func TestPreviewBatchMatchesApply(t *testing.T) {
+	p, err := NewBalanceProjection("acc_1", "USD", WithErrorMode(ContinueOnError))
+	require.NoError(t, err)
+
+	delta, previewErrs := p.PreviewBatch(mixedBatch())
+	assert.Equal(t, usd(0), p.Balance().Posted, "preview must not mutate the projection")
+
+	assert.Equal(t, usd(70), delta.Posted)
+	assert.Equal(t, usd(10), delta.Held)
+	assert.Equal(t, 4, delta.Applied)
+	require.Len(t, previewErrs, 1)
+	assert.ErrorIs(t, previewErrs[0], ErrInsufficientFunds)
+
+	applyErrs := p.ApplyBatch(mixedBatch())
+	require.Len(t, applyErrs, 1)
+	assert.Equal(t, previewErrs[0].(*EventError).Index, applyErrs[0].(*EventError).Index)
+	assert.Equal(t, delta.Posted, p.Balance().Posted)
+	assert.Equal(t, delta.Held, p.Balance().Held)
+	assert.Equal(t, usd(60), p.Balance().Available())
+}
+
+func TestPreviewBatchStopsAtFirstError(t *testing.T) {
+	p, err := NewBalanceProjection("acc_1", "USD")
+	require.NoError(t, err)
+
+	delta, errs := p.PreviewBatch(mixedBatch())
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, 3, errs[0].(*EventError).Index)
+	assert.Equal(t, 3, delta.Applied)
+	assert.Equal(t, usd(70), delta.Posted)
+	assert.Equal(t, usd(20), delta.Held)
+}
+
+func TestPreviewBatchAllowsOverdraft(t *testing.T) {
+	p, err := NewBalanceProjection("acc_1", "USD", WithOverdraftPolicy(AllowOverdraft))
+	require.NoError(t, err)
+
+	delta, errs := p.PreviewBatch(mixedBatch())
+
+	assert.Empty(t, errs)
+	assert.Equal(t, usd(-130), delta.Posted)
+	assert.Equal(t, 5, delta.Applied)
+}
+
+func TestBalanceAsOfEvaluatesHoldExpiryAtAsOfTime(t *testing.T) {
+	placed := time.Now().UTC().Add(-48 * time.Hour)
+	expiresAt := placed.Add(time.Hour)
+
+	credit := event(models.Credit, 100, 1)
+	credit.Timestamp = placed.Add(-time.Minute)
+	hold := event(models.Hold, 40, 2)
+	hold.Timestamp = placed
+	hold.ExpiresAt = &expiresAt
+	events := []*models.LedgerEvent{credit, hold}
+
+	p, err := NewBalanceProjection("acc_1", "USD")
+	require.NoError(t, err)
+	require.Empty(t, p.ApplyBatch(events))
+	assert.Equal(t, usd(0), p.Balance().Held, "hold has expired relative to now")
+	assert.Equal(t, usd(100), p.Balance().Available())
+
+	asOf, err := BalanceAsOf("acc_1", "USD", events, placed.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, usd(40), asOf.Held, "hold is live relative to the as-of time")
+	assert.Equal(t, usd(60), asOf.Available())
+
+	afterExpiry, err := BalanceAsOf("acc_1", "USD", events, expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, usd(0), afterExpiry.Held)
+}
+
+func TestBalanceAsOfIgnoresLaterEvents(t *testing.T) {
+	first := event(models.Credit, 100, 1)
+	second := event(models.Credit, 50, 2)
+	second.Timestamp = first.Timestamp.Add(time.Hour)
+
+	balance, err := BalanceAsOf("acc_1", "USD", []*models.LedgerEvent{first, second}, first.Timestamp)
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), balance.Posted)
+	assert.Equal(t, int64(1), balance.Version)
+}
+
+func TestBalanceAsOfRecordedVersusEffectiveOrder(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	credit := event(models.Credit, 100, 1)
+	credit.Timestamp = day(1)
+	debit := event(models.Debit, 40, 2)
+	debit.Timestamp = day(3)
+	// Recorded on the 5th but backdated to take effect on the 2nd
+	backdated := event(models.Credit, 25, 3).WithEffectiveAt(day(2))
+	backdated.Timestamp = day(5)
+	events := []*models.LedgerEvent{credit, debit, backdated}
+
+	recorded, err := BalanceAsOf("acc_1", "USD", events, day(4))
+	require.NoError(t, err)
+	assert.Equal(t, usd(60), recorded.Posted)
+	assert.Equal(t, int64(2), recorded.Version)
+
+	effective, err := BalanceAsOf("acc_1", "USD", events, day(4), WithOrderBy(models.Effective))
+	require.NoError(t, err)
+	assert.Equal(t, usd(85), effective.Posted)
+	assert.Equal(t, int64(3), effective.Version)
+
+	// Both views agree once every event is recorded and effective
+	for _, order := range []models.EventOrder{models.Recorded, models.Effective} {
+		final, err := BalanceAsOf("acc_1", "USD", events, day(6), WithOrderBy(order))
+		require.NoError(t, err)
+		assert.Equal(t, usd(85), final.Posted, order.String())
+	}
+}
+
+func TestBalanceAsOfHonoursValidityWindow(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	base := eventAt(models.Credit, 100, 1, start.Add(-48*time.Hour))
+	promotion := eventAt(models.Credit, 20, 2, start.Add(-24*time.Hour)).WithValidity(&start, &end)
+	events := []*models.LedgerEvent{base, promotion}
+
+	before, err := BalanceAsOf("acc_1", "USD", events, start.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), before.Posted)
+	assert.Equal(t, int64(2), before.Version, "a skipped event still advances the version")
+
+	during, err := BalanceAsOf("acc_1", "USD", events, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, usd(120), during.Posted)
+
+	after, err := BalanceAsOf("acc_1", "USD", events, end)
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), after.Posted)
+}
+
+func TestProjectBalanceAppliesInVersionOrder(t *testing.T) {
+	events := []*models.LedgerEvent{
+		event(models.Release, 5, 4),
+		event(models.Debit, 30, 2),
+		event(models.Credit, 100, 1),
+		event(models.Hold, 20, 3),
+		event(models.Adjustment, 2.5, 5),
+	}
+
+	balance, err := ProjectBalance("acc_1", events)
+	require.NoError(t, err)
+	assert.Equal(t, usd(72.5), balance.Posted)
+	assert.Equal(t, usd(15), balance.Held)
+	assert.Equal(t, usd(57.5), balance.Available())
+	assert.Equal(t, int64(5), balance.Version)
+	assert.Equal(t, models.Release, events[0].Type, "input order must be left alone")
+}
+
+func TestNegativeAdjustmentReducesBalance(t *testing.T) {
+	correction := event(models.Adjustment, -12.5, 2)
+	require.NoError(t, correction.Validate())
+
+	balance, err := ProjectBalance("acc_1", []*models.LedgerEvent{event(models.Credit, 100, 1), correction})
+	require.NoError(t, err)
+	assert.Equal(t, usd(87.5), balance.Posted)
+	assert.Equal(t, int64(2), balance.Version)
+}
+
+func TestReversalUndoesReversedEvent(t *testing.T) {
+	credit := event(models.Credit, 100, 1)
+	debit := event(models.Debit, 30, 2)
+	events := []*models.LedgerEvent{credit, debit, models.NewReversal(debit, "corr_2").WithVersion(3)}
+
+	balance, err := ProjectBalance("acc_1", events)
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), balance.Posted, "a reversed debit is returned")
+
+	events = append(events, models.NewReversal(credit, "corr_3").WithVersion(4))
+	balance, err = ProjectBalance("acc_1", events)
+	require.NoError(t, err)
+	assert.Equal(t, usd(0), balance.Posted, "a reversed credit is taken back")
+	assert.Equal(t, int64(4), balance.Version)
+
+	events = append(events, models.NewReversal(credit, "corr_4").WithVersion(5))
+	_, err = ProjectBalance("acc_1", events)
+	assert.ErrorIs(t, err, models.ErrAlreadyReversed)
+}
+
+func TestProjectBalanceRejectsForeignEvents(t *testing.T) {
+	other := models.NewLedgerEvent(models.Credit, usd(10), "acc_2", "corr_1").WithVersion(2)
+	balance, err := ProjectBalance("acc_1", []*models.LedgerEvent{other, event(models.Credit, 50, 1)})
+	assert.ErrorIs(t, err, ErrAccountMismatch)
+	assert.Equal(t, usd(50), balance.Posted)
+	assert.Equal(t, int64(1), balance.Version)
+
+	euros := models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "EUR", 2), "acc_1", "corr_1").WithVersion(2)
+	_, err = ProjectBalance("acc_1", []*models.LedgerEvent{euros, event(models.Credit, 50, 1)})
+	assert.ErrorIs(t, err, models.ErrCurrencyMismatch)
+}
+
+func TestPriorityOrderAppliesHigherPriorityFirst(t *testing.T) {
+	debit := event(models.Debit, 50, 0)
+	credit := event(models.Credit, 100, 0).WithPriority(1)
+	credit.Timestamp = debit.Timestamp
+	batch := []*models.LedgerEvent{debit, credit}
+
+	p, err := NewBalanceProjection("acc_1", "USD")
+	require.NoError(t, err)
+	errs := p.ApplyBatch(batch)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrInsufficientFunds)
+
+	p, err = NewBalanceProjection("acc_1", "USD", WithPriorityOrder())
+	require.NoError(t, err)
+	assert.Empty(t, p.ApplyBatch(batch))
+	assert.Equal(t, usd(50), p.Balance().Posted)
+
+	overdrawn := event(models.Debit, 500, 0)
+	overdrawn.Timestamp = debit.Timestamp
+	errs = p.ApplyBatch([]*models.LedgerEvent{debit, overdrawn, credit})
+	require.Len(t, errs, 1)
+	assert.Equal(t, 1, errs[0].(*EventError).Index, "failures report the position in the batch as given")
+}
+
+func TestPartialReleasesDrawOnTheirHold(t *testing.T) {
+	p, err := NewBalanceProjection("acc_1", "USD")
+	require.NoError(t, err)
+	hold := event(models.Hold, 100, 2)
+	other := event(models.Hold, 50, 3)
+	require.Empty(t, p.ApplyBatch([]*models.LedgerEvent{event(models.Credit, 200, 1), hold, other}))
+
+	require.Empty(t, p.ApplyBatch([]*models.LedgerEvent{
+		event(models.Release, 30, 4).WithHoldID(hold.ID),
+		event(models.Debit, 45.5, 5).WithHoldID(hold.ID),
+		event(models.Release, 24.5, 6).WithHoldID(hold.ID),
+	}))
+	balance := p.Balance()
+	assert.Equal(t, usd(154.5), balance.Posted)
+	assert.Equal(t, usd(50), balance.Held, "the other hold is untouched")
+	assert.Equal(t, usd(104.5), balance.Available())
+
+	err = p.Apply(event(models.Release, 0.01, 7).WithHoldID(hold.ID))
+	assert.ErrorIs(t, err, ErrReleaseExceedsHold)
+	err = p.Apply(event(models.Debit, 50.01, 7).WithHoldID(other.ID))
+	assert.ErrorIs(t, err, ErrReleaseExceedsHold)
+	err = p.Apply(event(models.Release, 1, 7).WithHoldID("evt_missing"))
+	assert.ErrorIs(t, err, ErrUnknownHold)
+	assert.Equal(t, balance, p.Balance(), "rejected draws leave the balance unchanged")
+}
@@ -0,0 +1,176 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrCursorAhead is returned when a saved cursor is past the saved state, meaning the state
+// lost events the cursor claims were processed
+var ErrCursorAhead = errors.New("cursor ahead of projection state")
+
+// CursorStore persists the stream version a projection has processed up to, per account
+type CursorStore interface {
+	// LoadCursor returns the account's last processed version, or 0 if there is none
+	LoadCursor(ctx context.Context, accountID string) (int64, error)
+	// SaveCursor records the account's last processed version
+	SaveCursor(ctx context.Context, accountID string, version int64) error
+}
+
+// CheckpointCommitter is implemented by stores that hold both a projection's state and its
+// cursor and can write them together, in one transaction or under one lock
+type CheckpointCommitter[S any] interface {
+	Commit(ctx context.Context, accountID string, snapshot Snapshot[S]) error
+}
+
+// CheckpointedProjection keeps a projection's state and its cursor in step, so a consumer that
+// restarts resumes where it left off without applying any event twice. When the state store
+// is also a CheckpointCommitter both are written atomically; otherwise the state is written
+// first, and events already folded into a saved state are skipped on resume.
+type CheckpointedProjection[S any] struct {
+	accountID  string
+	projection Projection[S]
+	states     SnapshotStore[S]
+	cursors    CursorStore
+}
+
+// NewCheckpointedProjection creates a checkpointed consumer of the account's stream
+func NewCheckpointedProjection[S any](accountID string, projection Projection[S], states SnapshotStore[S], cursors CursorStore) *CheckpointedProjection[S] {
+	return &CheckpointedProjection[S]{
+		accountID:  accountID,
+		projection: projection,
+		states:     states,
+		cursors:    cursors,
+	}
+}
+
+// ProcessUpTo applies the account's events after the saved cursor up to and including version
+// upTo, then checkpoints the new state and cursor. If an event fails, the events before it are
+// still checkpointed and the failure is returned.
+func (c *CheckpointedProjection[S]) ProcessUpTo(ctx context.Context, reader EventReader, upTo int64) (S, error) {
+	state := c.projection.Initial()
+	snapshot, ok, err := c.states.Load(ctx, c.accountID)
+	if err != nil {
+		return state, fmt.Errorf("failed to load state of account %s: %w", c.accountID, err)
+	}
+	if ok {
+		state = copyState(c.projection, snapshot.State)
+	}
+
+	cursor, err := c.cursors.LoadCursor(ctx, c.accountID)
+	if err != nil {
+		return state, fmt.Errorf("failed to load cursor of account %s: %w", c.accountID, err)
+	}
+	if cursor > snapshot.Version {
+		return state, fmt.Errorf("%w: account %s cursor at %d, state at %d", ErrCursorAhead, c.accountID, cursor, snapshot.Version)
+	}
+
+	events, err := reader.Read(ctx, c.accountID, cursor+1)
+	if err != nil {
+		return state, err
+	}
+
+	version := snapshot.Version
+	var applyErr error
+	for _, event := range events {
+		if event.Version > upTo {
+			break
+		}
+		// A state saved without its cursor already includes these events
+		if event.Version <= snapshot.Version {
+			continue
+		}
+		next, err := c.projection.Apply(state, event)
+		if err != nil {
+			applyErr = fmt.Errorf("event %s: %w", event.ID, err)
+			break
+		}
+		state, version = next, event.Version
+	}
+
+	if version != cursor {
+		if err := c.commit(ctx, Snapshot[S]{Version: version, State: copyState(c.projection, state)}); err != nil {
+			return state, err
+		}
+	}
+	return state, applyErr
+}
+
+// commit writes the state and cursor, atomically when the state store supports it
+func (c *CheckpointedProjection[S]) commit(ctx context.Context, snapshot Snapshot[S]) error {
+	if committer, ok := c.states.(CheckpointCommitter[S]); ok {
+		if err := committer.Commit(ctx, c.accountID, snapshot); err != nil {
+			return fmt.Errorf("failed to checkpoint account %s at %d: %w", c.accountID, snapshot.Version, err)
+		}
+		return nil
+	}
+	if err := c.states.Save(ctx, c.accountID, snapshot); err != nil {
+		return fmt.Errorf("failed to save state of account %s at %d: %w", c.accountID, snapshot.Version, err)
+	}
+	if err := c.cursors.SaveCursor(ctx, c.accountID, snapshot.Version); err != nil {
+		return fmt.Errorf("failed to save cursor of account %s at %d: %w", c.accountID, snapshot.Version, err)
+	}
+	return nil
+}
+
+// MemoryCheckpointStore keeps projection states and cursors in memory and commits them together
+type MemoryCheckpointStore[S any] struct {
+	mu      sync.RWMutex
+	states  map[string]Snapshot[S]
+	cursors map[string]int64
+}
+
+// NewMemoryCheckpointStore creates an empty checkpoint store
+func NewMemoryCheckpointStore[S any]() *MemoryCheckpointStore[S] {
+	return &MemoryCheckpointStore[S]{
+		states:  make(map[string]Snapshot[S]),
+		cursors: make(map[string]int64),
+	}
+}
+
+// Load returns the account's saved state
+func (m *MemoryCheckpointStore[S]) Load(ctx context.Context, accountID string) (Snapshot[S], bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot, ok := m.states[accountID]
+	return snapshot, ok, nil
+}
+
+// Save replaces the account's state without moving its cursor
+func (m *MemoryCheckpointStore[S]) Save(ctx context.Context, accountID string, snapshot Snapshot[S]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[accountID] = snapshot
+	return nil
+}
+
+// LoadCursor returns the account's saved cursor
+func (m *MemoryCheckpointStore[S]) LoadCursor(ctx context.Context, accountID string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.cursors[accountID], nil
+}
+
+// SaveCursor replaces the account's cursor without touching its state
+func (m *MemoryCheckpointStore[S]) SaveCursor(ctx context.Context, accountID string, version int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cursors[accountID] = version
+	return nil
+}
+
+// Commit replaces the account's state and moves its cursor to the state's version under one lock
+func (m *MemoryCheckpointStore[S]) Commit(ctx context.Context, accountID string, snapshot Snapshot[S]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[accountID] = snapshot
+	m.cursors[accountID] = snapshot.Version
+	return nil
+}
@@ -0,0 +1,74 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// crashingCursors fails cursor writes while crash is set, simulating a crash after the state write
+type crashingCursors struct {
+	CursorStore
+	crash bool
+}
+
+func (c *crashingCursors) SaveCursor(ctx context.Context, accountID string, version int64) error {
+	if c.crash {
+		return errors.New("crashed")
+	}
+	return c.CursorStore.SaveCursor(ctx, accountID, version)
+}
+
+func TestCheckpointedProjectionResumesFromCursor(t *testing.T) {
+	ctx := context.Background()
+	reader := &recordingReader{EventReader: seededStore(t)}
+	checkpoints := NewMemoryCheckpointStore[map[models.EventType]int]()
+	consumer := NewCheckpointedProjection[map[models.EventType]int]("acc_1", countByType{}, checkpoints, checkpoints)
+
+	counts, err := consumer.ProcessUpTo(ctx, reader, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.EventType]int{models.Credit: 1, models.Debit: 1}, counts)
+
+	counts, err = consumer.ProcessUpTo(ctx, reader, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.EventType]int{models.Credit: 1, models.Debit: 1, models.Hold: 1, models.Release: 1}, counts)
+	assert.Equal(t, []int64{1, 3}, reader.from)
+
+	cursor, err := checkpoints.LoadCursor(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), cursor)
+}
+
+func TestCheckpointedProjectionCrashBetweenWritesDoesNotDoubleApply(t *testing.T) {
+	ctx := context.Background()
+	reader := seededStore(t)
+	states := NewMemorySnapshotStore[map[models.EventType]int]()
+	cursors := &crashingCursors{CursorStore: NewMemoryCheckpointStore[map[models.EventType]int](), crash: true}
+
+	_, err := NewCheckpointedProjection[map[models.EventType]int]("acc_1", countByType{}, states, cursors).ProcessUpTo(ctx, reader, 3)
+	require.Error(t, err)
+
+	cursors.crash = false
+	restarted := NewCheckpointedProjection[map[models.EventType]int]("acc_1", countByType{}, states, cursors)
+	counts, err := restarted.ProcessUpTo(ctx, reader, 10)
+	require.NoError(t, err)
+	assert.Equal(t, map[models.EventType]int{models.Credit: 1, models.Debit: 1, models.Hold: 1, models.Release: 1}, counts)
+
+	cursor, err := cursors.LoadCursor(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), cursor)
+}
+
+func TestCheckpointedProjectionCursorAheadOfState(t *testing.T) {
+	ctx := context.Background()
+	checkpoints := NewMemoryCheckpointStore[map[models.EventType]int]()
+	require.NoError(t, checkpoints.SaveCursor(ctx, "acc_1", 2))
+
+	_, err := NewCheckpointedProjection[map[models.EventType]int]("acc_1", countByType{}, checkpoints, checkpoints).ProcessUpTo(ctx, seededStore(t), 10)
+	assert.ErrorIs(t, err, ErrCursorAhead)
+}
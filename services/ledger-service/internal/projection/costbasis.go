@@ -0,0 +1,124 @@
+package projection
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Metadata keys carrying the asset side of credits (acquisitions) and debits (disposals)
+const (
+	MetaQuantity = "quantity"
+	MetaUnitCost = "unitCost"
+)
+
+var (
+	// ErrInvalidLotMetadata is returned when an event's quantity or unit cost is missing or malformed
+	ErrInvalidLotMetadata = errors.New("invalid quantity or unit cost metadata")
+	// ErrInsufficientQuantity is returned when a disposal exceeds the quantity held
+	ErrInsufficientQuantity = errors.New("insufficient quantity")
+)
+
+// CostBasis is the holding of a fungible asset and its weighted-average cost
+type CostBasis struct {
+	AccountID   string
+	Quantity    models.Decimal
+	AverageCost models.Decimal
+	// Realized is the gain, or loss when negative, of all disposals so far
+	Realized models.Decimal
+	Version  int64
+}
+
+// CostBasisProjection tracks weighted-average cost basis from events whose metadata carries a
+// quantity and unit cost. A credit acquires quantity at its unit cost and re-weights the average;
+// a debit disposes of quantity at its unit cost, the sale price, realizing
+// quantity × (price − average). Other event types leave the basis unchanged.
+type CostBasisProjection struct {
+	basis    CostBasis
+	currency string
+}
+
+// NewCostBasisProjection creates an empty cost basis for an account priced in the given currency
+func NewCostBasisProjection(accountID, currency string) (*CostBasisProjection, error) {
+	if _, err := models.ZeroMoney(currency); err != nil {
+		return nil, err
+	}
+	return &CostBasisProjection{basis: CostBasis{AccountID: accountID}, currency: currency}, nil
+}
+
+// CostBasis returns the current holding, average cost and realized gain
+func (p *CostBasisProjection) CostBasis() CostBasis {
+	return p.basis
+}
+
+// Apply applies a single event, leaving the basis unchanged if it fails
+func (p *CostBasisProjection) Apply(event *models.LedgerEvent) error {
+	if event.AccountID != p.basis.AccountID {
+		return fmt.Errorf("%w: event for %s applied to %s", ErrAccountMismatch, event.AccountID, p.basis.AccountID)
+	}
+	if !event.IsCredit() && !event.IsDebit() {
+		p.basis.Version = maxVersion(p.basis.Version, event.Version)
+		return nil
+	}
+	if event.Amount.Currency != p.currency {
+		return fmt.Errorf("%w: %s event applied to %s cost basis", models.ErrCurrencyMismatch, event.Amount.Currency, p.currency)
+	}
+
+	quantity, err := lotValue(event, MetaQuantity)
+	if err != nil {
+		return err
+	}
+	if quantity.Sign() == 0 {
+		return fmt.Errorf("%w: event %s %s must be positive", ErrInvalidLotMetadata, event.ID, MetaQuantity)
+	}
+	unitCost, err := lotValue(event, MetaUnitCost)
+	if err != nil {
+		return err
+	}
+
+	next := p.basis
+	if event.IsCredit() {
+		held := next.Quantity.Mul(next.AverageCost)
+		next.Quantity = next.Quantity.Add(quantity)
+		next.AverageCost = held.Add(quantity.Mul(unitCost)).Quo(next.Quantity)
+	} else {
+		if quantity.Cmp(next.Quantity) > 0 {
+			return fmt.Errorf("%w: disposing of %s with %s held", ErrInsufficientQuantity, quantity, next.Quantity)
+		}
+		next.Realized = next.Realized.Add(quantity.Mul(unitCost.Sub(next.AverageCost)))
+		next.Quantity = next.Quantity.Sub(quantity)
+		// A full liquidation starts the next acquisition from a clean basis
+		if next.Quantity.Sign() == 0 {
+			next.AverageCost = models.Decimal{}
+		}
+	}
+
+	next.Version = maxVersion(next.Version, event.Version)
+	p.basis = next
+	return nil
+}
+
+// lotValue reads a non-negative decimal from the event's metadata, accepting numbers and decimal strings
+func lotValue(event *models.LedgerEvent, key string) (models.Decimal, error) {
+	var (
+		value models.Decimal
+		err   error
+	)
+	switch raw := event.Metadata[key].(type) {
+	case float64:
+		value, err = models.ParseDecimal(strconv.FormatFloat(raw, 'f', -1, 64))
+	case string:
+		value, err = models.ParseDecimal(raw)
+	default:
+		return value, fmt.Errorf("%w: event %s has no %s", ErrInvalidLotMetadata, event.ID, key)
+	}
+	if err != nil {
+		return value, fmt.Errorf("%w: event %s %s: %v", ErrInvalidLotMetadata, event.ID, key, err)
+	}
+	if value.Sign() < 0 {
+		return value, fmt.Errorf("%w: event %s %s must not be negative", ErrInvalidLotMetadata, event.ID, key)
+	}
+	return value, nil
+}
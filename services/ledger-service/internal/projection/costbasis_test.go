@@ -0,0 +1,65 @@
+package projection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func lot(eventType models.EventType, quantity, unitCost float64, version int64) *models.LedgerEvent {
+	return event(eventType, quantity*unitCost, version).
+		WithMetadata(MetaQuantity, quantity).
+		WithMetadata(MetaUnitCost, unitCost)
+}
+
+func TestCostBasisBuyBuySell(t *testing.T) {
+	p, err := NewCostBasisProjection("acc_1", "USD")
+	require.NoError(t, err)
+
+	require.NoError(t, p.Apply(lot(models.Credit, 10, 100, 1)))
+	assert.Equal(t, "100", p.CostBasis().AverageCost.String())
+
+	require.NoError(t, p.Apply(lot(models.Credit, 30, 120, 2)))
+	assert.Equal(t, "40", p.CostBasis().Quantity.String())
+	assert.Equal(t, "115", p.CostBasis().AverageCost.String())
+
+	require.NoError(t, p.Apply(lot(models.Debit, 25, 130, 3)))
+	basis := p.CostBasis()
+	assert.Equal(t, "15", basis.Quantity.String())
+	assert.Equal(t, "115", basis.AverageCost.String())
+	assert.Equal(t, "375", basis.Realized.String())
+	assert.Equal(t, int64(3), basis.Version)
+}
+
+func TestCostBasisFullLiquidation(t *testing.T) {
+	p, err := NewCostBasisProjection("acc_1", "USD")
+	require.NoError(t, err)
+
+	require.NoError(t, p.Apply(lot(models.Credit, 10, 100, 1)))
+	require.NoError(t, p.Apply(lot(models.Debit, 10, 85.5, 2)))
+	basis := p.CostBasis()
+	assert.Zero(t, basis.Quantity.Sign())
+	assert.Zero(t, basis.AverageCost.Sign())
+	assert.Equal(t, "-145", basis.Realized.String())
+
+	require.NoError(t, p.Apply(lot(models.Credit, 4, 90, 3)))
+	assert.Equal(t, "90", p.CostBasis().AverageCost.String())
+}
+
+func TestCostBasisRejections(t *testing.T) {
+	p, err := NewCostBasisProjection("acc_1", "USD")
+	require.NoError(t, err)
+	require.NoError(t, p.Apply(lot(models.Credit, 10, 100, 1)))
+
+	assert.ErrorIs(t, p.Apply(lot(models.Debit, 11, 100, 2)), ErrInsufficientQuantity)
+	assert.ErrorIs(t, p.Apply(event(models.Credit, 50, 2)), ErrInvalidLotMetadata)
+	assert.ErrorIs(t, p.Apply(event(models.Credit, 50, 2).WithMetadata(MetaQuantity, "abc").WithMetadata(MetaUnitCost, "5")), ErrInvalidLotMetadata)
+	assert.Equal(t, "10", p.CostBasis().Quantity.String())
+
+	// Holds do not move the basis but advance the version
+	require.NoError(t, p.Apply(event(models.Hold, 50, 2)))
+	assert.Equal(t, int64(2), p.CostBasis().Version)
+}
@@ -0,0 +1,109 @@
+package projection
+
+import (
+	"sort"
+	"time"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// ProjectionFunc computes an account's balance from its events, given in stream order
+type ProjectionFunc func(accountID string, events []*models.LedgerEvent) (Balance, error)
+
+// AccountDiff is an account whose balance, or failure, differs between two projections
+type AccountDiff struct {
+	AccountID string
+	A, B      Balance
+	ErrA      error
+	ErrB      error
+}
+
+// ReplayBalance is the current balance logic as a ProjectionFunc: it applies the events to a
+// BalanceProjection in the currency of the first monetary event and stops at the first failure.
+// The replay is deterministic: overdrafts are allowed, as the stream already recorded them,
+// and each event is evaluated at its own timestamp, as is the final balance at the latest one,
+// so hold expiry and validity windows do not depend on when the replay runs.
+func ReplayBalance(accountID string, events []*models.LedgerEvent) (Balance, error) {
+	currency := ""
+	var version int64
+	for _, event := range events {
+		version = maxVersion(version, event.Version)
+		if currency == "" && !event.IsControl() {
+			currency = event.Amount.Currency
+		}
+	}
+	if currency == "" {
+		return Balance{AccountID: accountID, Version: version}, nil
+	}
+
+	clock := &eventClock{}
+	p, err := NewBalanceProjection(accountID, currency, WithOverdraftPolicy(AllowOverdraft), WithClock(clock))
+	if err != nil {
+		return Balance{}, err
+	}
+	for i, event := range events {
+		clock.advance(event.Timestamp)
+		if err := p.Apply(event); err != nil {
+			return p.Balance(), &EventError{Index: i, EventID: event.ID, Err: err}
+		}
+	}
+	return p.Balance(), nil
+}
+
+// eventClock is the clock of a replay: it reads the latest timestamp of the events replayed so far
+type eventClock struct {
+	now time.Time
+}
+
+// Now returns the latest timestamp replayed
+func (c *eventClock) Now() time.Time {
+	return c.now
+}
+
+// advance moves the clock to timestamp unless it is already past it
+func (c *eventClock) advance(timestamp time.Time) {
+	if timestamp.After(c.now) {
+		c.now = timestamp
+	}
+}
+
+// DiffProjections replays each account's events through both projections and reports, ordered
+// by account, those that end on different posted or held balances or versions, or where only
+// one projection fails or they fail differently. A failing projection is compared on the
+// balance it reached before failing. It is meant for checking a change to balance
+// logic against recorded streams before deploying it.
+func DiffProjections(events []*models.LedgerEvent, a, b ProjectionFunc) []AccountDiff {
+	streams := make(map[string][]*models.LedgerEvent)
+	for _, event := range events {
+		streams[event.AccountID] = append(streams[event.AccountID], event)
+	}
+	accounts := make([]string, 0, len(streams))
+	for account := range streams {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	var diffs []AccountDiff
+	for _, account := range accounts {
+		diff := AccountDiff{AccountID: account}
+		diff.A, diff.ErrA = a(account, streams[account])
+		diff.B, diff.ErrB = b(account, streams[account])
+		if !sameOutcome(diff) {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+// sameOutcome reports whether both projections reached the same balance and failed alike, if at all
+func sameOutcome(diff AccountDiff) bool {
+	if (diff.ErrA == nil) != (diff.ErrB == nil) {
+		return false
+	}
+	if diff.ErrA != nil && diff.ErrA.Error() != diff.ErrB.Error() {
+		return false
+	}
+	return diff.A.Posted.Equal(diff.B.Posted) &&
+		diff.A.Held.Equal(diff.B.Held) &&
+		diff.A.Version == diff.B.Version
+}
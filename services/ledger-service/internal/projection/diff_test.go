@@ -0,0 +1,85 @@
+package projection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// holdsAsDebits is a deliberately broken projection that posts holds instead of holding them
+func holdsAsDebits(accountID string, events []*models.LedgerEvent) (Balance, error) {
+	rewritten := make([]*models.LedgerEvent, len(events))
+	for i, event := range events {
+		copied := *event
+		switch copied.Type {
+		case models.Hold:
+			copied.Type = models.Debit
+		case models.Release:
+			copied.Type = models.Credit
+		}
+		rewritten[i] = &copied
+	}
+	return ReplayBalance(accountID, rewritten)
+}
+
+func TestDiffProjectionsFlagsBuggyProjection(t *testing.T) {
+	other := func(eventType models.EventType, amount float64, version int64) *models.LedgerEvent {
+		return models.NewLedgerEvent(eventType, usd(amount), "acc_2", "corr_2").WithVersion(version)
+	}
+	events := []*models.LedgerEvent{
+		event(models.Credit, 100, 1),
+		other(models.Credit, 50, 1),
+		event(models.Hold, 20, 2),
+		other(models.Debit, 20, 2),
+		event(models.Release, 5, 3),
+	}
+
+	assert.Empty(t, DiffProjections(events, ReplayBalance, ReplayBalance))
+
+	diffs := DiffProjections(events, ReplayBalance, holdsAsDebits)
+	require.Len(t, diffs, 1)
+	diff := diffs[0]
+	assert.Equal(t, "acc_1", diff.AccountID)
+	assert.NoError(t, diff.ErrA)
+	assert.NoError(t, diff.ErrB)
+	assert.Equal(t, usd(100), diff.A.Posted)
+	assert.Equal(t, usd(15), diff.A.Held)
+	assert.Equal(t, usd(85), diff.B.Posted)
+	assert.Equal(t, usd(0), diff.B.Held)
+
+	// The overdraft is replayed rather than rejected, from different balances
+	overdrawn := append(events, event(models.Debit, 500, 4))
+	assert.Len(t, DiffProjections(overdrawn, ReplayBalance, holdsAsDebits), 1)
+	assert.Empty(t, DiffProjections(overdrawn, ReplayBalance, ReplayBalance))
+
+	// Only the correct projection fails on a release of more than is held
+	released := append(events, event(models.Release, 50, 4))
+	diffs = DiffProjections(released, ReplayBalance, holdsAsDebits)
+	require.Len(t, diffs, 1)
+	assert.ErrorIs(t, diffs[0].ErrA, ErrReleaseExceedsHold)
+	assert.NoError(t, diffs[0].ErrB)
+}
+
+func TestReplayBalanceEvaluatesEventsAtTheirTimestamps(t *testing.T) {
+	recorded := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(e *models.LedgerEvent, offset time.Duration) *models.LedgerEvent {
+		e.Timestamp = recorded.Add(offset)
+		return e
+	}
+	hold := at(event(models.Hold, 80, 2), time.Hour).WithExpiry(24 * time.Hour)
+	events := []*models.LedgerEvent{
+		at(event(models.Credit, 50, 1), 0),
+		hold,
+		at(event(models.Release, 30, 3).WithHoldID(hold.ID), 2*time.Hour),
+	}
+
+	balance, err := ReplayBalance("acc_1", events)
+	require.NoError(t, err, "the overdrawing hold is replayed and still live at the release")
+	assert.Equal(t, usd(50), balance.Posted)
+	assert.Equal(t, usd(50), balance.Held, "held as of the last event, long expired by now")
+	assert.Equal(t, int64(3), balance.Version)
+}
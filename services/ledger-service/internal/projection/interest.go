@@ -0,0 +1,56 @@
+package projection
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// ErrNoEvents is returned when a calculation needs at least one monetary event to identify the account
+var ErrNoEvents = errors.New("no events")
+
+// AccrueInterest accrues daily-balance interest on the account the events belong to over the
+// calendar days in [from, to). rate is an annual rate; each day accrues its end-of-day posted
+// balance × rate / the number of days in that year (actual/actual), so leap years and months
+// of any length are handled by walking real calendar days. Days with a zero or negative balance
+// accrue nothing. The accrual is summed exactly and rounded once, to the currency's precision.
+func AccrueInterest(events []*models.LedgerEvent, rate models.Decimal, from, to time.Time, round models.RoundingMode) (models.Money, error) {
+	var accountID, currency string
+	for _, event := range events {
+		if !event.IsControl() {
+			accountID, currency = event.AccountID, event.Amount.Currency
+			break
+		}
+	}
+	if accountID == "" {
+		return models.Money{}, ErrNoEvents
+	}
+	zero, err := models.ZeroMoney(currency)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	var accrued models.Decimal
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		balance, err := BalanceAsOf(accountID, currency, events, endOfDay)
+		if err != nil {
+			return models.Money{}, fmt.Errorf("balance on %s: %w", day.Format(time.DateOnly), err)
+		}
+
+		posted := models.DecimalFromMoney(balance.Posted)
+		if posted.Sign() <= 0 {
+			continue
+		}
+		accrued = accrued.Add(posted.Mul(rate).QuoInt(daysInYear(day.Year())))
+	}
+
+	return models.MoneyFromDecimal(accrued, currency, zero.Precision, round)
+}
+
+// daysInYear returns 366 for leap years and 365 otherwise
+func daysInYear(year int) int64 {
+	return int64(time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay())
+}
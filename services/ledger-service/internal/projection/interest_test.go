@@ -0,0 +1,63 @@
+package projection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func eventAt(eventType models.EventType, amount float64, version int64, at time.Time) *models.LedgerEvent {
+	e := event(eventType, amount, version)
+	e.Timestamp = at
+	return e
+}
+
+func TestAccrueInterestOverWeekWithBalanceChange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+	events := []*models.LedgerEvent{
+		eventAt(models.Credit, 1000, 1, day(9).Add(9*time.Hour)),
+		eventAt(models.Credit, 500, 2, day(11).Add(15*time.Hour)),
+	}
+	// 3.66% a year is 0.01% a day in 2024, a leap year
+	rate, err := models.ParseDecimal("0.0366")
+	require.NoError(t, err)
+
+	// Jan 8 accrues nothing, Jan 9-10 accrue 0.10 a day on 1000, Jan 11-14 accrue 0.15 a day on 1500
+	interest, err := AccrueInterest(events, rate, day(8), day(15), models.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, usd(0.80), interest)
+
+	// 0.05 × (2×1000 + 4×1500) / 366 = 1.0928..., which the rounding mode decides
+	rate, err = models.ParseDecimal("0.05")
+	require.NoError(t, err)
+	interest, err = AccrueInterest(events, rate, day(8), day(15), models.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, usd(1.09), interest)
+	interest, err = AccrueInterest(events, rate, day(8), day(15), models.RoundUp)
+	require.NoError(t, err)
+	assert.Equal(t, usd(1.10), interest)
+}
+
+func TestAccrueInterestAcrossMonthsOfDifferentLength(t *testing.T) {
+	events := []*models.LedgerEvent{eventAt(models.Credit, 36500, 1, time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC))}
+	rate, err := models.ParseDecimal("0.01")
+	require.NoError(t, err)
+
+	// 1.00 a day in 2023: February has 28 days and March 31
+	february, err := AccrueInterest(events, rate,
+		time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), models.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, usd(28), february)
+
+	march, err := AccrueInterest(events, rate,
+		time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC), models.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, usd(31), march)
+
+	_, err = AccrueInterest(nil, rate, time.Now(), time.Now(), models.RoundHalfEven)
+	assert.ErrorIs(t, err, ErrNoEvents)
+}
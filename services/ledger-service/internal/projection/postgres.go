@@ -0,0 +1,107 @@
+package projection
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresCheckpointStore keeps a named projection's states and cursors in the
+// ledger_projection_states and ledger_projection_cursors tables, committing both in one
+// transaction. States are stored as JSON.
+type PostgresCheckpointStore[S any] struct {
+	pool *pgxpool.Pool
+	name string
+}
+
+// NewPostgresCheckpointStore creates a checkpoint store for the projection called name
+func NewPostgresCheckpointStore[S any](pool *pgxpool.Pool, name string) *PostgresCheckpointStore[S] {
+	return &PostgresCheckpointStore[S]{pool: pool, name: name}
+}
+
+// Load returns the account's saved state
+func (p *PostgresCheckpointStore[S]) Load(ctx context.Context, accountID string) (Snapshot[S], bool, error) {
+	var (
+		snapshot Snapshot[S]
+		payload  []byte
+	)
+	err := p.pool.QueryRow(ctx,
+		`SELECT version, state FROM ledger_projection_states WHERE projection = $1 AND account_id = $2`,
+		p.name, accountID).Scan(&snapshot.Version, &payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return snapshot, false, nil
+	}
+	if err != nil {
+		return snapshot, false, err
+	}
+	if err := json.Unmarshal(payload, &snapshot.State); err != nil {
+		return snapshot, false, fmt.Errorf("failed to unmarshal %s state: %w", p.name, err)
+	}
+	return snapshot, true, nil
+}
+
+// Save replaces the account's state without moving its cursor
+func (p *PostgresCheckpointStore[S]) Save(ctx context.Context, accountID string, snapshot Snapshot[S]) error {
+	return p.saveState(ctx, p.pool, accountID, snapshot)
+}
+
+// LoadCursor returns the account's saved cursor
+func (p *PostgresCheckpointStore[S]) LoadCursor(ctx context.Context, accountID string) (int64, error) {
+	var version int64
+	err := p.pool.QueryRow(ctx,
+		`SELECT version FROM ledger_projection_cursors WHERE projection = $1 AND account_id = $2`,
+		p.name, accountID).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// SaveCursor replaces the account's cursor without touching its state
+func (p *PostgresCheckpointStore[S]) SaveCursor(ctx context.Context, accountID string, version int64) error {
+	return p.saveCursor(ctx, p.pool, accountID, version)
+}
+
+// Commit replaces the account's state and moves its cursor to the state's version in one transaction
+func (p *PostgresCheckpointStore[S]) Commit(ctx context.Context, accountID string, snapshot Snapshot[S]) error {
+	return pgx.BeginFunc(ctx, p.pool, func(tx pgx.Tx) error {
+		if err := p.saveState(ctx, tx, accountID, snapshot); err != nil {
+			return err
+		}
+		return p.saveCursor(ctx, tx, accountID, snapshot.Version)
+	})
+}
+
+// execer is satisfied by both the pool and a transaction
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// saveState upserts the account's state through db
+func (p *PostgresCheckpointStore[S]) saveState(ctx context.Context, db execer, accountID string, snapshot Snapshot[S]) error {
+	payload, err := json.Marshal(snapshot.State)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s state: %w", p.name, err)
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO ledger_projection_states (projection, account_id, version, state)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (projection, account_id) DO UPDATE SET version = EXCLUDED.version, state = EXCLUDED.state, updated_at = NOW()`,
+		p.name, accountID, snapshot.Version, payload)
+	return err
+}
+
+// saveCursor upserts the account's cursor through db
+func (p *PostgresCheckpointStore[S]) saveCursor(ctx context.Context, db execer, accountID string, version int64) error {
+	_, err := db.Exec(ctx, `
+		INSERT INTO ledger_projection_cursors (projection, account_id, version)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (projection, account_id) DO UPDATE SET version = EXCLUDED.version, updated_at = NOW()`,
+		p.name, accountID, version)
+	return err
+}
@@ -0,0 +1,190 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Projection folds an account's events into a typed state
+type Projection[S any] interface {
+	// Initial returns the state before any event is applied
+	Initial() S
+	// Apply returns the state after applying event
+	Apply(state S, event *models.LedgerEvent) (S, error)
+}
+
+// Copier is implemented by projections whose state shares memory between values, such as
+// pointers or maps that Apply mutates. Rebuild copies states through it before saving or
+// resuming from a snapshot, so snapshots are never changed by later events.
+type Copier[S any] interface {
+	Copy(state S) S
+}
+
+// EventReader reads an account stream; every store.EventStore satisfies it
+type EventReader interface {
+	Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error)
+}
+
+// Snapshot is a projection state as of a stream version
+type Snapshot[S any] struct {
+	Version int64
+	State   S
+}
+
+// SnapshotStore persists projection snapshots per account
+type SnapshotStore[S any] interface {
+	// Load returns the account's latest snapshot, or false if there is none
+	Load(ctx context.Context, accountID string) (Snapshot[S], bool, error)
+	// Save records a snapshot for the account
+	Save(ctx context.Context, accountID string, snapshot Snapshot[S]) error
+}
+
+// MemorySnapshotStore keeps the latest snapshot of each account in memory
+type MemorySnapshotStore[S any] struct {
+	mu        sync.RWMutex
+	snapshots map[string]Snapshot[S]
+}
+
+// NewMemorySnapshotStore creates an empty snapshot store
+func NewMemorySnapshotStore[S any]() *MemorySnapshotStore[S] {
+	return &MemorySnapshotStore[S]{snapshots: make(map[string]Snapshot[S])}
+}
+
+// Load returns the account's latest snapshot
+func (m *MemorySnapshotStore[S]) Load(ctx context.Context, accountID string) (Snapshot[S], bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot, ok := m.snapshots[accountID]
+	return snapshot, ok, nil
+}
+
+// Save replaces the account's snapshot
+func (m *MemorySnapshotStore[S]) Save(ctx context.Context, accountID string, snapshot Snapshot[S]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshots[accountID] = snapshot
+	return nil
+}
+
+// RebuildOption configures Rebuild
+type RebuildOption[S any] func(*rebuildConfig[S])
+
+type rebuildConfig[S any] struct {
+	snapshots SnapshotStore[S]
+	every     int
+}
+
+// WithSnapshots resumes from the account's latest snapshot and saves a new one after every
+// `every` applied events; with every <= 0 snapshots are only read
+func WithSnapshots[S any](snapshots SnapshotStore[S], every int) RebuildOption[S] {
+	return func(c *rebuildConfig[S]) {
+		c.snapshots = snapshots
+		c.every = every
+	}
+}
+
+// Rebuild folds the account's stream into the projection's state, starting from the latest
+// snapshot when snapshots are configured
+func Rebuild[S any](ctx context.Context, reader EventReader, accountID string, projection Projection[S], opts ...RebuildOption[S]) (S, error) {
+	var config rebuildConfig[S]
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	state := projection.Initial()
+	var from int64 = 1
+	if config.snapshots != nil {
+		snapshot, ok, err := config.snapshots.Load(ctx, accountID)
+		if err != nil {
+			return state, fmt.Errorf("failed to load snapshot of account %s: %w", accountID, err)
+		}
+		if ok {
+			state = copyState(projection, snapshot.State)
+			from = snapshot.Version + 1
+		}
+	}
+
+	events, err := reader.Read(ctx, accountID, from)
+	if err != nil {
+		return state, err
+	}
+
+	sinceSnapshot := 0
+	for _, event := range events {
+		if state, err = projection.Apply(state, event); err != nil {
+			return state, fmt.Errorf("event %s: %w", event.ID, err)
+		}
+		sinceSnapshot++
+
+		if config.snapshots != nil && config.every > 0 && sinceSnapshot >= config.every {
+			snapshot := Snapshot[S]{Version: event.Version, State: copyState(projection, state)}
+			if err := config.snapshots.Save(ctx, accountID, snapshot); err != nil {
+				return state, fmt.Errorf("failed to save snapshot of account %s: %w", accountID, err)
+			}
+			sinceSnapshot = 0
+		}
+	}
+	return state, nil
+}
+
+// copyState copies state through the projection's Copier, if it has one
+func copyState[S any](projection Projection[S], state S) S {
+	if copier, ok := projection.(Copier[S]); ok {
+		return copier.Copy(state)
+	}
+	return state
+}
+
+// BalanceFold is the balance reducer: a Projection folding an account's events into a
+// models.BalanceState under its overdraft policy, at its clock's time when each event is
+// applied. BalanceProjection applies events through it, and Rebuild can drive it directly.
+type BalanceFold struct {
+	accountID string
+	zero      models.Money
+	clock     models.Clock
+	overdraft OverdraftPolicy
+}
+
+// NewBalanceFold creates the balance reducer for the account, configured like
+// NewBalanceProjection; options that only concern batches and replay order do not affect it
+func NewBalanceFold(accountID, currency string, opts ...Option) (*BalanceFold, error) {
+	p, err := NewBalanceProjection(accountID, currency, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p.fold, nil
+}
+
+// Initial returns the state of the account with nothing posted or held
+func (f *BalanceFold) Initial() models.BalanceState {
+	return models.NewBalanceState(f.accountID, f.zero)
+}
+
+// Apply returns the state after folding event into it, or the state unchanged and the
+// reason the event could not be applied
+func (f *BalanceFold) Apply(state models.BalanceState, event *models.LedgerEvent) (models.BalanceState, error) {
+	rules := models.BalanceRules{Now: f.clock.Now(), AllowOverdraft: f.overdraft == AllowOverdraft}
+	err := state.Apply(event, rules)
+	return state, err
+}
+
+// Copy returns a copy of the state that shares no holds with it
+func (f *BalanceFold) Copy(state models.BalanceState) models.BalanceState {
+	return state.Clone()
+}
+
+// Balance returns the balance of state, counting only holds that have not expired by the
+// fold's clock
+func (f *BalanceFold) Balance(state models.BalanceState) Balance {
+	return Balance{
+		AccountID: state.AccountID,
+		Posted:    state.Posted,
+		Held:      state.Held(f.clock.Now()),
+		Version:   state.Version,
+	}
+}
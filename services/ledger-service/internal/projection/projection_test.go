@@ -0,0 +1,110 @@
+package projection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+	"fintech-platform/ledger-service/internal/store"
+)
+
+type countByType struct{}
+
+func (countByType) Initial() map[models.EventType]int {
+	return map[models.EventType]int{}
+}
+
+func (countByType) Apply(state map[models.EventType]int, event *models.LedgerEvent) (map[models.EventType]int, error) {
+	next := make(map[models.EventType]int, len(state)+1)
+	for eventType, count := range state {
+		next[eventType] = count
+	}
+	next[event.Type]++
+	return next, nil
+}
+
+type recordingReader struct {
+	EventReader
+	from []int64
+}
+
+func (r *recordingReader) Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error) {
+	r.from = append(r.from, fromVersion)
+	return r.EventReader.Read(ctx, accountID, fromVersion)
+}
+
+func seededStore(t *testing.T) *store.MemoryStore {
+	t.Helper()
+	s := store.NewMemoryStore()
+	for _, e := range []*models.LedgerEvent{
+		event(models.Credit, 100, 1),
+		event(models.Debit, 30, 2),
+		event(models.Hold, 20, 3),
+		event(models.Release, 10, 4),
+	} {
+		require.NoError(t, s.Append(context.Background(), e))
+	}
+	return s
+}
+
+func TestRebuildCountByType(t *testing.T) {
+	ctx := context.Background()
+	reader := &recordingReader{EventReader: seededStore(t)}
+	snapshots := NewMemorySnapshotStore[map[models.EventType]int]()
+
+	counts, err := Rebuild[map[models.EventType]int](ctx, reader, "acc_1", countByType{}, WithSnapshots[map[models.EventType]int](snapshots, 3))
+	require.NoError(t, err)
+	assert.Equal(t, map[models.EventType]int{models.Credit: 1, models.Debit: 1, models.Hold: 1, models.Release: 1}, counts)
+
+	snapshot, ok, err := snapshots.Load(ctx, "acc_1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int64(3), snapshot.Version)
+	assert.Equal(t, map[models.EventType]int{models.Credit: 1, models.Debit: 1, models.Hold: 1}, snapshot.State)
+
+	again, err := Rebuild[map[models.EventType]int](ctx, reader, "acc_1", countByType{}, WithSnapshots[map[models.EventType]int](snapshots, 0))
+	require.NoError(t, err)
+	assert.Equal(t, counts, again)
+	assert.Equal(t, []int64{1, 4}, reader.from)
+}
+
+func TestRebuildBalanceFold(t *testing.T) {
+	ctx := context.Background()
+	fold, err := NewBalanceFold("acc_1", "USD")
+	require.NoError(t, err)
+	snapshots := NewMemorySnapshotStore[models.BalanceState]()
+
+	state, err := Rebuild[models.BalanceState](ctx, seededStore(t), "acc_1", fold, WithSnapshots[models.BalanceState](snapshots, 3))
+	require.NoError(t, err)
+	assert.Equal(t, usd(70), fold.Balance(state).Posted)
+	assert.Equal(t, usd(10), fold.Balance(state).Held)
+
+	// The snapshot taken after the hold is unaffected by the release applied after it
+	snapshot, _, err := snapshots.Load(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), snapshot.Version)
+	assert.Equal(t, usd(20), fold.Balance(snapshot.State).Held)
+
+	_, err = NewBalanceFold("acc_1", "XYZ")
+	assert.ErrorIs(t, err, models.ErrUnknownCurrency)
+}
+
+func TestBalanceProjectionMatchesBalanceFold(t *testing.T) {
+	fold, err := NewBalanceFold("acc_1", "USD")
+	require.NoError(t, err)
+	p, err := NewBalanceProjection("acc_1", "USD")
+	require.NoError(t, err)
+
+	state := fold.Initial()
+	for _, event := range mixedBatch() {
+		next, foldErr := fold.Apply(state, event)
+		if foldErr == nil {
+			state = next
+		}
+		assert.Equal(t, foldErr, p.Apply(event), "event %d", event.Version)
+	}
+	assert.Equal(t, fold.Balance(state), p.Balance())
+}
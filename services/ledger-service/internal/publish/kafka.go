@@ -0,0 +1,162 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Kafka header names set on every message
+const (
+	HeaderEventID       = "ledger-event-id"
+	HeaderEventType     = "ledger-event-type"
+	HeaderCorrelationID = "correlation-id"
+	HeaderTraceID       = "trace-id"
+	HeaderContentType   = "content-type"
+)
+
+// traceMetadataKey is the event metadata key carrying an upstream trace ID
+const traceMetadataKey = "traceId"
+
+// Defaults for KafkaPublisher retries
+const (
+	DefaultMaxAttempts = 3
+	DefaultRetryDelay  = 100 * time.Millisecond
+)
+
+// KafkaHeader is a single Kafka record header
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaMessage is a record to produce
+type KafkaMessage struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []KafkaHeader
+}
+
+// KafkaProducer is the subset of a Kafka client the publisher needs. Produce must return only
+// once the record is acknowledged; adapters wrap a concrete client such as kafka-go or sarama.
+type KafkaProducer interface {
+	Produce(ctx context.Context, msg KafkaMessage) error
+}
+
+// KafkaPublisher publishes events to a topic keyed by account ID. With the default partitioner
+// every event of an account lands on the same partition, so consumers see each account's
+// events in append order.
+type KafkaPublisher struct {
+	producer    KafkaProducer
+	topic       string
+	codec       Codec
+	maxAttempts int
+	retryDelay  time.Duration
+	logger      logrus.FieldLogger
+}
+
+// KafkaOption configures a KafkaPublisher
+type KafkaOption func(*KafkaPublisher)
+
+// WithCodec sets the codec events are serialized with; the default is JSONCodec
+func WithCodec(codec Codec) KafkaOption {
+	return func(p *KafkaPublisher) {
+		p.codec = codec
+	}
+}
+
+// WithRetry sets how many times a produce is attempted and the delay between attempts,
+// which doubles after each failure
+func WithRetry(maxAttempts int, delay time.Duration) KafkaOption {
+	return func(p *KafkaPublisher) {
+		if maxAttempts > 0 {
+			p.maxAttempts = maxAttempts
+		}
+		p.retryDelay = delay
+	}
+}
+
+// WithLogger sets the logger used for retried produce failures
+func WithLogger(logger logrus.FieldLogger) KafkaOption {
+	return func(p *KafkaPublisher) {
+		p.logger = logger
+	}
+}
+
+// NewKafkaPublisher creates a publisher producing to topic
+func NewKafkaPublisher(producer KafkaProducer, topic string, opts ...KafkaOption) *KafkaPublisher {
+	p := &KafkaPublisher{
+		producer:    producer,
+		topic:       topic,
+		codec:       JSONCodec{},
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+		logger:      logrus.StandardLogger(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Publish encodes the event and produces it, retrying failed attempts. The last produce error
+// is returned once attempts run out or the context is done.
+func (p *KafkaPublisher) Publish(ctx context.Context, event *models.LedgerEvent) error {
+	msg, err := p.message(event)
+	if err != nil {
+		return err
+	}
+
+	delay := p.retryDelay
+	for attempt := 1; ; attempt++ {
+		err = p.producer.Produce(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.maxAttempts {
+			return fmt.Errorf("failed to publish event %s after %d attempts: %w", event.ID, attempt, err)
+		}
+
+		p.logger.WithFields(logrus.Fields{
+			"eventId": event.ID,
+			"attempt": attempt,
+		}).WithError(err).Warn("kafka produce failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to publish event %s: %w (last error: %v)", event.ID, ctx.Err(), err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// message builds the Kafka record for an event
+func (p *KafkaPublisher) message(event *models.LedgerEvent) (KafkaMessage, error) {
+	value, err := p.codec.Encode(event)
+	if err != nil {
+		return KafkaMessage{}, fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+
+	headers := []KafkaHeader{
+		{Key: HeaderEventID, Value: []byte(event.ID)},
+		{Key: HeaderEventType, Value: []byte(event.Type)},
+		{Key: HeaderCorrelationID, Value: []byte(event.CorrelationID)},
+		{Key: HeaderContentType, Value: []byte(p.codec.ContentType())},
+	}
+	if traceID, ok := event.Metadata[traceMetadataKey].(string); ok && traceID != "" {
+		headers = append(headers, KafkaHeader{Key: HeaderTraceID, Value: []byte(traceID)})
+	}
+
+	return KafkaMessage{
+		Topic:   p.topic,
+		Key:     []byte(event.AccountID),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+type mockProducer struct {
+	failures int
+	messages []KafkaMessage
+	attempts int
+}
+
+func (m *mockProducer) Produce(ctx context.Context, msg KafkaMessage) error {
+	m.attempts++
+	if m.attempts <= m.failures {
+		return errors.New("broker unavailable")
+	}
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func headers(msg KafkaMessage) map[string]string {
+	values := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		values[h.Key] = string(h.Value)
+	}
+	return values
+}
+
+func newEvent() *models.LedgerEvent {
+	return models.NewLedgerEvent(models.Credit, models.NewMoney(1000, "USD", 2), "acc_1", "corr_1").
+		WithMetadata("traceId", "4bf92f3577b34da6a3ce929d0e0e4736")
+}
+
+func TestKafkaPublisherSetsKeyAndHeaders(t *testing.T) {
+	producer := &mockProducer{}
+	publisher := NewKafkaPublisher(producer, "ledger-events")
+	event := newEvent()
+
+	require.NoError(t, publisher.Publish(context.Background(), event))
+
+	require.Len(t, producer.messages, 1)
+	msg := producer.messages[0]
+	assert.Equal(t, "ledger-events", msg.Topic)
+	assert.Equal(t, "acc_1", string(msg.Key))
+	assert.Equal(t, map[string]string{
+		HeaderEventID:       event.ID,
+		HeaderEventType:     "CREDIT",
+		HeaderCorrelationID: "corr_1",
+		HeaderTraceID:       "4bf92f3577b34da6a3ce929d0e0e4736",
+		HeaderContentType:   "application/json",
+	}, headers(msg))
+
+	decoded, err := models.LedgerEventFromJSON(msg.Value)
+	require.NoError(t, err)
+	assert.Equal(t, event.ID, decoded.ID)
+}
+
+func TestKafkaPublisherRetriesProduceErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	producer := &mockProducer{failures: 2}
+	publisher := NewKafkaPublisher(producer, "ledger-events", WithRetry(3, 0), WithLogger(logger))
+
+	require.NoError(t, publisher.Publish(context.Background(), newEvent()))
+	assert.Equal(t, 3, producer.attempts)
+	assert.Len(t, producer.messages, 1)
+	assert.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+
+	failing := &mockProducer{failures: 5}
+	publisher = NewKafkaPublisher(failing, "ledger-events", WithRetry(2, 0), WithLogger(logger))
+	err := publisher.Publish(context.Background(), newEvent())
+	assert.ErrorContains(t, err, "broker unavailable")
+	assert.Equal(t, 2, failing.attempts)
+}
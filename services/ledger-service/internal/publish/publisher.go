@@ -0,0 +1,35 @@
+// Package publish mirrors appended ledger events onto message brokers
+package publish
+
+import (
+	"context"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Publisher delivers ledger events to downstream consumers
+type Publisher interface {
+	// Publish delivers the event, returning once the broker has accepted it
+	Publish(ctx context.Context, event *models.LedgerEvent) error
+}
+
+// Codec serializes events for the wire
+type Codec interface {
+	// Encode returns the wire form of the event
+	Encode(event *models.LedgerEvent) ([]byte, error)
+	// ContentType names the wire format, e.g. application/json
+	ContentType() string
+}
+
+// JSONCodec encodes events as their JSON representation
+type JSONCodec struct{}
+
+// Encode returns the event's JSON bytes
+func (JSONCodec) Encode(event *models.LedgerEvent) ([]byte, error) {
+	return event.ToJSON()
+}
+
+// ContentType returns application/json
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
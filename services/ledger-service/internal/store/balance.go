@@ -0,0 +1,46 @@
+package store
+
+import (
+	"fmt"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// postedBalance sums the balance-affecting events and reversals of a stream in the given
+// currency; events in other currencies are skipped
+func postedBalance(events []*models.LedgerEvent, currency string) (models.Money, error) {
+	balance, err := models.ZeroMoney(currency)
+	if err != nil {
+		return models.Money{}, err
+	}
+	originals := models.ReversibleTypes(events)
+	for _, event := range events {
+		if balance, err = postEvent(balance, event, originals); err != nil {
+			return models.Money{}, err
+		}
+	}
+	return balance, nil
+}
+
+// postEvent returns balance after applying a balance-affecting event or reversal in its
+// currency, at the finer of their precisions; originals resolves what reversals undo, see
+// models.PostedAmount. Other events and events in other currencies leave it unchanged.
+func postEvent(balance models.Money, event *models.LedgerEvent, originals map[string]models.EventType) (models.Money, error) {
+	if !event.PostsBalance() || event.Amount.Currency != balance.Currency {
+		return balance, nil
+	}
+	amount, err := models.PostedAmount(event, originals)
+	if err != nil {
+		return models.Money{}, err
+	}
+	return models.SumPromoting([]models.Money{balance, amount})
+}
+
+// checkExpectedBalance returns ErrBalanceChanged unless the account's current balance equals expected
+func checkExpectedBalance(accountID string, current, expected models.Money) error {
+	if current.Equal(expected) {
+		return nil
+	}
+	return fmt.Errorf("%w: account %s balance is %.*f %s, expected %.*f %s", ErrBalanceChanged, accountID,
+		current.Precision, current.Float(), current.Currency, expected.Precision, expected.Float(), expected.Currency)
+}
@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// BatchMode controls how AppendBatch treats events that fail to append
+type BatchMode int
+
+const (
+	// Atomic persists the batch only if every event can be appended
+	Atomic BatchMode = iota
+	// BestEffort persists every event that can be appended and reports the rest
+	BestEffort
+)
+
+// String returns the name of the batch mode
+func (m BatchMode) String() string {
+	switch m {
+	case Atomic:
+		return "atomic"
+	case BestEffort:
+		return "best-effort"
+	default:
+		return fmt.Sprintf("BatchMode(%d)", int(m))
+	}
+}
+
+// BatchAppender is implemented by stores that can append several events in one call
+type BatchAppender interface {
+	// AppendBatch appends the events in order. It returns a *BatchError if any event failed;
+	// in Atomic mode nothing was persisted, in BestEffort mode every other event was.
+	AppendBatch(ctx context.Context, events []*models.LedgerEvent, mode BatchMode) error
+}
+
+// ErrEmptyBatchKey is returned by AppendBatchIdempotent when no batch key is given
+var ErrEmptyBatchKey = errors.New("batch key is required")
+
+// IdempotentBatchAppender is implemented by stores that can make a whole batch idempotent under one key
+type IdempotentBatchAppender interface {
+	// AppendBatchIdempotent appends the events atomically and returns their IDs. A batch that
+	// succeeded under batchKey before is not appended again; the IDs it produced are returned
+	// verbatim instead. A batch that failed recorded nothing, being atomic, so retrying it
+	// under the same key runs it again from scratch.
+	AppendBatchIdempotent(ctx context.Context, events []*models.LedgerEvent, batchKey string) ([]string, error)
+}
+
+// eventIDs returns the IDs of events in order
+func eventIDs(events []*models.LedgerEvent) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+// BatchError reports the events of a batch that failed to append, keyed by their index in the batch
+type BatchError struct {
+	Mode     BatchMode
+	Failures map[int]error
+}
+
+// Error summarizes the failures, naming the first failing index
+func (e *BatchError) Error() string {
+	indexes := e.Indexes()
+	if len(indexes) == 0 {
+		return fmt.Sprintf("%s batch failed", e.Mode)
+	}
+	first := indexes[0]
+	return fmt.Sprintf("%s batch: %d event(s) failed, first at index %d: %v", e.Mode, len(indexes), first, e.Failures[first])
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, index := range e.Indexes() {
+		errs = append(errs, e.Failures[index])
+	}
+	return errs
+}
+
+// Indexes returns the failing batch indexes in ascending order
+func (e *BatchError) Indexes() []int {
+	indexes := make([]int, 0, len(e.Failures))
+	for index := range e.Failures {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
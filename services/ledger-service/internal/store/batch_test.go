@@ -0,0 +1,150 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func mixedBatch() []*models.LedgerEvent {
+	return []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1"),
+		models.NewLedgerEvent(models.Debit, usd(0), "acc_1", "corr_1").WithVersion(2),
+		models.NewLedgerEvent(models.Credit, usd(20), "acc_2", "corr_1"),
+	}
+}
+
+func TestMemoryStoreAppendBatchAtomicRollsBack(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	err := s.AppendBatch(ctx, mixedBatch(), Atomic)
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{1}, batchErr.Indexes())
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+
+	for _, accountID := range []string{"acc_1", "acc_2"} {
+		events, err := s.Read(ctx, accountID, 1)
+		require.NoError(t, err)
+		assert.Empty(t, events, accountID)
+	}
+}
+
+func TestMemoryStoreAppendBatchAtomicRetryAfterRollback(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(WithChaining(0, key), MonotonicTimestamps(nil))
+	head := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")
+	require.NoError(t, s.Append(ctx, head))
+
+	skewed := models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1").WithVersion(2)
+	skewed.Timestamp = head.Timestamp.Add(-time.Minute)
+	batch := []*models.LedgerEvent{
+		skewed,
+		models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_1").WithVersion(4),
+	}
+	before := []*models.LedgerEvent{batch[0].Clone(), batch[1].Clone()}
+
+	err := s.AppendBatch(ctx, batch, Atomic)
+	require.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, before, batch, "a rolled back batch leaves its events as they were")
+
+	batch[1].Version = 3
+	require.NoError(t, s.AppendBatch(ctx, batch, Atomic))
+	assert.Equal(t, head.ComputeHash(), batch[0].PreviousHash)
+	assert.True(t, batch[0].Timestamp.After(head.Timestamp))
+
+	verified, err := s.VerifyChain(ctx, "acc_1", key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, verified)
+}
+
+func TestMemoryStoreAppendBatchBestEffortPersistsValid(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	batch := mixedBatch()
+
+	err := s.AppendBatch(ctx, batch, BestEffort)
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, []int{1}, batchErr.Indexes())
+
+	acc1, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, acc1, 1)
+	assert.Equal(t, batch[0].ID, acc1[0].ID)
+
+	acc2, err := s.Read(ctx, "acc_2", 1)
+	require.NoError(t, err)
+	require.Len(t, acc2, 1)
+	assert.Equal(t, batch[2].ID, acc2[0].ID)
+}
+
+func TestMemoryStoreAppendBatchAtomicCommitsValidBatch(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	batch := []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1"),
+		models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_1").WithVersion(2),
+	}
+
+	require.NoError(t, s.AppendBatch(ctx, batch, Atomic))
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestMemoryStoreAppendBatchIdempotentRetry(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	batch := []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1"),
+		models.NewLedgerEvent(models.Credit, usd(20), "acc_2", "corr_1"),
+	}
+
+	ids, err := s.AppendBatchIdempotent(ctx, batch, "batch_1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{batch[0].ID, batch[1].ID}, ids)
+
+	// A retry regenerates event IDs but must not append them
+	retry := []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1"),
+		models.NewLedgerEvent(models.Credit, usd(20), "acc_2", "corr_1"),
+	}
+	retried, err := s.AppendBatchIdempotent(ctx, retry, "batch_1")
+	require.NoError(t, err)
+	assert.Equal(t, ids, retried)
+
+	for _, accountID := range []string{"acc_1", "acc_2"} {
+		events, err := s.Read(ctx, accountID, 1)
+		require.NoError(t, err)
+		assert.Len(t, events, 1, accountID)
+	}
+}
+
+func TestMemoryStoreAppendBatchIdempotentFailedBatchRuns(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	_, err := s.AppendBatchIdempotent(ctx, mixedBatch(), "batch_1")
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+
+	valid := []*models.LedgerEvent{models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_1")}
+	ids, err := s.AppendBatchIdempotent(ctx, valid, "batch_1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{valid[0].ID}, ids)
+
+	_, err = s.AppendBatchIdempotent(ctx, valid, "")
+	assert.ErrorIs(t, err, ErrEmptyBatchKey)
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// CacheStats counts balance cache lookups
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the fraction of lookups served from the cache, or 0 before any lookup
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// cachedBalance is the posted balance of an account as of a stream version
+type cachedBalance struct {
+	balance models.Money
+	version int64
+}
+
+// balanceKey identifies a cached balance
+type balanceKey struct {
+	accountID string
+	currency  string
+}
+
+// CachedBalanceStore wraps an EventStore and caches the posted balance of each account.
+// Appends made through the wrapper update cached balances incrementally; an append that does
+// not directly follow the cached version drops the entry instead, so the cache never serves a
+// balance a full recompute would disagree with. Appends that bypass the wrapper are not seen,
+// so it must be the only writer of the accounts it caches.
+type CachedBalanceStore struct {
+	EventStore
+
+	mu       sync.Mutex
+	balances map[balanceKey]cachedBalance
+	appended map[string]int64
+	hits     atomic.Uint64
+	misses   atomic.Uint64
+}
+
+// NewCachedBalanceStore wraps the store with a balance cache
+func NewCachedBalanceStore(inner EventStore) *CachedBalanceStore {
+	return &CachedBalanceStore{
+		EventStore: inner,
+		balances:   make(map[balanceKey]cachedBalance),
+		appended:   make(map[string]int64),
+	}
+}
+
+// Append appends through the wrapped store and updates the account's cached balances
+func (c *CachedBalanceStore) Append(ctx context.Context, event *models.LedgerEvent) error {
+	if err := c.EventStore.Append(ctx, event); err != nil {
+		return err
+	}
+	c.record(event)
+	return nil
+}
+
+// AppendIfBalance appends conditionally through the wrapped store and updates the account's
+// cached balances
+func (c *CachedBalanceStore) AppendIfBalance(ctx context.Context, event *models.LedgerEvent, expected models.Money) error {
+	if err := c.EventStore.AppendIfBalance(ctx, event, expected); err != nil {
+		return err
+	}
+	c.record(event)
+	return nil
+}
+
+// record updates the cached balances of the account of an event that has just been appended
+func (c *CachedBalanceStore) record(event *models.LedgerEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if event.Version > c.appended[event.AccountID] {
+		c.appended[event.AccountID] = event.Version
+	}
+	for key, cached := range c.balances {
+		if key.accountID != event.AccountID {
+			continue
+		}
+		if event.Version != cached.version+1 {
+			delete(c.balances, key)
+			continue
+		}
+		// A reversal whose original type is not recorded on it is left to the next read
+		balance, err := postEvent(cached.balance, event, nil)
+		if err != nil {
+			delete(c.balances, key)
+			continue
+		}
+		c.balances[key] = cachedBalance{balance: balance, version: event.Version}
+	}
+}
+
+// Balance returns the account's posted balance in currency, computing it from the stream on a miss
+func (c *CachedBalanceStore) Balance(ctx context.Context, accountID, currency string) (models.Money, error) {
+	key := balanceKey{accountID: accountID, currency: currency}
+
+	c.mu.Lock()
+	cached, ok := c.balances[key]
+	c.mu.Unlock()
+	if ok {
+		c.hits.Add(1)
+		return cached.balance, nil
+	}
+	c.misses.Add(1)
+
+	events, err := c.EventStore.Read(ctx, accountID, 1)
+	if err != nil {
+		return models.Money{}, err
+	}
+	balance, err := postedBalance(events, currency)
+	if err != nil {
+		return models.Money{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// An append that landed after the read makes this balance stale, so it is not cached
+	if head := headVersion(events); head >= c.appended[accountID] {
+		c.balances[key] = cachedBalance{balance: balance, version: head}
+	}
+	return balance, nil
+}
+
+// Stats returns the cache's hit and miss counts
+func (c *CachedBalanceStore) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
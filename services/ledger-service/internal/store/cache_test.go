@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestCachedBalanceStoreMatchesRecompute(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryStore()
+	cache := NewCachedBalanceStore(inner)
+
+	require.NoError(t, cache.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")))
+	balance, err := cache.Balance(ctx, "acc_1", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, usd(100), balance)
+
+	require.NoError(t, cache.Append(ctx, models.NewLedgerEvent(models.Debit, usd(30.25), "acc_1", "corr_2").WithVersion(2)))
+	require.NoError(t, cache.Append(ctx, models.NewLedgerEvent(models.Hold, usd(10), "acc_1", "corr_3").WithVersion(3)))
+
+	cached, err := cache.Balance(ctx, "acc_1", "USD")
+	require.NoError(t, err)
+	events, err := inner.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	recomputed, err := postedBalance(events, "USD")
+	require.NoError(t, err)
+	assert.Equal(t, recomputed, cached)
+
+	stats := cache.Stats()
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1}, stats)
+	assert.Equal(t, 0.5, stats.HitRate())
+}
+
+func TestCachedBalanceStoreConcurrentAppends(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryStore()
+	cache := NewCachedBalanceStore(inner)
+
+	var wg sync.WaitGroup
+	for _, accountID := range []string{"acc_1", "acc_2", "acc_3"} {
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			for version := int64(1); version <= 50; version++ {
+				event := models.NewLedgerEvent(models.Credit, usd(1.1), accountID, "corr_1").WithVersion(version)
+				assert.NoError(t, cache.Append(ctx, event))
+				_, err := cache.Balance(ctx, accountID, "USD")
+				assert.NoError(t, err)
+			}
+		}(accountID)
+	}
+	wg.Wait()
+
+	for _, accountID := range []string{"acc_1", "acc_2", "acc_3"} {
+		cached, err := cache.Balance(ctx, accountID, "USD")
+		require.NoError(t, err)
+		events, err := inner.Read(ctx, accountID, 1)
+		require.NoError(t, err)
+		recomputed, err := postedBalance(events, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, recomputed, cached, accountID)
+	}
+}
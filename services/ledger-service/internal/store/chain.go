@@ -0,0 +1,56 @@
+package store
+
+import (
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// chainHead returns the PreviousHash for the event following last, which is the latest
+// checkpoint's hash when that checkpoint covers last
+func chainHead(last *models.LedgerEvent, checkpoint *models.Checkpoint) string {
+	if checkpoint != nil && (last == nil || checkpoint.Version >= last.Version) {
+		return checkpoint.Hash()
+	}
+	if last == nil {
+		return ""
+	}
+	return last.ComputeHash()
+}
+
+// nextCheckpoint signs a checkpoint at the last of the events appended since prev,
+// carrying prev's balance forward over them in the currency of the account's first posting.
+// originals maps the events reversed since prev to their types, see postEvent.
+func nextCheckpoint(signer models.Signer, accountID string, prev *models.Checkpoint, since []*models.LedgerEvent,
+	originals map[string]models.EventType) (*models.Checkpoint, error) {
+	var balance models.Money
+	if prev != nil {
+		balance = prev.Balance
+	}
+	for _, event := range since {
+		if !event.PostsBalance() {
+			continue
+		}
+		if balance.Currency == "" {
+			balance.Currency = event.Amount.Currency
+			balance.Precision = event.Amount.Precision
+		}
+		var err error
+		if balance, err = postEvent(balance, event, originals); err != nil {
+			return nil, err
+		}
+	}
+
+	head := since[len(since)-1]
+	return models.NewCheckpoint(accountID, head.Version, head.ComputeHash(), balance, signer)
+}
+
+// verifyFromCheckpoint checks the checkpoint signature and that events link up from it
+func verifyFromCheckpoint(checkpoint *models.Checkpoint, events []*models.LedgerEvent, verifier models.Verifier) error {
+	start := ""
+	if checkpoint != nil {
+		if err := checkpoint.Verify(verifier); err != nil {
+			return err
+		}
+		start = checkpoint.Hash()
+	}
+	return models.VerifyChainFrom(start, events)
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Class tells callers whether a failed store operation may succeed if retried
+type Class int
+
+const (
+	// Permanent failures fail again on retry: invalid, unsigned or duplicate events, frozen or
+	// closed accounts
+	Permanent Class = iota
+	// Retryable failures are transient or caused by a concurrent writer: version conflicts,
+	// changed balances, timeouts and dropped database connections
+	Retryable
+)
+
+// String returns the class name
+func (c Class) String() string {
+	if c == Retryable {
+		return "retryable"
+	}
+	return "permanent"
+}
+
+// classifier is implemented by errors that know their own class
+type classifier interface {
+	Class() Class
+}
+
+// Postgres SQLSTATE classes of failures that may clear on retry
+var retryableSQLStateClasses = map[string]bool{
+	"08": true, // connection exception
+	"40": true, // transaction rollback, including serialization failures and deadlocks
+	"53": true, // insufficient resources
+	"57": true, // operator intervention, such as an admin shutdown
+}
+
+// ErrorClass classifies an error returned by a store. Errors are matched through wrapping, so
+// store errors keep their class when callers add context. Errors the store does not recognise
+// are Permanent, as is nil.
+func ErrorClass(err error) Class {
+	if err == nil {
+		return Permanent
+	}
+
+	var classified classifier
+	if errors.As(err, &classified) {
+		return classified.Class()
+	}
+
+	switch {
+	case errors.Is(err, ErrVersionConflict), errors.Is(err, ErrBalanceChanged), errors.Is(err, context.DeadlineExceeded):
+		return Retryable
+	case errors.Is(err, ErrInvalidEvent), errors.Is(err, ErrUnsignedEvent), errors.Is(err, ErrDuplicateEvent),
+		errors.Is(err, models.ErrAccountFrozen), errors.Is(err, context.Canceled):
+		return Permanent
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if len(pgErr.Code) >= 2 && retryableSQLStateClasses[pgErr.Code[:2]] {
+			return Retryable
+		}
+		return Permanent
+	}
+	if pgconn.SafeToRetry(err) || pgconn.Timeout(err) {
+		return Retryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Retryable
+	}
+	return Permanent
+}
+
+// Class is Retryable only when every failure in the batch is, so retrying can succeed
+func (e *BatchError) Class() Class {
+	if len(e.Failures) == 0 {
+		return Permanent
+	}
+	for _, err := range e.Failures {
+		if ErrorClass(err) != Retryable {
+			return Permanent
+		}
+	}
+	return Retryable
+}
+
+// Retry calls op until it succeeds, fails with a Permanent error, or has been attempted
+// maxAttempts times, doubling delay between attempts. Operations that hit a version conflict
+// should re-read the stream inside op, since the retry repeats op as given.
+func Retry(ctx context.Context, maxAttempts int, delay time.Duration, op func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil || ErrorClass(err) == Permanent {
+			return err
+		}
+		if attempt >= maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
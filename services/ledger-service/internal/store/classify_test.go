@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestErrorClassOfStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")))
+
+	conflict := s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_2"))
+	require.ErrorIs(t, conflict, ErrVersionConflict)
+	assert.Equal(t, Retryable, ErrorClass(conflict))
+	assert.Equal(t, Retryable, ErrorClass(fmt.Errorf("posting payment: %w", conflict)))
+
+	invalid := s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(-5), "acc_1", "corr_3").WithVersion(2))
+	require.ErrorIs(t, invalid, ErrInvalidEvent)
+	assert.Equal(t, Permanent, ErrorClass(invalid))
+
+	assert.Equal(t, Permanent, ErrorClass(errors.New("unknown")))
+	assert.Equal(t, Permanent, ErrorClass(nil))
+}
+
+func TestErrorClassOfDatabaseErrors(t *testing.T) {
+	assert.Equal(t, Retryable, ErrorClass(&pgconn.PgError{Code: "40001"}))
+	assert.Equal(t, Retryable, ErrorClass(&pgconn.PgError{Code: "08006"}))
+	assert.Equal(t, Permanent, ErrorClass(&pgconn.PgError{Code: "23503"}))
+	assert.Equal(t, Retryable, ErrorClass(context.DeadlineExceeded))
+}
+
+func TestErrorClassOfBatchErrors(t *testing.T) {
+	retryable := &BatchError{Mode: BestEffort, Failures: map[int]error{0: ErrVersionConflict, 2: ErrBalanceChanged}}
+	assert.Equal(t, Retryable, ErrorClass(retryable))
+
+	mixed := &BatchError{Mode: BestEffort, Failures: map[int]error{0: ErrVersionConflict, 1: ErrInvalidEvent}}
+	assert.Equal(t, Permanent, ErrorClass(mixed))
+}
+
+func TestRetryStopsOnPermanentErrors(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, 5, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return ErrVersionConflict
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = Retry(ctx, 5, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		return ErrInvalidEvent
+	})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	err = Retry(ctx, 2, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		return ErrVersionConflict
+	})
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	assert.Equal(t, 2, calls)
+}
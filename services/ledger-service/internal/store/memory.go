@@ -0,0 +1,382 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// MemoryStore is an in-memory EventStore, intended for tests and local development. It keeps
+// and hands out clones, so callers mutating their events cannot change what is stored.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	streams     map[string][]*models.LedgerEvent
+	ids         map[string]struct{}
+	contents    map[string]string
+	checkpoints map[string][]*models.Checkpoint
+	manifests   map[string][]string
+	opts        options
+}
+
+// NewMemoryStore creates an empty in-memory event store
+func NewMemoryStore(opts ...Option) *MemoryStore {
+	return &MemoryStore{
+		streams:     make(map[string][]*models.LedgerEvent),
+		ids:         make(map[string]struct{}),
+		contents:    make(map[string]string),
+		checkpoints: make(map[string][]*models.Checkpoint),
+		manifests:   make(map[string][]string),
+		opts:        newOptions(opts),
+	}
+}
+
+// Append validates the event and appends it to its account stream
+func (s *MemoryStore) Append(ctx context.Context, event *models.LedgerEvent) error {
+	if err := s.opts.admit(event); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.appendLocked(event)
+	if err != nil {
+		return err
+	}
+	adopt(event, stored)
+	return nil
+}
+
+// AppendIfBalance appends the event only if the account's current posted balance equals expected.
+// The balance check and the append happen under the same lock, so concurrent callers racing on
+// the same expected balance cannot both succeed.
+func (s *MemoryStore) AppendIfBalance(ctx context.Context, event *models.LedgerEvent, expected models.Money) error {
+	if err := s.opts.admit(event); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := postedBalance(s.streams[event.AccountID], expected.Currency)
+	if err != nil {
+		return err
+	}
+	if err := checkExpectedBalance(event.AccountID, current, expected); err != nil {
+		return err
+	}
+
+	stored, err := s.appendLocked(event)
+	if err != nil {
+		return err
+	}
+	adopt(event, stored)
+	return nil
+}
+
+// appendLocked appends a clone of a validated event and returns the clone as stored, leaving
+// the caller's event untouched; the caller must hold the write lock
+func (s *MemoryStore) appendLocked(event *models.LedgerEvent) (*models.LedgerEvent, error) {
+	if _, exists := s.ids[event.ID]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrDuplicateEvent, event.ID)
+	}
+	contentHash := event.ContentHash()
+	if existing, exists := s.contents[contentHash]; contentHash != "" && exists {
+		return nil, fmt.Errorf("%w: %q already appended as %s", ErrDuplicateContent, event.IdempotencyKey, existing)
+	}
+
+	stream := s.streams[event.AccountID]
+	if err := s.opts.checkVersion(event, headVersion(stream)); err != nil {
+		return nil, err
+	}
+	if err := models.CheckFreeze(stream, event); err != nil {
+		return nil, err
+	}
+	if err := models.CheckClosed(stream, event); err != nil {
+		return nil, err
+	}
+
+	event = event.Clone()
+
+	var last *models.LedgerEvent
+	if len(stream) > 0 {
+		last = stream[len(stream)-1]
+	}
+	if err := s.opts.adjustTimestamp(event, last); err != nil {
+		return nil, err
+	}
+
+	checkpoint := s.latestCheckpointLocked(event.AccountID)
+	if s.opts.chaining() {
+		event.HashLength = s.opts.hashLength
+		event.PreviousHash = chainHead(last, checkpoint)
+	}
+
+	s.streams[event.AccountID] = append(stream, event)
+
+	var covered int64
+	if checkpoint != nil {
+		covered = checkpoint.Version
+	}
+	if s.opts.checkpointDue(int64(len(stream) + 1 - indexAfter(stream, covered))) {
+		if _, err := s.checkpointLocked(event.AccountID); err != nil {
+			s.streams[event.AccountID] = stream
+			return nil, err
+		}
+	}
+
+	s.ids[event.ID] = struct{}{}
+	if contentHash != "" {
+		s.contents[contentHash] = event.ID
+	}
+	return event, nil
+}
+
+// adopt copies the fields the store set on stored, such as its chain hash and any adjusted
+// timestamp, back to the caller's event once the append has committed
+func adopt(event, stored *models.LedgerEvent) {
+	*event = *stored.Clone()
+}
+
+// Checkpoint records a signed checkpoint at the account's current head, returning the existing
+// one if the head is already checkpointed
+func (s *MemoryStore) Checkpoint(ctx context.Context, accountID string) (*models.Checkpoint, error) {
+	if !s.opts.chaining() {
+		return nil, ErrChainingDisabled
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.checkpointLocked(accountID)
+}
+
+// LatestCheckpoint returns the account's most recent checkpoint, or nil if it has none
+func (s *MemoryStore) LatestCheckpoint(ctx context.Context, accountID string) (*models.Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.latestCheckpointLocked(accountID), nil
+}
+
+// VerifyChain verifies the account's chain starting from its latest checkpoint and returns
+// how many events had to be checked
+func (s *MemoryStore) VerifyChain(ctx context.Context, accountID string, verifier models.Verifier) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoint := s.latestCheckpointLocked(accountID)
+	events := s.streams[accountID]
+	if checkpoint != nil {
+		events = events[indexAfter(events, checkpoint.Version):]
+	}
+	if err := verifyFromCheckpoint(checkpoint, events, verifier); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// GetReferencing scans every stream for events referencing targetID
+func (s *MemoryStore) GetReferencing(ctx context.Context, targetID string, kinds ...models.RefKind) ([]*models.LedgerEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	accountIDs := make([]string, 0, len(s.streams))
+	for accountID := range s.streams {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+
+	events := []*models.LedgerEvent{}
+	for _, accountID := range accountIDs {
+		for _, event := range s.streams[accountID] {
+			if references(event, targetID, kinds) {
+				events = append(events, event.Clone())
+			}
+		}
+	}
+	return events, nil
+}
+
+// checkpointLocked records a checkpoint at the account head; the caller must hold the write lock
+func (s *MemoryStore) checkpointLocked(accountID string) (*models.Checkpoint, error) {
+	stream := s.streams[accountID]
+	if len(stream) == 0 {
+		return nil, fmt.Errorf("account %s has no events to checkpoint", accountID)
+	}
+
+	prev := s.latestCheckpointLocked(accountID)
+	var covered int64
+	if prev != nil {
+		covered = prev.Version
+	}
+	if covered == headVersion(stream) {
+		return prev, nil
+	}
+
+	checkpoint, err := nextCheckpoint(s.opts.checkpointKey, accountID, prev, stream[indexAfter(stream, covered):], models.ReversibleTypes(stream))
+	if err != nil {
+		return nil, err
+	}
+	s.checkpoints[accountID] = append(s.checkpoints[accountID], checkpoint)
+	return checkpoint, nil
+}
+
+// latestCheckpointLocked returns the account's most recent checkpoint; the caller must hold the lock
+func (s *MemoryStore) latestCheckpointLocked(accountID string) *models.Checkpoint {
+	checkpoints := s.checkpoints[accountID]
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	return checkpoints[len(checkpoints)-1]
+}
+
+// AppendBatch appends the events in order under a single lock. In Atomic mode any failure
+// rolls back the events already appended by this call.
+func (s *MemoryStore) AppendBatch(ctx context.Context, events []*models.LedgerEvent, mode BatchMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.appendBatchLocked(events, mode)
+}
+
+// AppendBatchIdempotent appends the events atomically and records their IDs under batchKey.
+// A repeat submission under the same key returns the recorded IDs without appending anything.
+func (s *MemoryStore) AppendBatchIdempotent(ctx context.Context, events []*models.LedgerEvent, batchKey string) ([]string, error) {
+	if batchKey == "" {
+		return nil, ErrEmptyBatchKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ids, ok := s.manifests[batchKey]; ok {
+		return append([]string(nil), ids...), nil
+	}
+	if err := s.appendBatchLocked(events, Atomic); err != nil {
+		return nil, err
+	}
+
+	ids := eventIDs(events)
+	s.manifests[batchKey] = ids
+	return append([]string(nil), ids...), nil
+}
+
+// appendBatchLocked implements AppendBatch; the caller must hold the write lock
+func (s *MemoryStore) appendBatchLocked(events []*models.LedgerEvent, mode BatchMode) error {
+	failures := make(map[int]error)
+	lengths := make(map[string]int)
+	checkpointLengths := make(map[string]int)
+	appended := make(map[int]*models.LedgerEvent, len(events))
+
+	for i, event := range events {
+		if err := s.opts.admit(event); err != nil {
+			failures[i] = err
+			continue
+		}
+		if _, seen := lengths[event.AccountID]; !seen {
+			lengths[event.AccountID] = len(s.streams[event.AccountID])
+			checkpointLengths[event.AccountID] = len(s.checkpoints[event.AccountID])
+		}
+		stored, err := s.appendLocked(event)
+		if err != nil {
+			failures[i] = err
+			continue
+		}
+		appended[i] = stored
+	}
+
+	if len(failures) > 0 && mode == Atomic {
+		s.rollbackLocked(lengths, checkpointLengths, appended)
+		return &BatchError{Mode: mode, Failures: failures}
+	}
+	for i, stored := range appended {
+		adopt(events[i], stored)
+	}
+	if len(failures) > 0 {
+		return &BatchError{Mode: mode, Failures: failures}
+	}
+	return nil
+}
+
+// rollbackLocked truncates streams and checkpoints to their recorded lengths and forgets the appended event IDs
+func (s *MemoryStore) rollbackLocked(lengths, checkpointLengths map[string]int, appended map[int]*models.LedgerEvent) {
+	for accountID, length := range lengths {
+		if length == 0 {
+			delete(s.streams, accountID)
+		} else {
+			s.streams[accountID] = s.streams[accountID][:length]
+		}
+	}
+	for accountID, length := range checkpointLengths {
+		if length == 0 {
+			delete(s.checkpoints, accountID)
+		} else {
+			s.checkpoints[accountID] = s.checkpoints[accountID][:length]
+		}
+	}
+	for _, event := range appended {
+		delete(s.ids, event.ID)
+		delete(s.contents, event.ContentHash())
+	}
+}
+
+// Read returns the account's events with a version >= fromVersion
+func (s *MemoryStore) Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[accountID]
+	stream = stream[indexAfter(stream, fromVersion-1):]
+
+	events := make([]*models.LedgerEvent, 0, len(stream))
+	for _, event := range stream {
+		events = append(events, event.Clone())
+	}
+	return events, nil
+}
+
+// Stream delivers the account's events with a version >= fromVersion on a channel
+func (s *MemoryStore) Stream(ctx context.Context, accountID string, fromVersion int64) (<-chan *models.LedgerEvent, error) {
+	events, err := s.Read(ctx, accountID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return streamEvents(ctx, events), nil
+}
+
+// UpdateSignature replaces the signature fields of the stored event at event's version
+func (s *MemoryStore) UpdateSignature(ctx context.Context, event *models.LedgerEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.streams[event.AccountID]
+	i := indexAfter(stream, event.Version-1)
+	var stored *models.LedgerEvent
+	if i < len(stream) && stream[i].Version == event.Version {
+		stored = stream[i]
+	}
+	if err := checkSignatureUpdate(stored, event); err != nil {
+		return err
+	}
+	stream[i] = event.Clone()
+	return nil
+}
+
+// headVersion returns the version of the stream's last event, or 0 for an empty stream
+func headVersion(stream []*models.LedgerEvent) int64 {
+	if len(stream) == 0 {
+		return 0
+	}
+	return stream[len(stream)-1].Version
+}
+
+// indexAfter returns the index of the first event in the version-ordered stream whose version is above version
+func indexAfter(stream []*models.LedgerEvent, version int64) int {
+	return sort.Search(len(stream), func(i int) bool {
+		return stream[i].Version > version
+	})
+}
@@ -0,0 +1,424 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func usd(amount float64) models.Money {
+	return models.NewMoney(int64(math.Round(amount*100)), "USD", 2)
+}
+
+func TestMemoryStoreAppendIfBalanceConcurrentDebits(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+
+	const attempts = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		changed   int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			debit := models.NewLedgerEvent(models.Debit, usd(30), "acc_1", "corr_1").WithVersion(2)
+			err := s.AppendIfBalance(ctx, debit, usd(100))
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrBalanceChanged):
+				changed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, attempts-1, changed)
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestMemoryStoreAppendIfBalanceMatchesAfterDebit(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(0.3), "acc_1", "corr_0")))
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Debit, usd(0.1), "acc_1", "corr_1").WithVersion(2)))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(0.2), "acc_1", "corr_2").WithVersion(3)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, debit, usd(0.3)), ErrBalanceChanged)
+	assert.NoError(t, s.AppendIfBalance(ctx, debit, usd(0.2)))
+}
+
+func TestMemoryStoreAppendIfBalanceAfterReversal(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	credit := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")
+	debit := models.NewLedgerEvent(models.Debit, usd(30), "acc_1", "corr_1").WithVersion(2)
+	require.NoError(t, s.Append(ctx, credit))
+	require.NoError(t, s.Append(ctx, debit))
+	require.NoError(t, s.Append(ctx, models.NewReversal(debit, "corr_2").WithVersion(3)))
+
+	next := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").WithVersion(4)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, next, usd(70)), ErrBalanceChanged)
+	assert.NoError(t, s.AppendIfBalance(ctx, next, usd(100)))
+
+	require.NoError(t, s.Append(ctx, models.NewReversal(credit, "corr_4").WithVersion(5)))
+	last := models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_5").WithVersion(6)
+	assert.NoError(t, s.AppendIfBalance(ctx, last, usd(-10)))
+}
+
+func TestMemoryStoreCheckpointCarriesReversals(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(WithChaining(0, key))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(4), "acc_1", "corr_1")
+	require.NoError(t, s.Append(ctx, debit))
+	_, err := s.Checkpoint(ctx, "acc_1")
+	require.NoError(t, err)
+
+	reversal := models.NewReversal(debit, "corr_2").WithVersion(2)
+	delete(reversal.Metadata, models.MetaReversedType)
+	require.NoError(t, s.Append(ctx, reversal))
+	checkpoint, err := s.Checkpoint(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.True(t, checkpoint.Balance.Equal(usd(0)), "the reversed debit is found before the previous checkpoint")
+}
+
+func TestMemoryStoreAppendIfBalanceSkipsOtherCurrencies(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(50), "acc_1", "corr_0")))
+	eur := models.NewMoney(2000, "EUR", 2)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, eur, "acc_1", "corr_1").WithVersion(2)))
+	fine := models.NewMoney(1005, "USD", 3)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Debit, fine, "acc_1", "corr_2").WithVersion(3)))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(1), "acc_1", "corr_3").WithVersion(4)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, debit, models.NewMoney(0, "EUR", 2)), ErrBalanceChanged)
+	assert.NoError(t, s.AppendIfBalance(ctx, debit, models.NewMoney(48995, "USD", 3)))
+
+	overflow := models.NewLedgerEvent(models.Credit, models.NewMoney(math.MaxInt64, "USD", 2), "acc_1", "corr_4").WithVersion(5)
+	require.NoError(t, s.Append(ctx, overflow))
+	next := models.NewLedgerEvent(models.Debit, usd(1), "acc_1", "corr_5").WithVersion(6)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, next, usd(0)), models.ErrAmountOverflow)
+}
+
+func TestMemoryStoreRequireSignature(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(RequireSignature(key))
+
+	unsigned := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1")
+	assert.ErrorIs(t, s.Append(ctx, unsigned), ErrUnsignedEvent)
+
+	forged := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1")
+	require.NoError(t, forged.SignWith(models.NewHMACKey("ledger-1", []byte("guess"))))
+	assert.ErrorIs(t, s.Append(ctx, forged), models.ErrInvalidSignature)
+
+	signed := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1")
+	require.NoError(t, signed.SignWith(key))
+	require.NoError(t, s.Append(ctx, signed))
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, signed.ID, events[0].ID)
+}
+
+func TestMemoryStoreEnforcesMetadataPolicy(t *testing.T) {
+	ctx := context.Background()
+	policies := map[models.EventType]models.MetadataPolicy{
+		models.Debit: {Allowed: []string{"description"}, Required: []string{"channel"}},
+	}
+	s := NewMemoryStore(WithMetadataPolicy(models.NewMetadataValidator(policies, true)))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_1").
+		WithMetadata("channel", "card").
+		WithMetadata("customerEmail", "a@example.com")
+	err := s.Append(ctx, debit)
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+	assert.ErrorIs(t, err, models.ErrUnknownMetadataKey)
+
+	unlabelled := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_2")
+	assert.ErrorIs(t, s.Append(ctx, unlabelled), models.ErrMissingMetadataKey)
+
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").
+		WithMetadata("channel", "card")))
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestMemoryStoreEnforcesFreeze(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")))
+	require.NoError(t, s.Append(ctx, models.NewAccountFreeze("acc_1", "corr_2", "compliance hold").WithVersion(2)))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").WithVersion(3)
+	assert.ErrorIs(t, s.Append(ctx, debit), models.ErrAccountFrozen)
+	credit := models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_4").WithVersion(3)
+	require.NoError(t, s.Append(ctx, credit))
+	assert.ErrorIs(t, s.Append(ctx, models.NewReversal(credit, "corr_4").WithVersion(4)), models.ErrAccountFrozen)
+
+	require.NoError(t, s.Append(ctx, models.NewAccountUnfreeze("acc_1", "corr_5", "review complete").WithVersion(4)))
+	assert.NoError(t, s.Append(ctx, debit.WithVersion(5)))
+}
+
+func TestMemoryStoreRejectsDebitsAfterClose(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")))
+
+	stream, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	closing, err := models.CloseAccount(stream, "corr_close")
+	require.NoError(t, err)
+	for _, event := range closing {
+		require.NoError(t, s.Append(ctx, event))
+	}
+
+	debit := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_2").WithVersion(4)
+	assert.ErrorIs(t, s.Append(ctx, debit), models.ErrAccountClosed)
+	assert.Equal(t, Permanent, ErrorClass(s.Append(ctx, debit)))
+
+	reversal := models.NewReversal(stream[0], "corr_3").WithVersion(4)
+	assert.ErrorIs(t, s.Append(ctx, reversal), models.ErrAccountClosed, "a reversal would move the closed balance off zero")
+}
+
+func TestMemoryStoreIsolatesStoredEvents(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	event := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1").WithMetadata("channel", "card")
+	require.NoError(t, s.Append(ctx, event))
+
+	// Building on the appended event or on a read copy leaves the stored event alone
+	event.WithMetadata("channel", "wire").WithPaymentID("pay_1")
+	read, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	read[0].Metadata["channel"] = "cash"
+
+	stored, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "card", stored[0].Metadata["channel"])
+	assert.Nil(t, stored[0].PaymentID)
+}
+
+func TestMemoryStoreVerifyChainStartsFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(WithChaining(4, key))
+
+	for version := int64(1); version <= 6; version++ {
+		event := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1").WithVersion(version)
+		require.NoError(t, s.Append(ctx, event))
+	}
+
+	checkpoint, err := s.LatestCheckpoint(ctx, "acc_1")
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, int64(4), checkpoint.Version)
+	assert.Equal(t, usd(40), checkpoint.Balance)
+
+	verified, err := s.VerifyChain(ctx, "acc_1", key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, verified)
+
+	// Events behind the checkpoint are no longer needed to verify the chain
+	s.streams["acc_1"][1].Metadata["tampered"] = true
+	verified, err = s.VerifyChain(ctx, "acc_1", key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, verified)
+
+	s.streams["acc_1"][4].Metadata["tampered"] = true
+	_, err = s.VerifyChain(ctx, "acc_1", key)
+	var chainErr *models.ChainBreakError
+	require.ErrorAs(t, err, &chainErr)
+	assert.Equal(t, 1, chainErr.Index)
+}
+
+func TestMemoryStoreTruncatedChain(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(WithChaining(3, key), WithChainHashLength(16))
+
+	for version := int64(1); version <= 5; version++ {
+		event := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1").WithVersion(version)
+		require.NoError(t, s.Append(ctx, event))
+		assert.Equal(t, 16, event.HashLength)
+	}
+	events, err := s.Read(ctx, "acc_1", 4)
+	require.NoError(t, err)
+	assert.Len(t, events[0].PreviousHash, 32, "the checkpoint hash takes the chain's length")
+
+	verified, err := s.VerifyChain(ctx, "acc_1", key)
+	require.NoError(t, err)
+	assert.Equal(t, 2, verified)
+}
+
+func TestMemoryStoreCheckpointOnDemand(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(WithChaining(0, key))
+
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1")))
+	checkpoint, err := s.Checkpoint(ctx, "acc_1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), checkpoint.Version)
+
+	next := models.NewLedgerEvent(models.Debit, usd(4), "acc_1", "corr_1").WithVersion(2)
+	require.NoError(t, s.Append(ctx, next))
+	assert.Equal(t, checkpoint.Hash(), next.PreviousHash)
+
+	verified, err := s.VerifyChain(ctx, "acc_1", key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, verified)
+
+	_, err = NewMemoryStore().Checkpoint(ctx, "acc_1")
+	assert.ErrorIs(t, err, ErrChainingDisabled)
+}
+
+func TestMemoryStoreMonotonicTimestamps(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	s := NewMemoryStore(RequireSignature(key), MonotonicTimestamps(key))
+
+	head := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1").WithVersion(1)
+	first.Timestamp = head
+	require.NoError(t, first.SignWith(key))
+	require.NoError(t, s.Append(ctx, first))
+
+	skewed := head.Add(-3 * time.Second)
+	second := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_2").WithVersion(2)
+	second.Timestamp = skewed
+	require.NoError(t, second.SignWith(key))
+	require.NoError(t, s.Append(ctx, second))
+
+	assert.Equal(t, head.Add(time.Microsecond), second.Timestamp)
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	stored := events[1]
+	assert.Equal(t, head.Add(time.Microsecond), stored.Timestamp)
+	assert.Equal(t, skewed.Format(time.RFC3339Nano), stored.Metadata[MetaOriginalTimestamp])
+	assert.NoError(t, stored.VerifyWith(key))
+
+	// A timestamp equal to or after the head is kept as is
+	third := models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_3").WithVersion(3)
+	third.Timestamp = stored.Timestamp
+	require.NoError(t, third.SignWith(key))
+	require.NoError(t, s.Append(ctx, third))
+	assert.Equal(t, stored.Timestamp, third.Timestamp)
+	assert.NotContains(t, third.Metadata, MetaOriginalTimestamp)
+}
+
+func TestMemoryStoreMonotonicTimestampsWithoutSigner(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(MonotonicTimestamps(nil))
+
+	first := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, s.Append(ctx, first))
+
+	unsigned := models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_2").WithVersion(2)
+	unsigned.Timestamp = first.Timestamp.Add(-time.Hour)
+	require.NoError(t, s.Append(ctx, unsigned))
+	assert.True(t, unsigned.Timestamp.After(first.Timestamp))
+
+	signed := models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_3").WithVersion(3)
+	signed.Timestamp = first.Timestamp.Add(-time.Hour)
+	require.NoError(t, signed.Sign("legacy-key"))
+	assert.ErrorIs(t, s.Append(ctx, signed), ErrInvalidEvent)
+}
+
+func TestMemoryStoreFailedAppendLeavesEventUntouched(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(MonotonicTimestamps(nil))
+	first := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, s.Append(ctx, first))
+
+	hold := models.NewLedgerEvent(models.Hold, usd(40), "acc_1", "corr_2").WithVersion(2)
+	hold.Timestamp = first.Timestamp.Add(-time.Minute)
+	hold.WithExpiry(time.Second)
+	before := hold.Clone()
+	assert.ErrorIs(t, s.Append(ctx, hold), ErrInvalidEvent, "the adjusted hold would expire before it is placed")
+	assert.Equal(t, before, hold)
+
+	hold.WithExpiry(time.Hour)
+	require.NoError(t, s.Append(ctx, hold))
+	assert.True(t, hold.Timestamp.After(first.Timestamp), "a committed adjustment is copied back")
+	assert.Contains(t, hold.Metadata, MetaOriginalTimestamp)
+}
+
+func TestMemoryStoreRejectsDuplicateIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+
+	first := models.NewLedgerEvent(models.Debit, usd(25), "acc_1", "corr_1").WithVersion(2).WithIdempotencyKey("pay_1")
+	require.NoError(t, s.Append(ctx, first))
+
+	retry := models.NewLedgerEvent(models.Debit, usd(25), "acc_1", "corr_1").WithVersion(3).WithIdempotencyKey("pay_1")
+	err := s.Append(ctx, retry)
+	require.ErrorIs(t, err, ErrDuplicateContent)
+	assert.Contains(t, err.Error(), first.ID)
+
+	other := models.NewLedgerEvent(models.Debit, usd(25), "acc_1", "corr_2").WithVersion(3).WithIdempotencyKey("pay_2")
+	require.NoError(t, s.Append(ctx, other))
+
+	batch := []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Debit, usd(5), "acc_1", "corr_3").WithVersion(4).WithIdempotencyKey("pay_3"),
+		models.NewLedgerEvent(models.Debit, usd(25), "acc_1", "corr_3").WithVersion(5).WithIdempotencyKey("pay_1"),
+	}
+	require.Error(t, s.AppendBatch(ctx, batch, Atomic))
+	require.NoError(t, s.Append(ctx, batch[0]), "a rolled back batch releases its idempotency keys")
+}
+
+func TestMemoryStoreStream(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	for version := int64(1); version <= 4; version++ {
+		require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1").WithVersion(version)))
+	}
+	require.ErrorIs(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1").WithVersion(3)), ErrVersionConflict)
+	require.ErrorIs(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(-10), "acc_1", "corr_1").WithVersion(5)), ErrInvalidEvent)
+
+	stream, err := s.Stream(ctx, "acc_1", 2)
+	require.NoError(t, err)
+	var versions []int64
+	for event := range stream {
+		versions = append(versions, event.Version)
+	}
+	assert.Equal(t, []int64{2, 3, 4}, versions)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	stream, err = s.Stream(cancelled, "acc_1", 1)
+	require.NoError(t, err)
+	first := <-stream
+	assert.Equal(t, int64(1), first.Version)
+	cancel()
+	for range stream {
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestVerifyMigrationDetectsCorruptedEvent(t *testing.T) {
+	ctx := context.Background()
+	key := models.NewHMACKey("ledger-1", []byte("secret"))
+	oldStore, newStore := NewMemoryStore(), NewMemoryStore()
+
+	var corrupted *models.LedgerEvent
+	for _, accountID := range []string{"acc_1", "acc_2"} {
+		for version := int64(1); version <= 5; version++ {
+			event := models.NewLedgerEvent(models.Credit, models.NewMoney(version*100, "USD", 2), accountID, "corr_1").
+				WithVersion(version)
+			require.NoError(t, event.SignWith(key))
+			require.NoError(t, oldStore.Append(ctx, event))
+
+			migrated := *event
+			if accountID == "acc_2" && version == 3 {
+				migrated.Amount = models.NewMoney(30000, "USD", 2)
+				corrupted = &migrated
+			}
+			if accountID == "acc_2" && version == 5 {
+				continue
+			}
+			require.NoError(t, newStore.Append(ctx, &migrated))
+		}
+	}
+
+	report, err := VerifyMigration(ctx, oldStore, newStore, []models.AccountID{"acc_1", "acc_2"})
+	require.NoError(t, err)
+	assert.False(t, report.Verified())
+	require.Len(t, report.Accounts, 2)
+
+	assert.True(t, report.Accounts[0].Verified())
+	assert.Equal(t, 5, report.Accounts[0].Compared)
+
+	account := report.Accounts[1]
+	assert.Equal(t, 5, account.Compared)
+	assert.Equal(t, []MigrationDiscrepancy{
+		{EventID: corrupted.ID, Version: 3, Reason: "canonical content differs"},
+	}, account.Mismatched)
+	require.Len(t, account.Missing, 1)
+	assert.Equal(t, int64(5), account.Missing[0].Version)
+	assert.Empty(t, account.Extra)
+
+	// Comparing the other way round reports the dropped event as extra
+	report, err = VerifyMigration(ctx, newStore, oldStore, []models.AccountID{"acc_2"})
+	require.NoError(t, err)
+	require.Len(t, report.Accounts[0].Extra, 1)
+	assert.Equal(t, int64(5), report.Accounts[0].Extra[0].Version)
+}
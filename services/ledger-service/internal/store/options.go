@@ -0,0 +1,172 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Option configures the append policy of a store
+type Option func(*options)
+
+// options holds the append policy shared by the store implementations
+type options struct {
+	verifier        models.Verifier
+	checkpointEvery int
+	checkpointKey   models.Signer
+	hashLength      int
+	versionGaps     bool
+	monotonic       bool
+	timestampSigner models.Signer
+	metadata        *models.MetadataValidator
+}
+
+// MetaOriginalTimestamp records, in RFC 3339 form, the timestamp an event carried before
+// MonotonicTimestamps moved it forward
+const MetaOriginalTimestamp = "originalTimestamp"
+
+// monotonicStep is how far past the head a skewed timestamp is moved; Postgres keeps microseconds
+const monotonicStep = time.Microsecond
+
+// RequireSignature makes the store verify every appended event with verifier,
+// rejecting unsigned events with ErrUnsignedEvent and bad signatures with models.ErrInvalidSignature
+func RequireSignature(verifier models.Verifier) Option {
+	return func(o *options) {
+		o.verifier = verifier
+	}
+}
+
+// WithChaining makes the store link every appended event to its account head by setting the
+// event's PreviousHash, and record a checkpoint signed by signer after every `every` events.
+// Chain verification then starts from the latest checkpoint, so its cost is bounded by `every`.
+func WithChaining(every int, signer models.Signer) Option {
+	return func(o *options) {
+		o.checkpointEvery = every
+		o.checkpointKey = signer
+	}
+}
+
+// WithChainHashLength makes a chaining store truncate chain hashes to length bytes, between
+// models.MinHashLength and models.FullHashLength; see models.WithHashLength for the collision
+// trade-off. Other lengths are ignored. A chain does not verify across a change of length, so
+// set it when an account's chain starts.
+func WithChainHashLength(length int) Option {
+	return func(o *options) {
+		if length >= models.MinHashLength && length <= models.FullHashLength {
+			o.hashLength = length
+		}
+	}
+}
+
+// AllowVersionGaps relaxes the version check so an event only needs a version above the stream
+// head instead of exactly the next one. It is meant for stores behind a RoutingStore, where an
+// account's stream is split across backends and the router enforces contiguous versions.
+func AllowVersionGaps() Option {
+	return func(o *options) {
+		o.versionGaps = true
+	}
+}
+
+// MonotonicTimestamps makes the store keep timestamps non-decreasing within each account stream.
+// An event stamped before the stream head, typically by a skewed clock, is moved to just after
+// the head's timestamp and its original timestamp is kept under MetaOriginalTimestamp; the
+// caller's event carries the adjusted timestamp once the append succeeds. Both fields are
+// signed, so signed events are re-signed with signer after the adjustment; with a nil signer
+// a signed event that needs adjusting is rejected with ErrInvalidEvent, as is one the
+// adjustment leaves invalid, such as a hold expiring before its new timestamp.
+func MonotonicTimestamps(signer models.Signer) Option {
+	return func(o *options) {
+		o.monotonic = true
+		o.timestampSigner = signer
+	}
+}
+
+// WithMetadataPolicy makes the store check every appended event against validator's
+// per-type metadata policies. A strict validator rejects violations with ErrInvalidEvent
+// wrapping models.ErrUnknownMetadataKey or models.ErrMissingMetadataKey; a lenient one only
+// logs them.
+func WithMetadataPolicy(validator *models.MetadataValidator) Option {
+	return func(o *options) {
+		o.metadata = validator
+	}
+}
+
+// adjustTimestamp moves the event's timestamp to just after the head's if it is earlier
+func (o options) adjustTimestamp(event, head *models.LedgerEvent) error {
+	if !o.monotonic || head == nil || !event.Timestamp.Before(head.Timestamp) {
+		return nil
+	}
+	if event.Signature != "" && o.timestampSigner == nil {
+		return fmt.Errorf("%w: signed event %s is stamped before the head of account %s",
+			ErrInvalidEvent, event.ID, event.AccountID)
+	}
+
+	event.WithMetadata(MetaOriginalTimestamp, event.Timestamp.Format(time.RFC3339Nano))
+	event.Timestamp = head.Timestamp.Add(monotonicStep)
+	if err := event.Validate(); err != nil {
+		return fmt.Errorf("%w: adjusted timestamp of %s: %w", ErrInvalidEvent, event.ID, err)
+	}
+	if event.Signature != "" {
+		if err := event.SignWith(o.timestampSigner); err != nil {
+			return fmt.Errorf("failed to re-sign event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// checkVersion checks the event's version against the version of the stream head
+func (o options) checkVersion(event *models.LedgerEvent, head int64) error {
+	if o.versionGaps {
+		if event.Version <= head {
+			return fmt.Errorf("%w: account %s expects a version above %d, got %d",
+				ErrVersionConflict, event.AccountID, head, event.Version)
+		}
+		return nil
+	}
+	if expected := head + 1; event.Version != expected {
+		return fmt.Errorf("%w: account %s expects version %d, got %d",
+			ErrVersionConflict, event.AccountID, expected, event.Version)
+	}
+	return nil
+}
+
+// chaining reports whether appended events are hash-chained
+func (o options) chaining() bool {
+	return o.checkpointKey != nil
+}
+
+// checkpointDue reports whether enough events follow the latest checkpoint to record a new one
+func (o options) checkpointDue(sinceCheckpoint int64) bool {
+	return o.chaining() && o.checkpointEvery > 0 && sinceCheckpoint >= int64(o.checkpointEvery)
+}
+
+// newOptions applies opts over the default policy
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// admit checks that an event may be appended under the store's policy
+func (o options) admit(event *models.LedgerEvent) error {
+	validate := event.Validate
+	if o.metadata != nil {
+		validate = func() error { return o.metadata.Validate(event) }
+	}
+	if err := validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+
+	if o.verifier != nil {
+		if event.Signature == "" {
+			return fmt.Errorf("%w: %s", ErrUnsignedEvent, event.ID)
+		}
+		if err := event.VerifyWith(o.verifier); err != nil {
+			return fmt.Errorf("event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
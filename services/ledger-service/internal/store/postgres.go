@@ -0,0 +1,678 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// uniqueViolation is the Postgres SQLSTATE for a unique constraint violation
+const uniqueViolation = "23505"
+
+// PostgresStore is an EventStore backed by the ledger_events table
+type PostgresStore struct {
+	pool *pgxpool.Pool
+	opts options
+}
+
+// NewPostgresStore creates a store using the given connection pool
+func NewPostgresStore(pool *pgxpool.Pool, opts ...Option) *PostgresStore {
+	return &PostgresStore{pool: pool, opts: newOptions(opts)}
+}
+
+// Append validates the event and appends it to its account stream in its own transaction
+func (s *PostgresStore) Append(ctx context.Context, event *models.LedgerEvent) error {
+	if err := s.opts.admit(event); err != nil {
+		return err
+	}
+
+	var stored *models.LedgerEvent
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		var err error
+		stored, err = s.appendTx(ctx, tx, event)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	adopt(event, stored)
+	return nil
+}
+
+// AppendIfBalance appends the event only if the account's current posted balance equals expected.
+// The balance is read and the event appended in one transaction holding the account's advisory
+// lock, so concurrent callers racing on the same expected balance cannot both succeed.
+func (s *PostgresStore) AppendIfBalance(ctx context.Context, event *models.LedgerEvent, expected models.Money) error {
+	if err := s.opts.admit(event); err != nil {
+		return err
+	}
+
+	var stored *models.LedgerEvent
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := lockAccountTx(ctx, tx, event.AccountID); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx,
+			`SELECT payload FROM ledger_events WHERE account_id = $1 ORDER BY version`,
+			event.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to read account %s: %w", event.AccountID, err)
+		}
+		stream, err := scanEvents(rows)
+		if err != nil {
+			return err
+		}
+		current, err := postedBalance(stream, expected.Currency)
+		if err != nil {
+			return err
+		}
+		if err := checkExpectedBalance(event.AccountID, current, expected); err != nil {
+			return err
+		}
+		stored, err = s.appendTx(ctx, tx, event)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	adopt(event, stored)
+	return nil
+}
+
+// AppendBatch appends the events in order. Atomic mode uses a single transaction that is
+// rolled back on the first failure; BestEffort appends each event in its own transaction.
+func (s *PostgresStore) AppendBatch(ctx context.Context, events []*models.LedgerEvent, mode BatchMode) error {
+	failures := make(map[int]error)
+	for i, event := range events {
+		if err := s.opts.admit(event); err != nil {
+			failures[i] = err
+		}
+	}
+
+	if mode == BestEffort {
+		for i, event := range events {
+			if _, invalid := failures[i]; invalid {
+				continue
+			}
+			if err := s.Append(ctx, event); err != nil {
+				failures[i] = err
+			}
+		}
+		if len(failures) == 0 {
+			return nil
+		}
+		return &BatchError{Mode: mode, Failures: failures}
+	}
+
+	if len(failures) > 0 {
+		return &BatchError{Mode: mode, Failures: failures}
+	}
+
+	var stored []*models.LedgerEvent
+	failedAt := -1
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		var err error
+		stored, failedAt, err = s.appendAllTx(ctx, tx, events)
+		return err
+	})
+	if err != nil {
+		return atomicBatchError(err, failedAt)
+	}
+	adoptAll(events, stored)
+	return nil
+}
+
+// AppendBatchIdempotent appends the events atomically and records their IDs under batchKey in
+// the same transaction. A repeat submission under the same key returns the recorded IDs
+// without appending anything.
+func (s *PostgresStore) AppendBatchIdempotent(ctx context.Context, events []*models.LedgerEvent, batchKey string) ([]string, error) {
+	if batchKey == "" {
+		return nil, ErrEmptyBatchKey
+	}
+
+	failures := make(map[int]error)
+	for i, event := range events {
+		if err := s.opts.admit(event); err != nil {
+			failures[i] = err
+		}
+	}
+	if len(failures) > 0 {
+		return nil, &BatchError{Mode: Atomic, Failures: failures}
+	}
+
+	var ids []string
+	var stored []*models.LedgerEvent
+	failedAt := -1
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('batch:' || $1))`, batchKey); err != nil {
+			return fmt.Errorf("failed to lock batch key %s: %w", batchKey, err)
+		}
+
+		var recorded []byte
+		err := tx.QueryRow(ctx, `SELECT event_ids FROM ledger_batch_manifests WHERE batch_key = $1`, batchKey).Scan(&recorded)
+		if err == nil {
+			if err := json.Unmarshal(recorded, &ids); err != nil {
+				return fmt.Errorf("failed to unmarshal manifest of batch %s: %w", batchKey, err)
+			}
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("failed to read manifest of batch %s: %w", batchKey, err)
+		}
+
+		if stored, failedAt, err = s.appendAllTx(ctx, tx, events); err != nil {
+			return err
+		}
+		manifest, err := json.Marshal(eventIDs(events))
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest of batch %s: %w", batchKey, err)
+		}
+		_, err = tx.Exec(ctx, `INSERT INTO ledger_batch_manifests (batch_key, event_ids) VALUES ($1, $2)`, batchKey, manifest)
+		if err != nil {
+			return fmt.Errorf("failed to record manifest of batch %s: %w", batchKey, err)
+		}
+		ids = eventIDs(events)
+		return nil
+	})
+	if err != nil {
+		return nil, atomicBatchError(err, failedAt)
+	}
+	adoptAll(events, stored)
+	return ids, nil
+}
+
+// appendAllTx appends the events in order inside tx, returning them as stored or the index of
+// the first failure
+func (s *PostgresStore) appendAllTx(ctx context.Context, tx pgx.Tx, events []*models.LedgerEvent) ([]*models.LedgerEvent, int, error) {
+	stored := make([]*models.LedgerEvent, len(events))
+	for i, event := range events {
+		var err error
+		if stored[i], err = s.appendTx(ctx, tx, event); err != nil {
+			return nil, i, err
+		}
+	}
+	return stored, -1, nil
+}
+
+// adoptAll adopts each stored event into the caller's event at the same index; a batch
+// answered from its manifest has nothing stored and leaves events alone
+func adoptAll(events, stored []*models.LedgerEvent) {
+	for i, event := range stored {
+		adopt(events[i], event)
+	}
+}
+
+// atomicBatchError reports a failed atomic batch as a *BatchError naming the failing event,
+// or as a commit failure when no event failed
+func atomicBatchError(err error, failedAt int) error {
+	if failedAt < 0 {
+		return fmt.Errorf("failed to commit batch: %w", err)
+	}
+	return &BatchError{Mode: Atomic, Failures: map[int]error{failedAt: err}}
+}
+
+// Read returns the account's events with a version >= fromVersion
+func (s *PostgresStore) Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT payload FROM ledger_events WHERE account_id = $1 AND version >= $2 ORDER BY version`,
+		accountID, fromVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account %s: %w", accountID, err)
+	}
+	return scanEvents(rows)
+}
+
+// Stream delivers the account's events with a version >= fromVersion on a channel
+func (s *PostgresStore) Stream(ctx context.Context, accountID string, fromVersion int64) (<-chan *models.LedgerEvent, error) {
+	events, err := s.Read(ctx, accountID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return streamEvents(ctx, events), nil
+}
+
+// GetReferencing finds referencing events through the indexes on the referenceId and references payload fields
+func (s *PostgresStore) GetReferencing(ctx context.Context, targetID string, kinds ...models.RefKind) ([]*models.LedgerEvent, error) {
+	var (
+		rows pgx.Rows
+		err  error
+	)
+	if len(kinds) == 0 {
+		// A filter without a kind matches a reference of any kind by containment
+		ref, marshalErr := json.Marshal([]map[string]string{{"eventId": targetID}})
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal reference filter: %w", marshalErr)
+		}
+		rows, err = s.pool.Query(ctx,
+			`SELECT payload FROM ledger_events
+			 WHERE payload->>'referenceId' = $1 OR payload->'references' @> $2::jsonb
+			 ORDER BY account_id, version`,
+			targetID, ref)
+	} else {
+		filters := make([]string, len(kinds))
+		for i, kind := range kinds {
+			filter, marshalErr := json.Marshal([]models.EventRef{{Kind: kind, EventID: targetID}})
+			if marshalErr != nil {
+				return nil, fmt.Errorf("failed to marshal reference filter: %w", marshalErr)
+			}
+			filters[i] = string(filter)
+		}
+		rows, err = s.pool.Query(ctx,
+			`SELECT payload FROM ledger_events
+			 WHERE payload->'references' @> ANY($1::jsonb[])
+			 ORDER BY account_id, version`,
+			filters)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events referencing %s: %w", targetID, err)
+	}
+	return scanEvents(rows)
+}
+
+// scanEvents decodes a result set of event payloads and closes it
+func scanEvents(rows pgx.Rows) ([]*models.LedgerEvent, error) {
+	defer rows.Close()
+
+	events := []*models.LedgerEvent{}
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger event: %w", err)
+		}
+		event, err := models.LedgerEventFromJSON(payload)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read ledger events: %w", err)
+	}
+	return events, nil
+}
+
+// Checkpoint records a signed checkpoint at the account's current head, returning the existing
+// one if the head is already checkpointed
+func (s *PostgresStore) Checkpoint(ctx context.Context, accountID string) (*models.Checkpoint, error) {
+	if !s.opts.chaining() {
+		return nil, ErrChainingDisabled
+	}
+
+	var checkpoint *models.Checkpoint
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := lockAccountTx(ctx, tx, accountID); err != nil {
+			return err
+		}
+		var err error
+		checkpoint, err = s.checkpointTx(ctx, tx, accountID)
+		return err
+	})
+	return checkpoint, err
+}
+
+// LatestCheckpoint returns the account's most recent checkpoint, or nil if it has none
+func (s *PostgresStore) LatestCheckpoint(ctx context.Context, accountID string) (*models.Checkpoint, error) {
+	return latestCheckpointTx(ctx, s.pool, accountID)
+}
+
+// VerifyChain verifies the account's chain starting from its latest checkpoint and returns
+// how many events had to be checked
+func (s *PostgresStore) VerifyChain(ctx context.Context, accountID string, verifier models.Verifier) (int, error) {
+	checkpoint, err := s.LatestCheckpoint(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	var from int64 = 1
+	if checkpoint != nil {
+		from = checkpoint.Version + 1
+	}
+	events, err := s.Read(ctx, accountID, from)
+	if err != nil {
+		return 0, err
+	}
+	if err := verifyFromCheckpoint(checkpoint, events, verifier); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// UpdateSignature replaces the stored payload of the event at event's version after checking
+// that only its signature fields differ
+func (s *PostgresStore) UpdateSignature(ctx context.Context, event *models.LedgerEvent) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if err := lockAccountTx(ctx, tx, event.AccountID); err != nil {
+			return err
+		}
+		stored, err := eventAtTx(ctx, tx, event.AccountID, event.Version)
+		if errors.Is(err, pgx.ErrNoRows) {
+			stored, err = nil, nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkSignatureUpdate(stored, event); err != nil {
+			return err
+		}
+
+		payload, err := event.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to marshal ledger event: %w", err)
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE ledger_events SET payload = $3 WHERE account_id = $1 AND version = $2`,
+			event.AccountID, event.Version, payload)
+		if err != nil {
+			return fmt.Errorf("failed to update event %s: %w", event.ID, err)
+		}
+		return nil
+	})
+}
+
+// querier is the subset of pgx shared by pools and transactions that checkpoint reads need
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// lockAccountTx serializes writers of the same account with a transaction-scoped advisory lock
+func lockAccountTx(ctx context.Context, tx pgx.Tx, accountID string) error {
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, accountID); err != nil {
+		return fmt.Errorf("failed to lock account %s: %w", accountID, err)
+	}
+	return nil
+}
+
+// appendTx appends a clone of a validated event inside tx, holding the account's advisory lock,
+// and returns the clone as stored; the caller's event is untouched until the commit
+func (s *PostgresStore) appendTx(ctx context.Context, tx pgx.Tx, event *models.LedgerEvent) (*models.LedgerEvent, error) {
+	if err := lockAccountTx(ctx, tx, event.AccountID); err != nil {
+		return nil, err
+	}
+
+	var head int64
+	err := tx.QueryRow(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM ledger_events WHERE account_id = $1`,
+		event.AccountID).Scan(&head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read head of account %s: %w", event.AccountID, err)
+	}
+	if err := s.opts.checkVersion(event, head); err != nil {
+		return nil, err
+	}
+	if err := checkFreezeTx(ctx, tx, event); err != nil {
+		return nil, err
+	}
+	if err := checkClosedTx(ctx, tx, event); err != nil {
+		return nil, err
+	}
+	if err := checkContentTx(ctx, tx, event); err != nil {
+		return nil, err
+	}
+
+	event = event.Clone()
+
+	var last *models.LedgerEvent
+	if head > 0 && (s.opts.chaining() || s.opts.monotonic) {
+		if last, err = eventAtTx(ctx, tx, event.AccountID, head); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.opts.adjustTimestamp(event, last); err != nil {
+		return nil, err
+	}
+
+	var checkpoint *models.Checkpoint
+	if s.opts.chaining() {
+		if checkpoint, err = latestCheckpointTx(ctx, tx, event.AccountID); err != nil {
+			return nil, err
+		}
+		event.HashLength = s.opts.hashLength
+		event.PreviousHash = chainHead(last, checkpoint)
+	}
+
+	payload, err := event.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ledger event: %w", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO ledger_events (id, account_id, version, type, payload) VALUES ($1, $2, $3, $4, $5)`,
+		event.ID, event.AccountID, event.Version, string(event.Type), payload)
+	if err != nil {
+		return nil, translateError(err, event)
+	}
+
+	if !s.opts.chaining() {
+		return event, nil
+	}
+	var covered, since int64
+	if checkpoint != nil {
+		covered = checkpoint.Version
+	}
+	err = tx.QueryRow(ctx,
+		`SELECT COUNT(*) FROM ledger_events WHERE account_id = $1 AND version > $2`,
+		event.AccountID, covered).Scan(&since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count events of account %s: %w", event.AccountID, err)
+	}
+	if s.opts.checkpointDue(since) {
+		if _, err := s.checkpointTx(ctx, tx, event.AccountID); err != nil {
+			return nil, err
+		}
+	}
+	return event, nil
+}
+
+// checkpointTx records a checkpoint at the account head inside tx, which must hold the account lock
+func (s *PostgresStore) checkpointTx(ctx context.Context, tx pgx.Tx, accountID string) (*models.Checkpoint, error) {
+	prev, err := latestCheckpointTx(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	var covered int64
+	if prev != nil {
+		covered = prev.Version
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT payload FROM ledger_events WHERE account_id = $1 AND version > $2 ORDER BY version`,
+		accountID, covered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account %s: %w", accountID, err)
+	}
+	since, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(since) == 0 {
+		if prev == nil {
+			return nil, fmt.Errorf("account %s has no events to checkpoint", accountID)
+		}
+		return prev, nil
+	}
+
+	originals, err := reversedTypesTx(ctx, tx, accountID, since)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, err := nextCheckpoint(s.opts.checkpointKey, accountID, prev, since, originals)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO ledger_checkpoints (account_id, version, payload) VALUES ($1, $2, $3)`,
+		accountID, checkpoint.Version, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert checkpoint for account %s: %w", accountID, err)
+	}
+	return checkpoint, nil
+}
+
+// latestCheckpointTx returns the account's most recent checkpoint, or nil if it has none
+func latestCheckpointTx(ctx context.Context, q querier, accountID string) (*models.Checkpoint, error) {
+	var payload []byte
+	err := q.QueryRow(ctx,
+		`SELECT payload FROM ledger_checkpoints WHERE account_id = $1 ORDER BY version DESC LIMIT 1`,
+		accountID).Scan(&payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint of account %s: %w", accountID, err)
+	}
+
+	var checkpoint models.Checkpoint
+	if err := json.Unmarshal(payload, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// reversedTypesTx maps the events the reversals in since reverse to their types, reading the
+// originals recorded before since from the account's stream
+func reversedTypesTx(ctx context.Context, tx pgx.Tx, accountID string, since []*models.LedgerEvent) (map[string]models.EventType, error) {
+	types := models.ReversibleTypes(since)
+	var missing []string
+	for _, event := range since {
+		if event.IsReversal() {
+			if _, ok := types[event.ReversedEventID()]; !ok {
+				missing = append(missing, event.ReversedEventID())
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return types, nil
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT id, type FROM ledger_events WHERE account_id = $1 AND id = ANY($2)`,
+		accountID, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reversed events of account %s: %w", accountID, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, eventType string
+		if err := rows.Scan(&id, &eventType); err != nil {
+			return nil, fmt.Errorf("failed to scan reversed event: %w", err)
+		}
+		types[id] = models.EventType(eventType)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reversed events of account %s: %w", accountID, err)
+	}
+	return types, nil
+}
+
+// eventAtTx reads the account's event at version
+func eventAtTx(ctx context.Context, q querier, accountID string, version int64) (*models.LedgerEvent, error) {
+	var payload []byte
+	err := q.QueryRow(ctx,
+		`SELECT payload FROM ledger_events WHERE account_id = $1 AND version = $2`,
+		accountID, version).Scan(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account %s version %d: %w", accountID, version, err)
+	}
+	return models.LedgerEventFromJSON(payload)
+}
+
+// checkFreezeTx rejects withdrawals and holds when the account's latest control event is a
+// freeze, reading the original of a reversal to tell whether it withdraws
+func checkFreezeTx(ctx context.Context, tx pgx.Tx, event *models.LedgerEvent) error {
+	var originals map[string]models.EventType
+	if event.IsReversal() {
+		var err error
+		if originals, err = reversedTypesTx(ctx, tx, event.AccountID, []*models.LedgerEvent{event}); err != nil {
+			return err
+		}
+	}
+	if !event.Withdraws(originals) && !event.IsHold() {
+		return nil
+	}
+
+	var lastControl string
+	err := tx.QueryRow(ctx,
+		`SELECT type FROM ledger_events WHERE account_id = $1 AND type IN ($2, $3) ORDER BY version DESC LIMIT 1`,
+		event.AccountID, string(models.AccountFreeze), string(models.AccountUnfreeze)).Scan(&lastControl)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read freeze state of account %s: %w", event.AccountID, err)
+	}
+	if models.EventType(lastControl) == models.AccountFreeze {
+		return fmt.Errorf("%w: %s rejected on account %s", models.ErrAccountFrozen, event.Type, event.AccountID)
+	}
+	return nil
+}
+
+// checkClosedTx rejects balance-affecting events, reversals and holds once the account has been closed
+func checkClosedTx(ctx context.Context, tx pgx.Tx, event *models.LedgerEvent) error {
+	if !event.PostsBalance() && !event.IsHold() {
+		return nil
+	}
+
+	var closed bool
+	err := tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM ledger_events WHERE account_id = $1 AND type = $2)`,
+		event.AccountID, string(models.AccountClose)).Scan(&closed)
+	if err != nil {
+		return fmt.Errorf("failed to read closed state of account %s: %w", event.AccountID, err)
+	}
+	if closed {
+		return fmt.Errorf("%w: %s rejected on account %s", models.ErrAccountClosed, event.Type, event.AccountID)
+	}
+	return nil
+}
+
+// checkContentTx rejects an event whose ContentHash matches an event already on its account.
+// The hash covers the account, so the account lock held by tx serializes the check.
+func checkContentTx(ctx context.Context, tx pgx.Tx, event *models.LedgerEvent) error {
+	contentHash := event.ContentHash()
+	if contentHash == "" {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx,
+		`SELECT payload FROM ledger_events WHERE account_id = $1 AND payload->>'idempotencyKey' = $2`,
+		event.AccountID, event.IdempotencyKey)
+	if err != nil {
+		return fmt.Errorf("failed to read idempotency key %q: %w", event.IdempotencyKey, err)
+	}
+	existing, err := scanEvents(rows)
+	if err != nil {
+		return err
+	}
+	for _, stored := range existing {
+		if stored.ContentHash() == contentHash {
+			return fmt.Errorf("%w: %q already appended as %s", ErrDuplicateContent, event.IdempotencyKey, stored.ID)
+		}
+	}
+	return nil
+}
+
+// translateError maps constraint violations on ledger_events to the store's sentinel errors
+func translateError(err error, event *models.LedgerEvent) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if pgErr.ConstraintName == "ledger_events_pkey" {
+			return fmt.Errorf("%w: %s", ErrDuplicateEvent, event.ID)
+		}
+		return fmt.Errorf("%w: account %s version %d", ErrVersionConflict, event.AccountID, event.Version)
+	}
+	return fmt.Errorf("failed to insert ledger event: %w", err)
+}
@@ -0,0 +1,188 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// testDatabaseURLEnv names the Postgres database the Postgres store tests run against; they are
+// skipped when it is unset
+const testDatabaseURLEnv = "LEDGER_TEST_DATABASE_URL"
+
+// newTestPostgresStore returns a store over a ledger_events table in a fresh schema that is
+// dropped when the test ends
+func newTestPostgresStore(t *testing.T, opts ...Option) *PostgresStore {
+	t.Helper()
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("ledger_test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(admin.Close)
+	_, err = admin.Exec(ctx, `CREATE SCHEMA `+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := admin.Exec(context.Background(), `DROP SCHEMA `+schema+` CASCADE`)
+		assert.NoError(t, err)
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	require.NoError(t, err)
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	_, err = pool.Exec(ctx, `CREATE TABLE ledger_events (
+		id VARCHAR(64) PRIMARY KEY,
+		account_id VARCHAR(255) NOT NULL,
+		version BIGINT NOT NULL CHECK (version > 0),
+		type VARCHAR(32) NOT NULL,
+		payload JSONB NOT NULL,
+		created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+		CONSTRAINT ledger_events_account_version_key UNIQUE (account_id, version)
+	)`)
+	require.NoError(t, err)
+	return NewPostgresStore(pool, opts...)
+}
+
+func TestPostgresStoreAppendIfBalanceConcurrentDebits(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+
+	const attempts = 10
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		changed   int
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			debit := models.NewLedgerEvent(models.Debit, usd(30), "acc_1", fmt.Sprintf("corr_%d", i+1)).WithVersion(2)
+			err := s.AppendIfBalance(ctx, debit, usd(100))
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, ErrBalanceChanged):
+				changed++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, attempts-1, changed)
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+func TestPostgresStoreAppendIfBalanceMatchesAfterDebit(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(0.3), "acc_1", "corr_0")))
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Debit, usd(0.1), "acc_1", "corr_1").WithVersion(2)))
+	eur := models.NewMoney(500, "EUR", 2)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, eur, "acc_1", "corr_2").WithVersion(3)))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(0.2), "acc_1", "corr_3").WithVersion(4)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, debit, usd(0.3)), ErrBalanceChanged)
+	assert.NoError(t, s.AppendIfBalance(ctx, debit, usd(0.2)))
+}
+
+func TestPostgresStoreAppendIfBalanceAfterReversal(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+	debit := models.NewLedgerEvent(models.Debit, usd(30), "acc_1", "corr_1").WithVersion(2)
+	require.NoError(t, s.Append(ctx, debit))
+	require.NoError(t, s.Append(ctx, models.NewReversal(debit, "corr_2").WithVersion(3)))
+
+	next := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").WithVersion(4)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, next, usd(70)), ErrBalanceChanged)
+	assert.NoError(t, s.AppendIfBalance(ctx, next, usd(100)))
+}
+
+func TestPostgresStoreFreezeRejectsReversedCredits(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	credit := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")
+	debit := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_1").WithVersion(2)
+	require.NoError(t, s.Append(ctx, credit))
+	require.NoError(t, s.Append(ctx, debit))
+	require.NoError(t, s.Append(ctx, models.NewAccountFreeze("acc_1", "corr_2", "compliance hold").WithVersion(3)))
+
+	reversal := models.NewReversal(credit, "corr_3").WithVersion(4)
+	delete(reversal.Metadata, models.MetaReversedType)
+	assert.ErrorIs(t, s.Append(ctx, reversal), models.ErrAccountFrozen)
+	assert.NoError(t, s.Append(ctx, models.NewReversal(debit, "corr_4").WithVersion(4)))
+}
+
+func TestPostgresStoreFreezeRejectsNegativeAdjustments(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+	require.NoError(t, s.Append(ctx, models.NewAccountFreeze("acc_1", "corr_1", "compliance hold").WithVersion(2)))
+
+	writeDown := models.NewLedgerEvent(models.Adjustment, usd(-10), "acc_1", "corr_2").WithVersion(3)
+	assert.ErrorIs(t, s.Append(ctx, writeDown), models.ErrAccountFrozen)
+	assert.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Adjustment, usd(10), "acc_1", "corr_3").WithVersion(3)))
+}
+
+func TestPostgresStoreRejectsReversalsAfterClose(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t)
+	credit := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")
+	require.NoError(t, s.Append(ctx, credit))
+	closing, err := models.CloseAccount([]*models.LedgerEvent{credit}, "corr_close")
+	require.NoError(t, err)
+	for _, event := range closing {
+		require.NoError(t, s.Append(ctx, event))
+	}
+
+	reversal := models.NewReversal(credit, "corr_1").WithVersion(4)
+	assert.ErrorIs(t, s.Append(ctx, reversal), models.ErrAccountClosed)
+}
+
+func TestPostgresStoreFailedAppendLeavesEventUntouched(t *testing.T) {
+	ctx := context.Background()
+	s := newTestPostgresStore(t, MonotonicTimestamps(nil))
+	first := models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")
+	require.NoError(t, s.Append(ctx, first))
+
+	hold := models.NewLedgerEvent(models.Hold, usd(40), "acc_1", "corr_2").WithVersion(2)
+	hold.Timestamp = first.Timestamp.Add(-time.Minute)
+	hold.WithExpiry(time.Second)
+	before := hold.Clone()
+	assert.ErrorIs(t, s.Append(ctx, hold), ErrInvalidEvent)
+	assert.Equal(t, before, hold)
+
+	hold.WithExpiry(time.Hour)
+	require.NoError(t, s.Append(ctx, hold))
+	assert.True(t, hold.Timestamp.After(first.Timestamp))
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// Query selects events of an account stream and the order they are returned in
+type Query struct {
+	AccountID string
+	// FromVersion is the lowest version returned; zero reads the whole stream
+	FromVersion int64
+	// OrderBy orders the result by recorded or effective time; the default is models.Recorded
+	OrderBy models.EventOrder
+}
+
+// references reports whether event points at targetID; see EventStore.GetReferencing
+func references(event *models.LedgerEvent, targetID string, kinds []models.RefKind) bool {
+	if len(kinds) == 0 && event.ReferenceID != nil && *event.ReferenceID == targetID {
+		return true
+	}
+	for _, ref := range event.References {
+		if ref.EventID != targetID {
+			continue
+		}
+		if len(kinds) == 0 {
+			return true
+		}
+		for _, kind := range kinds {
+			if ref.Kind == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Run reads the query's events from s in the requested order
+func (q Query) Run(ctx context.Context, s EventStore) ([]*models.LedgerEvent, error) {
+	events, err := s.Read(ctx, q.AccountID, q.FromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return models.SortEvents(events, q.OrderBy), nil
+}
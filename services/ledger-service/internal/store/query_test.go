@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func TestMemoryStoreGetReferencing(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	original := models.NewLedgerEvent(models.Debit, usd(40), "acc_1", "corr_1")
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_0")))
+	require.NoError(t, s.Append(ctx, original.WithVersion(2)))
+
+	reversal := models.NewLedgerEvent(models.Reversal, usd(40), "acc_1", "corr_2").
+		WithVersion(3).AddReference(models.RefReverses, original.ID)
+	amendment := models.NewLedgerEvent(models.Adjustment, usd(5), "acc_2", "corr_3").
+		AddReference(models.RefAmends, original.ID)
+	legacy := models.NewLedgerEvent(models.Credit, usd(1), "acc_2", "corr_4").
+		WithVersion(2).WithReferenceID(original.ID)
+	unrelated := models.NewLedgerEvent(models.Credit, usd(1), "acc_2", "corr_5").
+		WithVersion(3).AddReference(models.RefReverses, "evt_other")
+	for _, event := range []*models.LedgerEvent{reversal, amendment, legacy, unrelated} {
+		require.NoError(t, s.Append(ctx, event))
+	}
+
+	all, err := s.GetReferencing(ctx, original.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{reversal.ID, amendment.ID, legacy.ID}, eventIDs(all))
+
+	reversals, err := s.GetReferencing(ctx, original.ID, models.RefReverses)
+	require.NoError(t, err)
+	assert.Equal(t, []string{reversal.ID}, eventIDs(reversals))
+
+	changes, err := s.GetReferencing(ctx, original.ID, models.RefReverses, models.RefAmends)
+	require.NoError(t, err)
+	assert.Equal(t, []string{reversal.ID, amendment.ID}, eventIDs(changes))
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// SignatureUpdater is implemented by stores that can replace the signature of a stored event.
+// This is the one exception to append-only streams: the signature, its key and algorithm, and
+// the prior signatures may change, nothing covered by the event's hash may.
+type SignatureUpdater interface {
+	// UpdateSignature replaces the signature fields of the stored event with event's, returning
+	// ErrEventNotFound if the account has no such event at that version and ErrContentChanged
+	// if event differs from it in anything else
+	UpdateSignature(ctx context.Context, event *models.LedgerEvent) error
+}
+
+// ResignableStore is an EventStore whose signatures can be rewritten
+type ResignableStore interface {
+	EventStore
+	SignatureUpdater
+}
+
+// EventFilter selects the events BulkResign processes
+type EventFilter struct {
+	// AccountIDs are the account streams to process, in order
+	AccountIDs []string
+	// FromVersion is the lowest version processed in every stream; zero starts at the beginning
+	FromVersion int64
+	// Resume maps accounts to the last version a previous run processed, as reported in its
+	// ResignReport.Checkpoints; processing continues after it
+	Resume map[string]int64
+	// Match, if set, selects events within the streams; unmatched events are passed over
+	Match func(*models.LedgerEvent) bool
+}
+
+// ResignFailure is an event BulkResign could not re-sign or write back
+type ResignFailure struct {
+	EventID   string `json:"eventId"`
+	AccountID string `json:"accountId"`
+	Version   int64  `json:"version"`
+	Reason    string `json:"reason"`
+}
+
+// ResignReport summarizes a BulkResign run
+type ResignReport struct {
+	// Matched counts the events the filter selected
+	Matched int `json:"matched"`
+	// Resigned counts the events re-signed and written back
+	Resigned int `json:"resigned"`
+	// Skipped counts matched events that were unsigned or already signed with the new key
+	Skipped  int             `json:"skipped"`
+	Failures []ResignFailure `json:"failures"`
+	// Checkpoints maps each account to the last version processed; pass it as EventFilter.Resume
+	// to continue an interrupted run
+	Checkpoints map[string]int64 `json:"checkpoints"`
+}
+
+// BulkResign re-signs the filtered events with signer over their current canonical form and
+// writes them back, keeping each event's previous signature among its prior signatures. Events
+// already signed with signer's key are skipped, so the run is idempotent and can be repeated or
+// resumed incrementally; a run stopped by ctx returns the partial report with its checkpoints.
+// Events that fail to re-sign or write back are reported and do not stop the run.
+func BulkResign(ctx context.Context, store ResignableStore, signer models.Signer, filter EventFilter) (ResignReport, error) {
+	report := ResignReport{Failures: []ResignFailure{}, Checkpoints: make(map[string]int64)}
+	for _, accountID := range filter.AccountIDs {
+		from := filter.FromVersion
+		if last, ok := filter.Resume[accountID]; ok && last >= from {
+			from = last + 1
+		}
+		events, err := store.Read(ctx, accountID, from)
+		if err != nil {
+			return report, fmt.Errorf("failed to read account %s: %w", accountID, err)
+		}
+
+		for _, event := range events {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if filter.Match == nil || filter.Match(event) {
+				report.Matched++
+				if err := resignOne(ctx, store, signer, event, &report); err != nil {
+					report.Failures = append(report.Failures, ResignFailure{
+						EventID:   event.ID,
+						AccountID: accountID,
+						Version:   event.Version,
+						Reason:    err.Error(),
+					})
+				}
+			}
+			report.Checkpoints[accountID] = event.Version
+		}
+	}
+	return report, nil
+}
+
+// resignOne re-signs a single event and writes it back, counting it in report
+func resignOne(ctx context.Context, store SignatureUpdater, signer models.Signer, event *models.LedgerEvent, report *ResignReport) error {
+	if event.Signature == "" || event.KeyID == signer.KeyID() {
+		report.Skipped++
+		return nil
+	}
+	if err := event.Resign(signer); err != nil {
+		return err
+	}
+	if err := store.UpdateSignature(ctx, event); err != nil {
+		return err
+	}
+	report.Resigned++
+	return nil
+}
+
+// checkSignatureUpdate verifies that updated is stored under the same ID and differs from stored
+// only in its signature fields
+func checkSignatureUpdate(stored, updated *models.LedgerEvent) error {
+	if stored == nil || stored.ID != updated.ID {
+		return fmt.Errorf("%w: %s at %s version %d", ErrEventNotFound, updated.ID, updated.AccountID, updated.Version)
+	}
+	if stored.ComputeHash() != updated.ComputeHash() {
+		return fmt.Errorf("%w: %s", ErrContentChanged, updated.ID)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// interruptingStore cancels the run after a number of signature updates, simulating a
+// BulkResign interrupted part way through
+type interruptingStore struct {
+	*MemoryStore
+	cancel  context.CancelFunc
+	after   int
+	updated int
+}
+
+func (s *interruptingStore) UpdateSignature(ctx context.Context, event *models.LedgerEvent) error {
+	if err := s.MemoryStore.UpdateSignature(ctx, event); err != nil {
+		return err
+	}
+	s.updated++
+	if s.updated == s.after {
+		s.cancel()
+	}
+	return nil
+}
+
+func TestBulkResignResumesWithoutResigning(t *testing.T) {
+	ctx := context.Background()
+	oldKey := models.NewHMACKey("ledger-1", []byte("old secret"))
+	newKey := models.NewHMACKey("ledger-2", []byte("new secret"))
+
+	s := NewMemoryStore()
+	accounts := []string{"acc_1", "acc_2"}
+	for _, accountID := range accounts {
+		for v := int64(1); v <= 3; v++ {
+			event := models.NewLedgerEvent(models.Credit, usd(10), accountID, "corr_1").WithVersion(v)
+			require.NoError(t, event.SignWith(oldKey))
+			require.NoError(t, s.Append(ctx, event))
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	interrupted := &interruptingStore{MemoryStore: s, cancel: cancel, after: 4}
+	report, err := BulkResign(runCtx, interrupted, newKey, EventFilter{AccountIDs: accounts})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 4, report.Resigned)
+	assert.Equal(t, map[string]int64{"acc_1": 3, "acc_2": 1}, report.Checkpoints)
+
+	first, err := s.Read(ctx, "acc_2", 1)
+	require.NoError(t, err)
+	resignedAt := first[0].Signature
+
+	resumed, err := BulkResign(ctx, interrupted, newKey, EventFilter{AccountIDs: accounts, Resume: report.Checkpoints})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resumed.Matched, "events processed before the interruption are not revisited")
+	assert.Equal(t, 2, resumed.Resigned)
+	assert.Empty(t, resumed.Failures)
+	assert.Equal(t, map[string]int64{"acc_2": 3}, resumed.Checkpoints)
+
+	for _, accountID := range accounts {
+		events, err := s.Read(ctx, accountID, 1)
+		require.NoError(t, err)
+		for _, event := range events {
+			assert.Equal(t, "ledger-2", event.KeyID)
+			assert.Len(t, event.PriorSignatures, 1, "event %s re-signed once", event.ID)
+			assert.NoError(t, event.VerifyWith(newKey))
+			assert.NoError(t, event.VerifyWith(oldKey))
+		}
+	}
+	events, err := s.Read(ctx, "acc_2", 1)
+	require.NoError(t, err)
+	assert.Equal(t, resignedAt, events[0].Signature)
+
+	again, err := BulkResign(ctx, s, newKey, EventFilter{AccountIDs: accounts})
+	require.NoError(t, err)
+	assert.Equal(t, 6, again.Skipped, "a full rerun finds everything already re-signed")
+	assert.Zero(t, again.Resigned)
+}
+
+func TestUpdateSignatureRejectsContentChanges(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	event := models.NewLedgerEvent(models.Credit, usd(10), "acc_1", "corr_1").WithVersion(1)
+	require.NoError(t, event.SignWith(models.NewHMACKey("ledger-1", []byte("secret"))))
+	require.NoError(t, s.Append(ctx, event))
+
+	tampered := *event
+	tampered.Amount = usd(20)
+	assert.ErrorIs(t, s.UpdateSignature(ctx, &tampered), ErrContentChanged)
+
+	missing := *event
+	missing.Version = 2
+	assert.ErrorIs(t, s.UpdateSignature(ctx, &missing), ErrEventNotFound)
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+// ErrNoRoute is returned when an event matches no backend and the router has no default
+var ErrNoRoute = errors.New("no route for event")
+
+// StoreKey names a backend of a RoutingStore
+type StoreKey string
+
+// Router picks the backend an event is appended to, typically from its metadata
+type Router func(*models.LedgerEvent) StoreKey
+
+// RoutingStore splits events across several backing stores, e.g. keeping high-risk events in a
+// separate store, while reads fan out to every backend and merge the account's stream back into
+// version order. An account's events can live in several backends, so the backends must be
+// created with AllowVersionGaps; the router enforces contiguous versions and freezes across
+// them by serializing its appends, which makes it the single writer for its backends.
+type RoutingStore struct {
+	mu       sync.Mutex
+	route    Router
+	backends map[StoreKey]EventStore
+	fallback StoreKey
+}
+
+// RoutingOption configures a RoutingStore
+type RoutingOption func(*RoutingStore)
+
+// WithDefaultRoute sends events whose route names no backend to the given backend instead of
+// rejecting them with ErrNoRoute
+func WithDefaultRoute(key StoreKey) RoutingOption {
+	return func(r *RoutingStore) {
+		r.fallback = key
+	}
+}
+
+// NewRoutingStore creates a store dispatching appends to backends by route
+func NewRoutingStore(route Router, backends map[StoreKey]EventStore, opts ...RoutingOption) *RoutingStore {
+	r := &RoutingStore{route: route, backends: backends}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Append checks the event against the account's merged stream and appends it to its backend
+func (r *RoutingStore) Append(ctx context.Context, event *models.LedgerEvent) error {
+	backend, err := r.backendFor(event)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream, err := r.Read(ctx, event.AccountID, 1)
+	if err != nil {
+		return err
+	}
+	return r.appendLocked(ctx, backend, stream, event)
+}
+
+// AppendIfBalance appends the event like Append, but only if the posted balance of the
+// account's merged stream equals expected
+func (r *RoutingStore) AppendIfBalance(ctx context.Context, event *models.LedgerEvent, expected models.Money) error {
+	backend, err := r.backendFor(event)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream, err := r.Read(ctx, event.AccountID, 1)
+	if err != nil {
+		return err
+	}
+	current, err := postedBalance(stream, expected.Currency)
+	if err != nil {
+		return err
+	}
+	if err := checkExpectedBalance(event.AccountID, current, expected); err != nil {
+		return err
+	}
+	return r.appendLocked(ctx, backend, stream, event)
+}
+
+// appendLocked checks the event against the account's merged stream and appends it to backend;
+// the caller must hold r.mu
+func (r *RoutingStore) appendLocked(ctx context.Context, backend EventStore, stream []*models.LedgerEvent, event *models.LedgerEvent) error {
+	if expected := headVersion(stream) + 1; event.Version != expected {
+		return fmt.Errorf("%w: account %s expects version %d, got %d",
+			ErrVersionConflict, event.AccountID, expected, event.Version)
+	}
+	if err := models.CheckFreeze(stream, event); err != nil {
+		return err
+	}
+	if err := models.CheckClosed(stream, event); err != nil {
+		return err
+	}
+	return backend.Append(ctx, event)
+}
+
+// Read returns the account's events with a version >= fromVersion from every backend, in version order
+func (r *RoutingStore) Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error) {
+	merged := []*models.LedgerEvent{}
+	for key, backend := range r.backends {
+		events, err := backend.Read(ctx, accountID, fromVersion)
+		if err != nil {
+			return nil, fmt.Errorf("backend %s: %w", key, err)
+		}
+		merged = append(merged, events...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Version != merged[j].Version {
+			return merged[i].Version < merged[j].Version
+		}
+		return merged[i].ID < merged[j].ID
+	})
+	return merged, nil
+}
+
+// Stream delivers the account's events with a version >= fromVersion on a channel
+func (r *RoutingStore) Stream(ctx context.Context, accountID string, fromVersion int64) (<-chan *models.LedgerEvent, error) {
+	events, err := r.Read(ctx, accountID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	return streamEvents(ctx, events), nil
+}
+
+// GetReferencing queries every backend and merges the results by account and version
+func (r *RoutingStore) GetReferencing(ctx context.Context, targetID string, kinds ...models.RefKind) ([]*models.LedgerEvent, error) {
+	merged := []*models.LedgerEvent{}
+	for key, backend := range r.backends {
+		events, err := backend.GetReferencing(ctx, targetID, kinds...)
+		if err != nil {
+			return nil, fmt.Errorf("backend %s: %w", key, err)
+		}
+		merged = append(merged, events...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].AccountID != merged[j].AccountID {
+			return merged[i].AccountID < merged[j].AccountID
+		}
+		return merged[i].Version < merged[j].Version
+	})
+	return merged, nil
+}
+
+// backendFor resolves the backend an event routes to
+func (r *RoutingStore) backendFor(event *models.LedgerEvent) (EventStore, error) {
+	key := r.route(event)
+	if backend, ok := r.backends[key]; ok {
+		return backend, nil
+	}
+	if backend, ok := r.backends[r.fallback]; ok && r.fallback != "" {
+		return backend, nil
+	}
+	return nil, fmt.Errorf("%w: event %s routes to %q", ErrNoRoute, event.ID, key)
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+func riskRouter(event *models.LedgerEvent) StoreKey {
+	if event.Metadata["risk"] == "high" {
+		return "high-risk"
+	}
+	return "standard"
+}
+
+func TestRoutingStoreRoutesAndMerges(t *testing.T) {
+	ctx := context.Background()
+	standard := NewMemoryStore(AllowVersionGaps())
+	highRisk := NewMemoryStore(AllowVersionGaps())
+	s := NewRoutingStore(riskRouter, map[StoreKey]EventStore{"standard": standard, "high-risk": highRisk})
+
+	events := []*models.LedgerEvent{
+		models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1"),
+		models.NewLedgerEvent(models.Debit, usd(60), "acc_1", "corr_2").WithVersion(2).WithMetadata("risk", "high"),
+		models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").WithVersion(3),
+	}
+	for _, event := range events {
+		require.NoError(t, s.Append(ctx, event))
+	}
+
+	standardEvents, err := standard.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{events[0].ID, events[2].ID}, eventIDs(standardEvents))
+	highRiskEvents, err := highRisk.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{events[1].ID}, eventIDs(highRiskEvents))
+
+	merged, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, eventIDs(events), eventIDs(merged))
+
+	tail, err := s.Read(ctx, "acc_1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, eventIDs(events[1:]), eventIDs(tail))
+
+	err = s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_4").WithVersion(3))
+	assert.ErrorIs(t, err, ErrVersionConflict)
+}
+
+func TestRoutingStoreUnmatchedRoute(t *testing.T) {
+	ctx := context.Background()
+	route := func(*models.LedgerEvent) StoreKey { return "archive" }
+	backends := map[StoreKey]EventStore{"standard": NewMemoryStore(AllowVersionGaps())}
+
+	err := NewRoutingStore(route, backends).Append(ctx, models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_1"))
+	assert.ErrorIs(t, err, ErrNoRoute)
+
+	s := NewRoutingStore(route, backends, WithDefaultRoute("standard"))
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(5), "acc_1", "corr_1")))
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestRoutingStoreAppendIfBalance(t *testing.T) {
+	ctx := context.Background()
+	s := NewRoutingStore(riskRouter, map[StoreKey]EventStore{
+		"standard":  NewMemoryStore(AllowVersionGaps()),
+		"high-risk": NewMemoryStore(AllowVersionGaps()),
+	})
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Credit, usd(100), "acc_1", "corr_1")))
+	require.NoError(t, s.Append(ctx, models.NewLedgerEvent(models.Debit, usd(60), "acc_1", "corr_2").
+		WithVersion(2).WithMetadata("risk", "high")))
+
+	debit := models.NewLedgerEvent(models.Debit, usd(10), "acc_1", "corr_3").WithVersion(3)
+	assert.ErrorIs(t, s.AppendIfBalance(ctx, debit, usd(100)), ErrBalanceChanged)
+	require.NoError(t, s.AppendIfBalance(ctx, debit, usd(40)))
+
+	events, err := s.Read(ctx, "acc_1", 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 3)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"fintech-platform/ledger-service/internal/models"
+)
+
+var (
+	// ErrInvalidEvent is returned when an event fails validation on append
+	ErrInvalidEvent = errors.New("invalid event")
+	// ErrVersionConflict is returned when an event's version is not the next version of its account stream
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrDuplicateEvent is returned when an event with the same ID has already been appended
+	ErrDuplicateEvent = errors.New("duplicate event")
+	// ErrDuplicateContent is returned when an event with the same ContentHash has already been appended,
+	// meaning a retry of a creation attempt that already succeeded
+	ErrDuplicateContent = errors.New("duplicate idempotency key")
+	// ErrBalanceChanged is returned by a conditional append when the account balance differs from the expected one
+	ErrBalanceChanged = errors.New("balance changed")
+	// ErrUnsignedEvent is returned by a store requiring signatures when an event has none
+	ErrUnsignedEvent = errors.New("unsigned event")
+	// ErrChainingDisabled is returned by checkpoint operations on a store created without WithChaining
+	ErrChainingDisabled = errors.New("chaining is not enabled")
+	// ErrEventNotFound is returned when an event to update is not in its account stream
+	ErrEventNotFound = errors.New("event not found")
+	// ErrContentChanged is returned when a signature update would also change an event's content
+	ErrContentChanged = errors.New("event content changed")
+)
+
+// EventStore persists ledger events as append-only, per-account streams
+type EventStore interface {
+	// Append validates the event and persists it at the next version of its account stream
+	Append(ctx context.Context, event *models.LedgerEvent) error
+	// AppendIfBalance appends the event like Append, but only if the account's posted balance in
+	// expected's currency equals expected, returning ErrBalanceChanged otherwise. The check and
+	// the append are atomic with respect to other writers of the account.
+	AppendIfBalance(ctx context.Context, event *models.LedgerEvent, expected models.Money) error
+	// Read returns the account's events with a version >= fromVersion, in version order
+	Read(ctx context.Context, accountID string, fromVersion int64) ([]*models.LedgerEvent, error)
+	// Stream delivers the events Read would return on a channel, in version order. The channel
+	// is closed after the last event, or early once ctx is done; read failures are returned
+	// before any event is delivered.
+	Stream(ctx context.Context, accountID string, fromVersion int64) (<-chan *models.LedgerEvent, error)
+	// GetReferencing returns the events across all accounts that reference targetID, ordered by
+	// account and version. With no kinds, both ReferenceID and typed References match; with
+	// kinds, only typed references of those kinds do.
+	GetReferencing(ctx context.Context, targetID string, kinds ...models.RefKind) ([]*models.LedgerEvent, error)
+}
+
+// streamEvents delivers events on an unbuffered channel, closing it after the last event or
+// once ctx is done
+func streamEvents(ctx context.Context, events []*models.LedgerEvent) <-chan *models.LedgerEvent {
+	stream := make(chan *models.LedgerEvent)
+	go func() {
+		defer close(stream)
+		for _, event := range events {
+			select {
+			case stream <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return stream
+}